@@ -0,0 +1,80 @@
+// Package testchain 测试用的模拟链：simulated后端上带两个有余额的账户，供各包的合约测试共用
+package testchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ChainID simulated后端的链ID
+const ChainID = 1337
+
+// Chain alice和bob各有100 ETH的模拟链
+type Chain struct {
+	Backend *simulated.Backend
+	Client  simulated.Client
+	Alice   *bind.TransactOpts
+	Bob     *bind.TransactOpts
+}
+
+// New 创建模拟链，测试结束时自动关闭
+func New(t testing.TB) *Chain {
+	t.Helper()
+	aliceKey, bobKey := NewKey(t), NewKey(t)
+	balance := new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(aliceKey.PublicKey): {Balance: balance},
+		crypto.PubkeyToAddress(bobKey.PublicKey):   {Balance: balance},
+	})
+	t.Cleanup(func() { backend.Close() })
+	return &Chain{
+		Backend: backend,
+		Client:  backend.Client(),
+		Alice:   Transactor(t, aliceKey),
+		Bob:     Transactor(t, bobKey),
+	}
+}
+
+// NewKey 生成随机私钥
+func NewKey(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// Transactor 模拟链上的签名交易选项
+func Transactor(t testing.TB, key *ecdsa.PrivateKey) *bind.TransactOpts {
+	t.Helper()
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(ChainID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth
+}
+
+// Mined 出块并确认交易执行成功，err为发送交易时的错误
+func (c *Chain) Mined(t testing.TB, tx *types.Transaction, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Backend.Commit()
+	receipt, err := c.Client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("交易 %s 执行失败", tx.Hash().Hex())
+	}
+}
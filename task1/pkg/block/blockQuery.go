@@ -2,6 +2,8 @@ package main
 
 import (
 	"DApp/pkg/config"
+	"DApp/pkg/gas"
+	"DApp/pkg/provider"
 	"context"
 	"crypto/ecdsa"
 	"fmt"
@@ -12,21 +14,19 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 func main() {
 	// 替换为你的Infura Sepolia API URL  注意InitConfig配置时 工作目录设置一致，否则有的启动类etc/config.yaml这个路径会加载不到。
 	config.InitConfig("etc/config.yaml")
-	apIkey := config.GetConfig().Server.APIkey
-	infuraURL := "https://sepolia.infura.io/v3/" + apIkey
 
-	// 连接到Sepolia测试网络
-	client, err := ethclient.Dial(infuraURL)
+	// 连接到Sepolia测试网络，所有RPC请求经过限流器
+	client, limiter, err := provider.DialSepolia(context.Background())
 	if err != nil {
 		log.Fatalf("无法连接到以太坊客户端: %v", err)
 	}
 	defer client.Close()
+	defer func() { fmt.Println(limiter.Stats()) }()
 
 	fmt.Println("成功连接到Sepolia测试网络")
 
@@ -94,15 +94,18 @@ func main() {
 	//	Data:     nil, // 普通转账不需要数据
 	//}
 
-	// 使用自定义的CallMsg结构体
-	gasLimit, err := client.EstimateGas(context.Background(), ethereum.CallMsg{
-		From:  fromAddress,
-		To:    &toAddress,
-		Value: amount,
+	// 发送前先模拟执行并估算Gas，Gas上限按配置的安全系数放大
+	report, err := gas.Simulate(context.Background(), client, ethereum.CallMsg{
+		From:     fromAddress,
+		To:       &toAddress,
+		Value:    amount,
+		GasPrice: gasPrice,
 	})
 	if err != nil {
-		log.Fatalf("无法估计Gas限制: %v", err)
+		log.Fatalf("模拟交易失败: %v", err)
 	}
+	fmt.Println(report)
+	gasLimit := report.GasLimit
 
 	// 获取当前链ID (Sepolia的链ID是11155111)
 	chainID, err := client.NetworkID(context.Background())
@@ -15,6 +15,9 @@ var (
 	once   sync.Once
 )
 
+// DefaultGasMultiplier 配置文件未设置safety_multiplier时的Gas安全系数，gas.DefaultMultiplier同此值
+const DefaultGasMultiplier = 1.2
+
 // Configuration 结构体对应配置文件内容
 type Configuration struct {
 	Server struct {
@@ -40,6 +43,23 @@ type Configuration struct {
 		JwtSecret   string `yaml:"jwt_secret"`
 		TokenExpiry int    `yaml:"token_expiry"`
 	} `yaml:"auth"`
+
+	Gas struct {
+		SafetyMultiplier float64 `yaml:"safety_multiplier"` // Gas上限 = 预估Gas × 安全系数
+	} `yaml:"gas"`
+
+	// RateLimit 按服务商名称（如 infura）配置的客户端限流与配额
+	RateLimit map[string]RateLimitConfig `yaml:"rate_limit"`
+}
+
+// RateLimitConfig 单个RPC服务商的限流配置
+type RateLimitConfig struct {
+	RPS        float64        `yaml:"rps"`         // 每秒请求数
+	Burst      int            `yaml:"burst"`       // 突发请求数
+	DailyQuota int64          `yaml:"daily_quota"` // 每日额度（按方法权重累计），0表示不限制
+	MethodCost map[string]int `yaml:"method_cost"` // 方法权重，未配置的方法默认为1
+	MaxRetries int            `yaml:"max_retries"` // 收到429时的最大重试次数
+	UsageFile  string         `yaml:"usage_file"`  // 当日用量持久化文件，跨进程累计额度
 }
 
 // InitConfig 初始化配置（单例模式）
@@ -72,6 +92,9 @@ func loadConfig(path string) error {
 	if config.Server.Port == "" {
 		config.Server.Port = "8080"
 	}
+	if config.Gas.SafetyMultiplier <= 0 {
+		config.Gas.SafetyMultiplier = DefaultGasMultiplier
+	}
 
 	return nil
 }
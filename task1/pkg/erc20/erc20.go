@@ -0,0 +1,171 @@
+package main
+
+import (
+	"DApp/pkg/config"
+	"DApp/pkg/gas"
+	"DApp/pkg/provider"
+	"DApp/pkg/signer"
+	"DApp/pkg/token"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const usage = `用法: go run ./pkg/erc20 -token <合约地址> <命令> [参数]
+
+命令:
+  info                               查看代币信息
+  balance [地址]                     查询余额，默认为配置中的账户
+  allowance <owner> <spender>        查询授权额度
+  transfer <to> <金额>               转账
+  approve <spender> <金额|max>       授权
+  transferFrom <from> <to> <金额>    使用授权额度转账
+
+金额按代币精度填写，如 1.5
+`
+
+func main() {
+	tokenAddr := flag.String("token", "", "ERC-20合约地址")
+	wait := flag.Bool("wait", true, "发送后等待交易确认")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 || !common.IsHexAddress(*tokenAddr) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config.InitConfig("etc/config.yaml")
+	ctx := context.Background()
+
+	client, limiter, err := provider.DialSepolia(ctx)
+	if err != nil {
+		log.Fatalf("无法连接到以太坊客户端: %v", err)
+	}
+	defer client.Close()
+	defer func() { fmt.Println(limiter.Stats()) }()
+
+	t, err := token.Load(ctx, common.HexToAddress(*tokenAddr), client)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	s, err := signer.FromConfig()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	var (
+		tx     *types.Transaction
+		report *gas.Report
+	)
+	switch cmd, params := args[0], args[1:]; cmd {
+	case "info":
+		supply, err := t.TotalSupply(ctx)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("合约地址: %s\n", t.Address.Hex())
+		fmt.Printf("名称: %s\n", t.Name)
+		fmt.Printf("符号: %s\n", t.Symbol)
+		fmt.Printf("精度: %d\n", t.Decimals)
+		fmt.Printf("总供应量: %s\n", t.FormatAmount(supply))
+	case "balance":
+		owner := s.Address
+		if len(params) > 0 {
+			owner = mustAddress(params[0])
+		}
+		balance, err := t.BalanceOf(ctx, owner)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("%s 余额: %s\n", owner.Hex(), t.FormatAmount(balance))
+	case "allowance":
+		needArgs(params, 2)
+		allowance, err := t.Allowance(ctx, mustAddress(params[0]), mustAddress(params[1]))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("授权额度: %s\n", t.FormatAmount(allowance))
+	case "transfer":
+		needArgs(params, 2)
+		tx, report, err = t.Transfer(ctx, transactOpts(ctx, client, s), mustAddress(params[0]), mustAmount(t, params[1]))
+	case "approve":
+		needArgs(params, 2)
+		tx, report, err = t.Approve(ctx, transactOpts(ctx, client, s), mustAddress(params[0]), mustAmount(t, params[1]))
+	case "transferFrom":
+		needArgs(params, 3)
+		tx, report, err = t.TransferFrom(ctx, transactOpts(ctx, client, s), mustAddress(params[0]), mustAddress(params[1]), mustAmount(t, params[2]))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if tx == nil {
+		return
+	}
+	fmt.Println(report)
+
+	fmt.Printf("交易已发送，哈希: %s\n", tx.Hash().Hex())
+	fmt.Printf("可以在Etherscan查看: https://sepolia.etherscan.io/tx/%s\n", tx.Hash().Hex())
+	if *wait {
+		fmt.Println("等待交易确认...")
+		receipt, err := bind.WaitMined(ctx, client, tx)
+		if err != nil {
+			log.Fatalf("等待交易确认失败: %v", err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			log.Fatalf("交易执行失败，区块 %d", receipt.BlockNumber.Uint64())
+		}
+		fmt.Printf("交易已确认，区块 %d，Gas使用 %d\n", receipt.BlockNumber.Uint64(), receipt.GasUsed)
+	}
+}
+
+func transactOpts(ctx context.Context, client *ethclient.Client, s *signer.Signer) *bind.TransactOpts {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		log.Fatalf("无法获取链ID: %v", err)
+	}
+	auth, err := s.TransactOpts(ctx, chainID)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return auth
+}
+
+func needArgs(params []string, n int) {
+	if len(params) != n {
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func mustAddress(s string) common.Address {
+	if !common.IsHexAddress(s) {
+		log.Fatalf("无效地址: %s", s)
+	}
+	return common.HexToAddress(s)
+}
+
+// mustAmount 按代币精度解析金额，max表示uint256最大值（无限授权）
+func mustAmount(t *token.Token, s string) *big.Int {
+	if strings.EqualFold(s, "max") {
+		return new(big.Int).Set(math.MaxBig256)
+	}
+	amount, err := t.ParseAmount(s)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return amount
+}
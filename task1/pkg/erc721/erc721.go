@@ -0,0 +1,174 @@
+package main
+
+import (
+	"DApp/pkg/config"
+	"DApp/pkg/nft"
+	"DApp/pkg/provider"
+	"DApp/pkg/signer"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const usage = `用法: go run ./pkg/erc721 -nft <合约地址> <命令> [参数]
+
+命令:
+  info                       查看合约信息
+  owner <tokenId>            查询持有者
+  uri <tokenId>              查询tokenURI
+  metadata <tokenId>         读取并解析元数据
+  balance [地址]             查询持有数量，默认为配置中的账户
+  tokens [地址]              从Transfer日志枚举持有的token（配合 -from-block 缩小扫描范围）
+  transfer <to> <tokenId>    safeTransferFrom转出token
+`
+
+func main() {
+	nftAddr := flag.String("nft", "", "ERC-721合约地址")
+	fromBlock := flag.Uint64("from-block", 0, "tokens命令扫描日志的起始区块，建议填合约部署区块")
+	wait := flag.Bool("wait", true, "发送后等待交易确认")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 || !common.IsHexAddress(*nftAddr) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config.InitConfig("etc/config.yaml")
+	ctx := context.Background()
+
+	client, limiter, err := provider.DialSepolia(ctx)
+	if err != nil {
+		log.Fatalf("无法连接到以太坊客户端: %v", err)
+	}
+	defer client.Close()
+	defer func() { fmt.Println(limiter.Stats()) }()
+
+	c, err := nft.Load(ctx, common.HexToAddress(*nftAddr), client)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	s, err := signer.FromConfig()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	switch cmd, params := args[0], args[1:]; cmd {
+	case "info":
+		fmt.Printf("合约地址: %s\n", c.Address.Hex())
+		fmt.Printf("名称: %s\n", c.Name)
+		fmt.Printf("符号: %s\n", c.Symbol)
+	case "owner":
+		needArgs(params, 1)
+		owner, err := c.OwnerOf(ctx, mustTokenID(params[0]))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("持有者: %s\n", owner.Hex())
+	case "uri":
+		needArgs(params, 1)
+		uri, err := c.TokenURI(ctx, mustTokenID(params[0]))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("tokenURI: %s\n", uri)
+	case "metadata":
+		needArgs(params, 1)
+		m, err := c.Metadata(ctx, mustTokenID(params[0]))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("名称: %s\n", m.Name)
+		fmt.Printf("描述: %s\n", m.Description)
+		fmt.Printf("图片: %s\n", nft.ResolveURI(m.Image))
+		for _, a := range m.Attributes {
+			fmt.Printf("  %s: %v\n", a.TraitType, a.Value)
+		}
+		raw, _ := json.MarshalIndent(json.RawMessage(m.Raw), "", "  ")
+		fmt.Printf("原始JSON:\n%s\n", raw)
+	case "balance":
+		owner := ownerArg(params, s.Address)
+		balance, err := c.BalanceOf(ctx, owner)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("%s 持有数量: %s\n", owner.Hex(), balance)
+	case "tokens":
+		owner := ownerArg(params, s.Address)
+		tokens, err := c.TokensOf(ctx, owner, *fromBlock)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("%s 持有 %d 个token:\n", owner.Hex(), len(tokens))
+		for _, id := range tokens {
+			fmt.Printf("  %s\n", id)
+		}
+	case "transfer":
+		needArgs(params, 2)
+		if !common.IsHexAddress(params[0]) {
+			log.Fatalf("无效地址: %s", params[0])
+		}
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			log.Fatalf("无法获取链ID: %v", err)
+		}
+		auth, err := s.TransactOpts(ctx, chainID)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		tx, report, err := c.SafeTransferFrom(ctx, auth, common.HexToAddress(params[0]), mustTokenID(params[1]))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Println(report)
+		fmt.Printf("交易已发送，哈希: %s\n", tx.Hash().Hex())
+		fmt.Printf("可以在Etherscan查看: https://sepolia.etherscan.io/tx/%s\n", tx.Hash().Hex())
+		if *wait {
+			fmt.Println("等待交易确认...")
+			receipt, err := bind.WaitMined(ctx, client, tx)
+			if err != nil {
+				log.Fatalf("等待交易确认失败: %v", err)
+			}
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				log.Fatalf("交易执行失败，区块 %d", receipt.BlockNumber.Uint64())
+			}
+			fmt.Printf("交易已确认，区块 %d，Gas使用 %d\n", receipt.BlockNumber.Uint64(), receipt.GasUsed)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func needArgs(params []string, n int) {
+	if len(params) != n {
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func ownerArg(params []string, def common.Address) common.Address {
+	if len(params) == 0 {
+		return def
+	}
+	if !common.IsHexAddress(params[0]) {
+		log.Fatalf("无效地址: %s", params[0])
+	}
+	return common.HexToAddress(params[0])
+}
+
+func mustTokenID(s string) *big.Int {
+	id, ok := new(big.Int).SetString(s, 0)
+	if !ok || id.Sign() < 0 {
+		log.Fatalf("无效的tokenId: %s", s)
+	}
+	return id
+}
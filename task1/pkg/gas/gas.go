@@ -0,0 +1,213 @@
+package gas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"DApp/pkg/config"
+	"DApp/pkg/units"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultMultiplier 未加载配置或配置未设置时使用的Gas安全系数
+const DefaultMultiplier = config.DefaultGasMultiplier
+
+// revertErrorCode 节点对执行回滚返回的JSON-RPC错误码
+const revertErrorCode = 3
+
+// ErrReverted 模拟执行被合约回滚，交易不会被广播
+var ErrReverted = errors.New("模拟执行回滚")
+
+// RevertError 模拟执行回滚的原因
+type RevertError struct {
+	Reason string // 解码后的原因，无法解码时为空
+	Data   []byte // 原始回滚数据
+	Err    error
+}
+
+func (e *RevertError) Error() string {
+	switch {
+	case e.Reason != "":
+		return fmt.Sprintf("%v: %s", ErrReverted, e.Reason)
+	case len(e.Data) >= 4:
+		return fmt.Sprintf("%v: 自定义错误 %s (数据 %s)", ErrReverted, hexutil.Encode(e.Data[:4]), hexutil.Encode(e.Data))
+	default:
+		return fmt.Sprintf("%v: %v", ErrReverted, e.Err)
+	}
+}
+
+func (e *RevertError) Unwrap() []error { return []error{ErrReverted, e.Err} }
+
+// Report 写操作发送前的模拟与费用估算结果
+type Report struct {
+	Estimate   uint64   // eth_estimateGas结果
+	GasLimit   uint64   // 乘以安全系数后的Gas上限
+	Multiplier float64  // 安全系数
+	GasPrice   *big.Int // 旧版交易的Gas价格，EIP-1559交易为nil
+	GasFeeCap  *big.Int // EIP-1559交易的maxFeePerGas
+	GasTipCap  *big.Int // EIP-1559交易的maxPriorityFeePerGas
+	BaseFee    *big.Int // 最新区块的baseFee，链不支持时为nil
+	MinFee     *big.Int // 预计最低手续费(wei)：预估Gas × 实际单价
+	MaxFee     *big.Int // 最高手续费(wei)：Gas上限 × 最高单价
+	ReturnData []byte   // eth_call返回的数据
+}
+
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "模拟执行: 成功\n")
+	fmt.Fprintf(&b, "预估Gas: %d, Gas上限: %d (x%.2f)\n", r.Estimate, r.GasLimit, r.Multiplier)
+	if r.GasPrice != nil {
+		fmt.Fprintf(&b, "Gas价格: %s gwei\n", units.FormatUnits(r.GasPrice, 9))
+	} else {
+		fmt.Fprintf(&b, "maxFeePerGas: %s gwei, maxPriorityFeePerGas: %s gwei\n", units.FormatUnits(r.GasFeeCap, 9), units.FormatUnits(r.GasTipCap, 9))
+	}
+	if r.BaseFee != nil {
+		fmt.Fprintf(&b, "当前baseFee: %s gwei\n", units.FormatUnits(r.BaseFee, 9))
+	}
+	fmt.Fprintf(&b, "预计手续费: %s ~ %s ETH (%s ~ %s gwei)",
+		units.FormatEther(r.MinFee), units.FormatEther(r.MaxFee),
+		units.FormatUnits(r.MinFee, 9), units.FormatUnits(r.MaxFee, 9))
+	return b.String()
+}
+
+// Multiplier 返回配置中的Gas安全系数
+func Multiplier() float64 {
+	if c := config.GetConfig(); c != nil && c.Gas.SafetyMultiplier > 0 {
+		return c.Gas.SafetyMultiplier
+	}
+	return DefaultMultiplier
+}
+
+// Simulate 对msg先做eth_call试运行再eth_estimateGas，回滚时返回*RevertError。
+// msg中未设置费用时使用节点建议的Gas价格
+func Simulate(ctx context.Context, backend bind.ContractBackend, msg ethereum.CallMsg) (*Report, error) {
+	// 不带费用字段调用，避免节点按区块Gas上限×单价检查余额
+	call := ethereum.CallMsg{From: msg.From, To: msg.To, Value: msg.Value, Data: msg.Data}
+	ret, err := backend.CallContract(ctx, call, nil)
+	if err != nil {
+		return nil, revertError(err)
+	}
+	estimate, err := backend.EstimateGas(ctx, call)
+	if err != nil {
+		return nil, fmt.Errorf("无法估计Gas限制: %w", revertError(err))
+	}
+	r := &Report{
+		Estimate:   estimate,
+		Multiplier: Multiplier(),
+		ReturnData: ret,
+		GasPrice:   msg.GasPrice,
+		GasFeeCap:  msg.GasFeeCap,
+		GasTipCap:  msg.GasTipCap,
+	}
+	r.GasLimit = uint64(float64(estimate) * r.Multiplier)
+	if head, err := backend.HeaderByNumber(ctx, nil); err == nil {
+		r.BaseFee = head.BaseFee
+	}
+	if r.GasPrice == nil && r.GasFeeCap == nil {
+		if r.GasPrice, err = backend.SuggestGasPrice(ctx); err != nil {
+			return nil, fmt.Errorf("无法获取Gas价格: %w", err)
+		}
+	}
+	r.fees()
+	return r, nil
+}
+
+// fees 计算手续费区间
+func (r *Report) fees() {
+	estimate, limit := new(big.Int).SetUint64(r.Estimate), new(big.Int).SetUint64(r.GasLimit)
+	if r.GasPrice != nil {
+		r.MinFee = new(big.Int).Mul(estimate, r.GasPrice)
+		r.MaxFee = new(big.Int).Mul(limit, r.GasPrice)
+		return
+	}
+	price := new(big.Int).Set(r.GasFeeCap)
+	if r.BaseFee != nil && r.GasTipCap != nil {
+		if effective := new(big.Int).Add(r.BaseFee, r.GasTipCap); effective.Cmp(price) < 0 {
+			price = effective
+		}
+	}
+	r.MinFee = new(big.Int).Mul(estimate, price)
+	r.MaxFee = new(big.Int).Mul(limit, r.GasFeeCap)
+}
+
+// Transact 在发送合约交易前先模拟。fn是合约绑定的写方法，会被调用两次：
+// 第一次只构造交易不发送，用来取得调用数据做模拟；模拟通过后用算好的Gas上限真正发送
+func Transact(ctx context.Context, backend bind.ContractBackend, auth *bind.TransactOpts, fn func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, *Report, error) {
+	dry := *auth
+	dry.NoSend = true
+	dry.GasLimit = 1 // 避免绑定自己估算Gas，回滚原因由Simulate给出
+	unsent, err := fn(&dry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msg := ethereum.CallMsg{
+		From:  auth.From,
+		To:    unsent.To(),
+		Value: unsent.Value(),
+		Data:  unsent.Data(),
+	}
+	if unsent.Type() == types.LegacyTxType {
+		msg.GasPrice = unsent.GasPrice()
+	} else {
+		msg.GasFeeCap, msg.GasTipCap = unsent.GasFeeCap(), unsent.GasTipCap()
+	}
+	report, err := Simulate(ctx, backend, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := *auth
+	opts.GasLimit = report.GasLimit
+	opts.Nonce = new(big.Int).SetUint64(unsent.Nonce())
+	opts.GasPrice, opts.GasFeeCap, opts.GasTipCap = msg.GasPrice, msg.GasFeeCap, msg.GasTipCap
+	tx, err := fn(&opts)
+	if err != nil {
+		return nil, report, err
+	}
+	return tx, report, nil
+}
+
+// IsRevert 判断eth_call等调用的错误是否为合约执行回滚，网络、限流和ctx取消等错误返回false
+func IsRevert(err error) bool {
+	if err == nil {
+		return false
+	}
+	var re *RevertError
+	return errors.As(revertError(err), &re)
+}
+
+// revertError 从节点返回的错误中取出回滚数据并解码Error(string)/Panic(uint256)。
+// 只认JSON-RPC错误码3或带回滚数据的错误，不匹配错误文本
+func revertError(err error) error {
+	var data []byte
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		switch d := dataErr.ErrorData().(type) {
+		case string:
+			data, _ = hexutil.Decode(d)
+		case []byte:
+			data = d
+		}
+	}
+	var rpcErr rpc.Error
+	if len(data) == 0 && !(errors.As(err, &rpcErr) && rpcErr.ErrorCode() == revertErrorCode) {
+		return err
+	}
+	re := &RevertError{Data: data, Err: err}
+	if reason, uerr := abi.UnpackRevert(data); uerr == nil {
+		re.Reason = reason
+	} else if len(data) < 4 {
+		re.Reason = strings.TrimPrefix(err.Error(), "execution reverted: ")
+	}
+	return re
+}
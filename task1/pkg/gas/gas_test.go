@@ -0,0 +1,55 @@
+package gas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// jsonError 节点返回的JSON-RPC错误
+type jsonError struct {
+	code int
+	msg  string
+	data interface{}
+}
+
+func (e jsonError) Error() string          { return e.msg }
+func (e jsonError) ErrorCode() int         { return e.code }
+func (e jsonError) ErrorData() interface{} { return e.data }
+
+func TestIsRevert(t *testing.T) {
+	// Error(string) "nope"
+	const reasonData = "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6e6f706500000000000000000000000000000000000000000000000000000000"
+	tests := []struct {
+		name       string
+		err        error
+		want       bool
+		wantReason string
+	}{
+		{name: "code 3带原因", err: jsonError{3, "execution reverted: nope", reasonData}, want: true, wantReason: "nope"},
+		{name: "code 3无数据", err: jsonError{3, "execution reverted", nil}, want: true},
+		{name: "包装后的code 3", err: fmt.Errorf("调用失败: %w", jsonError{3, "execution reverted: nope", reasonData}), want: true, wantReason: "nope"},
+		{name: "其他错误码", err: jsonError{-32000, "insufficient funds", nil}},
+		// 只有文本包含revert不算回滚
+		{name: "文本包含revert", err: errors.New("upstream reverted to cached response")},
+		{name: "HTTP错误", err: rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}},
+		{name: "ctx取消", err: context.Canceled},
+		{name: "nil", err: nil},
+	}
+	for _, tt := range tests {
+		if got := IsRevert(tt.err); got != tt.want {
+			t.Errorf("%s: IsRevert = %v，期望 %v", tt.name, got, tt.want)
+			continue
+		}
+		var re *RevertError
+		if tt.wantReason != "" && (!errors.As(revertError(tt.err), &re) || re.Reason != tt.wantReason) {
+			t.Errorf("%s: 回滚原因 %+v，期望 %q", tt.name, re, tt.wantReason)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"DApp/pkg/hdwallet"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+)
+
+// 从一个助记词确定性地派生测试钱包：N个以太坊账户(m/44'/60'/0'/0/i)和N个Solana账户(m/44'/501'/i'/0')，
+// 打印或导出为JSON/CSV，Solana账户还可以导出为solana-keygen密钥文件；可选在派生序号中并行搜索靓号地址。
+// 助记词通过 -mnemonic 或环境变量 HD_MNEMONIC 传入，-new 生成新的助记词。
+//
+//	go run ./pkg/hdkeys -new -n 5
+//	go run ./pkg/hdkeys -n 10 -chain sol -keygen-dir keys
+//	go run ./pkg/hdkeys -vanity-chain eth -prefix dead -workers 8
+func main() {
+	mnemonic := flag.String("mnemonic", os.Getenv("HD_MNEMONIC"), "BIP39助记词，默认读取环境变量HD_MNEMONIC")
+	newMnemonic := flag.Bool("new", false, "生成新的助记词")
+	words := flag.Int("words", 12, "生成助记词的单词数：12或24")
+	passphrase := flag.String("passphrase", os.Getenv("HD_PASSPHRASE"), "BIP39口令（可选），默认读取环境变量HD_PASSPHRASE")
+	chain := flag.String("chain", "both", "派生的链：eth、sol或both")
+	n := flag.Uint("n", 5, "每条链派生的账户数")
+	start := flag.Uint("start", 0, "起始序号")
+	format := flag.String("format", "table", "输出格式：table、json或csv（json/csv包含私钥）")
+	out := flag.String("out", "", "输出文件，默认标准输出")
+	showKeys := flag.Bool("show-keys", false, "table格式下同时打印私钥")
+	keygenDir := flag.String("keygen-dir", "", "把Solana账户导出为solana-keygen密钥文件的目录")
+
+	vanityChain := flag.String("vanity-chain", "", "搜索靓号地址的链：eth或sol，设置后只做靓号搜索")
+	prefix := flag.String("prefix", "", "靓号前缀（以太坊不含0x）")
+	suffix := flag.String("suffix", "", "靓号后缀")
+	caseSensitive := flag.Bool("case-sensitive", false, "区分大小写（以太坊按EIP-55校验和格式）")
+	maxIndex := flag.Uint("max-index", 0, "靓号搜索的最大序号，0表示不限制")
+	workers := flag.Int("workers", 0, "靓号搜索的并行数，默认CPU核数")
+	flag.Parse()
+
+	// 序号按uint32派生，直接转换会截断成另一个序号
+	startIndex, count, maxIdx := toUint32("start", *start), toUint32("n", *n), toUint32("max-index", *maxIndex)
+	if uint64(startIndex)+uint64(count) > math.MaxUint32 {
+		log.Fatalf("-start %d 加 -n %d 超过 %d", startIndex, count, uint64(math.MaxUint32))
+	}
+
+	if *newMnemonic {
+		m, err := hdwallet.NewMnemonic(*words / 3 * 32)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		*mnemonic = m
+		// 助记词写到标准错误，重定向导出结果时不会混进文件
+		fmt.Fprintf(os.Stderr, "新助记词（请妥善保管）:\n%s\n\n", m)
+	}
+	if *mnemonic == "" {
+		fmt.Fprintln(os.Stderr, "需要通过 -mnemonic、HD_MNEMONIC 提供助记词，或使用 -new 生成")
+		flag.Usage()
+		os.Exit(2)
+	}
+	seed, err := hdwallet.Seed(*mnemonic, *passphrase)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	if *vanityChain != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		searchVanity(ctx, seed, hdwallet.VanityOptions{
+			Chain:         hdwallet.Chain(*vanityChain),
+			Prefix:        *prefix,
+			Suffix:        *suffix,
+			CaseSensitive: *caseSensitive,
+			Start:         startIndex,
+			MaxIndex:      maxIdx,
+			Workers:       *workers,
+		}, *showKeys)
+		return
+	}
+
+	var accounts []account
+	if *chain == "eth" || *chain == "both" {
+		eth, err := hdwallet.DeriveEthereum(seed, startIndex, count)
+		if err != nil {
+			log.Fatalf("派生以太坊账户失败: %v", err)
+		}
+		for _, a := range eth {
+			accounts = append(accounts, account{Chain: "eth", Index: a.Index, Path: a.Path, Address: a.Address.Hex(), PrivateKey: a.PrivateKeyHex()})
+		}
+	}
+	if *chain == "sol" || *chain == "both" {
+		sol, err := hdwallet.DeriveSolana(seed, startIndex, count)
+		if err != nil {
+			log.Fatalf("派生Solana账户失败: %v", err)
+		}
+		for _, a := range sol {
+			accounts = append(accounts, account{Chain: "sol", Index: a.Index, Path: a.Path, Address: a.Address(), PrivateKey: a.PrivateKeyBase58()})
+		}
+		if *keygenDir != "" {
+			if err := writeKeygenFiles(*keygenDir, sol); err != nil {
+				log.Fatalf("%v", err)
+			}
+		}
+	}
+	if len(accounts) == 0 {
+		log.Fatalf("未知的链: %s（可选 eth/sol/both）", *chain)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		file, err := os.OpenFile(*out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			log.Fatalf("创建输出文件失败: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := writeAccounts(w, *format, accounts, *showKeys); err != nil {
+		log.Fatalf("%v", err)
+	}
+}
+
+// toUint32 检查uint参数不超过uint32范围，超过时退出
+func toUint32(name string, v uint) uint32 {
+	if uint64(v) > math.MaxUint32 {
+		log.Fatalf("-%s %d 超过 %d", name, v, uint64(math.MaxUint32))
+	}
+	return uint32(v)
+}
+
+// account 导出的一行账户信息。以太坊私钥为不带0x的十六进制，可填入etc/config.yaml的server.private_key；
+// Solana私钥为base58，密钥文件见-keygen-dir
+type account struct {
+	Chain      string `json:"chain"`
+	Index      uint32 `json:"index"`
+	Path       string `json:"path"`
+	Address    string `json:"address"`
+	PrivateKey string `json:"private_key"`
+}
+
+func writeAccounts(f *os.File, format string, accounts []account, showKeys bool) error {
+	switch format {
+	case "table":
+		for _, a := range accounts {
+			fmt.Fprintf(f, "%-4s %-4d %-20s %s", a.Chain, a.Index, a.Path, a.Address)
+			if showKeys {
+				fmt.Fprintf(f, "  %s", a.PrivateKey)
+			}
+			fmt.Fprintln(f)
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(accounts); err != nil {
+			return fmt.Errorf("写入JSON失败: %w", err)
+		}
+		return nil
+	case "csv":
+		w := csv.NewWriter(f)
+		w.Write([]string{"chain", "index", "path", "address", "private_key"})
+		for _, a := range accounts {
+			w.Write([]string{a.Chain, strconv.FormatUint(uint64(a.Index), 10), a.Path, a.Address, a.PrivateKey})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("写入CSV失败: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("未知的输出格式: %s（可选 table/json/csv）", format)
+}
+
+// writeKeygenFiles 每个Solana账户写一个 sol-<序号>.json，格式与solana-keygen一致
+func writeKeygenFiles(dir string, accounts []*hdwallet.SolanaAccount) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	for _, a := range accounts {
+		path := filepath.Join(dir, fmt.Sprintf("sol-%d.json", a.Index))
+		if err := os.WriteFile(path, a.KeygenJSON(), 0o600); err != nil {
+			return fmt.Errorf("写入密钥文件失败: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "已写入 %s (%s)\n", path, a.Address())
+	}
+	return nil
+}
+
+func searchVanity(ctx context.Context, seed []byte, opts hdwallet.VanityOptions, showKeys bool) {
+	expected := hdwallet.ExpectedAttempts(opts.Chain, opts.Prefix, opts.Suffix, opts.CaseSensitive)
+	fmt.Fprintf(os.Stderr, "搜索 %s 地址 前缀%q 后缀%q，期望尝试 %.0f 次\n", opts.Chain, opts.Prefix, opts.Suffix, expected)
+	opts.OnProgress = func(p hdwallet.VanityProgress) {
+		fmt.Fprintf(os.Stderr, "\r%s   ", p)
+	}
+	match, err := hdwallet.SearchVanity(ctx, seed, opts)
+	fmt.Fprintln(os.Stderr)
+	if errors.Is(err, context.Canceled) {
+		log.Fatalf("已中断")
+	}
+	if err != nil {
+		log.Fatalf("靓号搜索失败: %v", err)
+	}
+	fmt.Printf("找到序号 %d: %s（检查 %d 个，用时 %s）\n", match.Index, match.Address, match.Checked, match.Elapsed.Round(1e6))
+	switch {
+	case match.Eth != nil:
+		fmt.Printf("派生路径: %s\n", match.Eth.Path)
+		if showKeys {
+			fmt.Printf("私钥: %s\n", match.Eth.PrivateKeyHex())
+		}
+	case match.Sol != nil:
+		fmt.Printf("派生路径: %s\n", match.Sol.Path)
+		if showKeys {
+			fmt.Printf("私钥: %s\n", match.Sol.PrivateKeyBase58())
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package hdwallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"solana-go/wallet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// 由一个BIP39助记词确定性地派生以太坊和Solana账户：
+// 以太坊按BIP32(secp256k1)派生 m/44'/60'/0'/0/i，与MetaMask、Hardhat一致；
+// Solana按SLIP-0010(ed25519)派生 m/44'/501'/i'/0'，与Phantom、solana-keygen一致，
+// 派生和导出直接使用task3/solana/wallet，两个项目得到的Solana账户不会不一致。
+// 以太坊私钥导出为不带0x的十六进制（config的server.private_key、signer.FromHex可直接使用），
+// Solana私钥导出为solana-keygen的字节数组JSON（utils.GetAccountFromPrivateKey可直接读取）。
+
+// ErrInvalidPath 派生路径格式错误
+var ErrInvalidPath = errors.New("无效的派生路径")
+
+const (
+	hardenedOffset = 0x80000000
+	bitcoinSeed    = "Bitcoin seed" // BIP32主密钥的HMAC key
+
+	// EthereumBasePath 以太坊账户路径的公共前缀，第i个账户为 EthereumBasePath/i
+	EthereumBasePath = "m/44'/60'/0'/0"
+	// SolanaBasePath Solana账户路径的公共前缀，第i个账户为 SolanaBasePath/i'/0'
+	SolanaBasePath = "m/44'/501'"
+)
+
+// NewMnemonic 生成助记词，bits为128（12个单词）或256（24个单词）
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", fmt.Errorf("生成助记词熵失败: %w", err)
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", fmt.Errorf("生成助记词失败: %w", err)
+	}
+	return mnemonic, nil
+}
+
+// Seed 校验助记词并生成BIP39种子，passphrase为可选口令
+func Seed(mnemonic, passphrase string) ([]byte, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("助记词校验失败: %w", err)
+	}
+	return seed, nil
+}
+
+// EthereumPath 第i个以太坊账户的派生路径
+func EthereumPath(i uint32) string {
+	return fmt.Sprintf("%s/%d", EthereumBasePath, i)
+}
+
+// SolanaPath 第i个Solana账户的派生路径
+func SolanaPath(i uint32) string {
+	return fmt.Sprintf("%s/%d'/0'", SolanaBasePath, i)
+}
+
+// EthAccount 派生出的以太坊账户
+type EthAccount struct {
+	Index   uint32
+	Path    string
+	Address common.Address
+	Key     *ecdsa.PrivateKey
+}
+
+// PrivateKeyHex 不带0x前缀的十六进制私钥，与crypto.HexToECDSA的输入格式一致
+func (a *EthAccount) PrivateKeyHex() string {
+	return hex.EncodeToString(crypto.FromECDSA(a.Key))
+}
+
+// SolanaAccount 派生出的Solana账户
+type SolanaAccount struct {
+	Index uint32
+	Path  string
+	Key   ed25519.PrivateKey
+}
+
+// Address base58编码的公钥
+func (a *SolanaAccount) Address() string {
+	return solana.PrivateKey(a.Key).PublicKey().String()
+}
+
+// PrivateKeyBase58 base58编码的64字节私钥，Phantom等钱包可以导入
+func (a *SolanaAccount) PrivateKeyBase58() string {
+	return wallet.ToBase58(solana.PrivateKey(a.Key))
+}
+
+// KeygenJSON solana-keygen格式的字节数组JSON
+func (a *SolanaAccount) KeygenJSON() []byte {
+	return wallet.ToJSON(solana.PrivateKey(a.Key))
+}
+
+// EthereumDeriver 以太坊账户派生器，预先算好 m/44'/60'/0'/0 节点，逐个派生账户只需一次子密钥计算
+type EthereumDeriver struct {
+	parent *bip32Key
+}
+
+// NewEthereumDeriver 由种子创建以太坊账户派生器
+func NewEthereumDeriver(seed []byte) (*EthereumDeriver, error) {
+	parent, err := deriveBIP32(seed, EthereumBasePath)
+	if err != nil {
+		return nil, err
+	}
+	return &EthereumDeriver{parent: parent}, nil
+}
+
+// Account 派生第i个账户
+func (d *EthereumDeriver) Account(i uint32) (*EthAccount, error) {
+	if i >= hardenedOffset {
+		return nil, fmt.Errorf("%w: 账户序号过大: %d", ErrInvalidPath, i)
+	}
+	child, err := d.parent.child(i)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.ToECDSA(child.key)
+	if err != nil {
+		return nil, fmt.Errorf("构造私钥失败: %w", err)
+	}
+	return &EthAccount{Index: i, Path: EthereumPath(i), Address: crypto.PubkeyToAddress(key.PublicKey), Key: key}, nil
+}
+
+// SolanaDeriver Solana账户派生器，按wallet.DeriveFromSeed逐个派生
+type SolanaDeriver struct {
+	seed []byte
+}
+
+// NewSolanaDeriver 由种子创建Solana账户派生器
+func NewSolanaDeriver(seed []byte) (*SolanaDeriver, error) {
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("种子不能为空")
+	}
+	return &SolanaDeriver{seed: seed}, nil
+}
+
+// Account 派生第i个账户
+func (d *SolanaDeriver) Account(i uint32) (*SolanaAccount, error) {
+	if i >= hardenedOffset {
+		return nil, fmt.Errorf("%w: 账户序号过大: %d", ErrInvalidPath, i)
+	}
+	path := SolanaPath(i)
+	key, err := wallet.DeriveFromSeed(d.seed, path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+	return &SolanaAccount{Index: i, Path: path, Key: ed25519.PrivateKey(key)}, nil
+}
+
+// DeriveEthereum 从start开始派生n个以太坊账户
+func DeriveEthereum(seed []byte, start, n uint32) ([]*EthAccount, error) {
+	if err := checkRange(start, n); err != nil {
+		return nil, err
+	}
+	d, err := NewEthereumDeriver(seed)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*EthAccount, 0, n)
+	for i := start; i < start+n; i++ {
+		a, err := d.Account(i)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, nil
+}
+
+// DeriveSolana 从start开始派生n个Solana账户
+func DeriveSolana(seed []byte, start, n uint32) ([]*SolanaAccount, error) {
+	if err := checkRange(start, n); err != nil {
+		return nil, err
+	}
+	d, err := NewSolanaDeriver(seed)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*SolanaAccount, 0, n)
+	for i := start; i < start+n; i++ {
+		a, err := d.Account(i)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, nil
+}
+
+// checkRange 检查start+n-1不超过最大账户序号，避免start+n溢出uint32后循环不结束或一个账户都不派生
+func checkRange(start, n uint32) error {
+	if n > 0 && uint64(start)+uint64(n)-1 > maxAccountIndex {
+		return fmt.Errorf("%w: 账户序号范围 %d+%d 超过最大序号 %d", ErrInvalidPath, start, n, maxAccountIndex)
+	}
+	return nil
+}
+
+// bip32Key BIP32扩展私钥
+type bip32Key struct {
+	key       []byte // 32字节私钥
+	chainCode []byte
+}
+
+// deriveBIP32 从种子按路径派生secp256k1扩展私钥
+func deriveBIP32(seed []byte, path string) (*bip32Key, error) {
+	indexes, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := hmacSHA512([]byte(bitcoinSeed), seed)
+	if k := new(big.Int).SetBytes(sum[:32]); k.Sign() == 0 || k.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, fmt.Errorf("种子生成的主密钥无效，请更换助记词")
+	}
+	node := &bip32Key{key: sum[:32], chainCode: sum[32:]}
+	for _, i := range indexes {
+		if node, err = node.child(i); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// child 派生子私钥：硬化派生使用父私钥，普通派生使用父公钥（压缩格式）
+func (k *bip32Key) child(i uint32) (*bip32Key, error) {
+	data := make([]byte, 0, 37)
+	if i >= hardenedOffset {
+		data = append(data, 0)
+		data = append(data, k.key...)
+	} else {
+		priv, err := crypto.ToECDSA(k.key)
+		if err != nil {
+			return nil, fmt.Errorf("构造私钥失败: %w", err)
+		}
+		data = append(data, crypto.CompressPubkey(&priv.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, i)
+	sum := hmacSHA512(k.chainCode, data)
+
+	n := crypto.S256().Params().N
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("序号 %d 派生出无效的子密钥，请跳过该序号", i)
+	}
+	childKey := il.Add(il, new(big.Int).SetBytes(k.key))
+	childKey.Mod(childKey, n)
+	if childKey.Sign() == 0 {
+		return nil, fmt.Errorf("序号 %d 派生出无效的子密钥，请跳过该序号", i)
+	}
+	return &bip32Key{key: childKey.FillBytes(make([]byte, 32)), chainCode: sum[32:]}, nil
+}
+
+// parsePath 解析m/44'/60'/0'/0形式的路径
+func parsePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("%w: 必须以m开头: %q", ErrInvalidPath, path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		trimmed := strings.TrimRight(part, "'h")
+		hardened := trimmed != part
+		n, err := strconv.ParseUint(trimmed, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q 中的 %q 无效", ErrInvalidPath, path, part)
+		}
+		if hardened {
+			n += hardenedOffset
+		}
+		indexes = append(indexes, uint32(n))
+	}
+	return indexes, nil
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
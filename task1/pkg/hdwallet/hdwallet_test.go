@@ -0,0 +1,152 @@
+package hdwallet
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 测试助记词为BIP39的全零熵助记词，各钱包对它的派生结果公开可查。
+// Solana的SLIP-0010派生由task3/solana/wallet实现，向量测试在那边
+const (
+	testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	// testEthAddress MetaMask在 m/44'/60'/0'/0/0 的地址
+	testEthAddress = "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+	// testSolAddress Phantom在 m/44'/501'/0'/0' 的地址
+	testSolAddress = "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk"
+	// testKeygenFile 第0个Solana账户的solana-keygen文件，hdkeys -keygen-dir写出的就是这种文件
+	testKeygenFile = "testdata/solana-keygen.json"
+)
+
+// vectorSeed BIP32测试向量1的种子
+var vectorSeed, _ = hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+type vector struct {
+	path      string
+	chainCode string
+	key       string
+}
+
+func TestBIP32Vectors(t *testing.T) {
+	// BIP32 Test vector 1
+	vectors := []vector{
+		{"m", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{"m/0'", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"m/0'/1/2'/2", "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"m/0'/1/2'/2/1000000000", "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, v := range vectors {
+		node, err := deriveBIP32(vectorSeed, v.path)
+		if err != nil {
+			t.Fatalf("%s: %v", v.path, err)
+		}
+		if got := hex.EncodeToString(node.chainCode); got != v.chainCode {
+			t.Fatalf("%s chain code = %s，期望 %s", v.path, got, v.chainCode)
+		}
+		if got := hex.EncodeToString(node.key); got != v.key {
+			t.Fatalf("%s 私钥 = %s，期望 %s", v.path, got, v.key)
+		}
+	}
+}
+
+func TestEthereumAccountRoundTrip(t *testing.T) {
+	seed, err := Seed(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := DeriveEthereum(seed, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := accounts[0].Address.Hex(); got != testEthAddress {
+		t.Fatalf("第0个以太坊账户 = %s，期望 %s", got, testEthAddress)
+	}
+	for _, a := range accounts {
+		// 导出的私钥可以直接给crypto.HexToECDSA（config的server.private_key）使用
+		key, err := crypto.HexToECDSA(a.PrivateKeyHex())
+		if err != nil {
+			t.Fatalf("%s: %v", a.Path, err)
+		}
+		if crypto.PubkeyToAddress(key.PublicKey) != a.Address {
+			t.Fatalf("%s 私钥还原出的地址与派生地址不一致", a.Path)
+		}
+	}
+}
+
+func TestSolanaAccountRoundTrip(t *testing.T) {
+	seed, err := Seed(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := DeriveSolana(seed, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := accounts[0].Address(); got != testSolAddress {
+		t.Fatalf("第0个Solana账户 = %s，期望 %s", got, testSolAddress)
+	}
+	for _, a := range accounts {
+		// 按solana-keygen的格式解析：字节数组，后32字节为前32字节对应的公钥
+		var ints []int
+		if err := json.Unmarshal(a.KeygenJSON(), &ints); err != nil {
+			t.Fatalf("%s: %v", a.Path, err)
+		}
+		raw := make([]byte, len(ints))
+		for i, v := range ints {
+			raw[i] = byte(v)
+		}
+		if len(raw) != ed25519.PrivateKeySize || !bytes.Equal(ed25519.NewKeyFromSeed(raw[:ed25519.SeedSize]), raw) {
+			t.Fatalf("%s keygen JSON不是有效的密钥", a.Path)
+		}
+		if !bytes.Equal(raw, a.Key) {
+			t.Fatalf("%s keygen JSON与派生私钥不一致", a.Path)
+		}
+	}
+
+	// 与提交的keygen文件一致，导出格式不能变
+	want, err := os.ReadFile(filepath.FromSlash(testKeygenFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(accounts[0].KeygenJSON(), bytes.TrimSpace(want)) {
+		t.Fatalf("第0个账户的keygen JSON与 %s 不一致", testKeygenFile)
+	}
+}
+
+func TestDeriveRange(t *testing.T) {
+	seed, err := Seed(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		start, n uint32
+		wantErr  bool
+	}{
+		{start: maxAccountIndex, n: 1},
+		{start: maxAccountIndex, n: 2, wantErr: true},
+		{start: hardenedOffset, n: 1, wantErr: true},
+		// start+n溢出uint32
+		{start: 1 << 31, n: 1 << 31, wantErr: true},
+		{start: 0xffffffff, n: 2, wantErr: true},
+		{start: 5, n: 0},
+	}
+	for _, tt := range tests {
+		eth, err := DeriveEthereum(seed, tt.start, tt.n)
+		if tt.wantErr != errors.Is(err, ErrInvalidPath) || (!tt.wantErr && len(eth) != int(tt.n)) {
+			t.Fatalf("DeriveEthereum(%d, %d) 得到 %d 个账户，err = %v", tt.start, tt.n, len(eth), err)
+		}
+		sol, err := DeriveSolana(seed, tt.start, tt.n)
+		if tt.wantErr != errors.Is(err, ErrInvalidPath) || (!tt.wantErr && len(sol) != int(tt.n)) {
+			t.Fatalf("DeriveSolana(%d, %d) 得到 %d 个账户，err = %v", tt.start, tt.n, len(sol), err)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package hdwallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// 靓号搜索：在助记词的派生序号中并行查找地址带指定前缀/后缀的账户。
+// 与随机生成密钥不同，找到的账户仍能由助记词和序号恢复；多个worker按批次领取序号，
+// 结果总是范围内最小的匹配序号，同样的参数重复运行结果相同。
+
+// Chain 链类型
+type Chain string
+
+const (
+	ChainEthereum Chain = "eth"
+	ChainSolana   Chain = "sol"
+)
+
+// ErrVanityNotFound 在序号范围内没有找到匹配的地址
+var ErrVanityNotFound = errors.New("在序号范围内没有找到匹配的地址")
+
+const (
+	vanityBatch     = 256
+	base58Alphabet  = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	hexAlphabet     = "0123456789abcdef"
+	maxAccountIndex = hardenedOffset - 1
+	defaultProgress = time.Second
+)
+
+// VanityOptions 靓号搜索参数
+type VanityOptions struct {
+	Chain  Chain
+	Prefix string // 以太坊地址不含0x
+	Suffix string
+	// CaseSensitive 区分大小写：以太坊按EIP-55校验和格式比较，Solana按base58原样比较
+	CaseSensitive bool
+	Start         uint32 // 起始序号
+	MaxIndex      uint32 // 最大序号（含），0表示不限制
+	Workers       int    // 并行数，默认CPU核数
+	// OnProgress 每隔ProgressInterval回调一次（默认1秒），在单独的goroutine中调用
+	OnProgress       func(VanityProgress)
+	ProgressInterval time.Duration
+}
+
+// VanityProgress 搜索进度
+type VanityProgress struct {
+	Checked  uint64        // 已检查的序号数
+	Elapsed  time.Duration // 已用时间
+	Expected float64       // 找到一个匹配的期望尝试次数
+}
+
+// Rate 每秒检查的序号数
+func (p VanityProgress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Checked) / p.Elapsed.Seconds()
+}
+
+// ETA 按当前速度检查完期望尝试次数还需要的时间，超出期望后为0
+func (p VanityProgress) ETA() time.Duration {
+	rate := p.Rate()
+	if rate == 0 || float64(p.Checked) >= p.Expected {
+		return 0
+	}
+	return time.Duration((p.Expected - float64(p.Checked)) / rate * float64(time.Second))
+}
+
+func (p VanityProgress) String() string {
+	return fmt.Sprintf("已检查 %d 个，%.0f 个/秒，期望 %.0f 次，预计还需 %s",
+		p.Checked, p.Rate(), p.Expected, p.ETA().Round(time.Second))
+}
+
+// VanityMatch 搜索结果，Eth和Sol按链类型二选一
+type VanityMatch struct {
+	Index   uint32
+	Address string
+	Eth     *EthAccount
+	Sol     *SolanaAccount
+	VanityProgress
+}
+
+// ValidateVanity 检查前缀/后缀只包含地址可能出现的字符
+func ValidateVanity(chain Chain, prefix, suffix string, caseSensitive bool) error {
+	if prefix == "" && suffix == "" {
+		return fmt.Errorf("前缀和后缀不能都为空")
+	}
+	var alphabet string
+	switch chain {
+	case ChainEthereum:
+		alphabet = hexAlphabet + "ABCDEF"
+	case ChainSolana:
+		alphabet = base58Alphabet
+	default:
+		return fmt.Errorf("不支持的链: %s", chain)
+	}
+	if !caseSensitive {
+		alphabet = strings.ToLower(alphabet)
+	}
+	for _, c := range prefix + suffix {
+		check := c
+		if !caseSensitive {
+			check = unicode.ToLower(c)
+		}
+		if !strings.ContainsRune(alphabet, check) {
+			return fmt.Errorf("%q 不会出现在%s地址中", c, chain)
+		}
+	}
+	if chain == ChainEthereum && len(prefix)+len(suffix) > 40 {
+		return fmt.Errorf("前缀和后缀总长度超过以太坊地址长度")
+	}
+	return nil
+}
+
+// ExpectedAttempts 找到一个匹配地址的期望尝试次数（近似，Solana地址首字符分布并不均匀）
+func ExpectedAttempts(chain Chain, prefix, suffix string, caseSensitive bool) float64 {
+	p := 1.0
+	for _, c := range prefix + suffix {
+		switch chain {
+		case ChainEthereum:
+			p /= 16
+			// 校验和格式下字母的大小写各占一半
+			if caseSensitive && strings.ContainsRune("abcdefABCDEF", c) {
+				p /= 2
+			}
+		case ChainSolana:
+			matches := 1
+			if !caseSensitive {
+				matches = strings.Count(strings.ToLower(base58Alphabet), strings.ToLower(string(c)))
+			}
+			p *= float64(matches) / 58
+		}
+	}
+	if p == 0 {
+		return math.Inf(1)
+	}
+	return 1 / p
+}
+
+// SearchVanity 在seed派生的账户中查找靓号地址，ctx取消时返回ctx.Err()
+func SearchVanity(ctx context.Context, seed []byte, opts VanityOptions) (*VanityMatch, error) {
+	if opts.Chain == ChainEthereum {
+		opts.Prefix = strings.TrimPrefix(opts.Prefix, "0x")
+	}
+	if err := ValidateVanity(opts.Chain, opts.Prefix, opts.Suffix, opts.CaseSensitive); err != nil {
+		return nil, err
+	}
+	if opts.MaxIndex == 0 || opts.MaxIndex > maxAccountIndex {
+		opts.MaxIndex = maxAccountIndex
+	}
+	if opts.Start > opts.MaxIndex {
+		return nil, fmt.Errorf("起始序号 %d 超过最大序号 %d", opts.Start, opts.MaxIndex)
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.ProgressInterval <= 0 {
+		opts.ProgressInterval = defaultProgress
+	}
+
+	match, err := newMatcher(seed, opts)
+	if err != nil {
+		return nil, err
+	}
+	expected := ExpectedAttempts(opts.Chain, opts.Prefix, opts.Suffix, opts.CaseSensitive)
+	started := time.Now()
+	var (
+		checked  atomic.Uint64
+		next     atomic.Int64 // 下一个待领取批次的起始序号
+		mu       sync.Mutex
+		best     *VanityMatch
+		firstErr error
+	)
+	next.Store(int64(opts.Start))
+	progress := func() VanityProgress {
+		return VanityProgress{Checked: checked.Load(), Elapsed: time.Since(started), Expected: expected}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if opts.OnProgress != nil {
+		go func() {
+			ticker := time.NewTicker(opts.ProgressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					opts.OnProgress(progress())
+				}
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				start := next.Add(vanityBatch) - vanityBatch
+				if start > int64(opts.MaxIndex) {
+					return
+				}
+				mu.Lock()
+				// 已找到更小的序号时不再领取后面的批次
+				done := best != nil && int64(best.Index) < start
+				mu.Unlock()
+				if done {
+					return
+				}
+				end := min(start+vanityBatch-1, int64(opts.MaxIndex))
+				for i := start; i <= end && ctx.Err() == nil; i++ {
+					found, err := match(uint32(i))
+					checked.Add(1)
+					if err != nil || found != nil {
+						mu.Lock()
+						if err != nil && firstErr == nil {
+							firstErr = err
+							cancel()
+						}
+						if found != nil && (best == nil || found.Index < best.Index) {
+							best = found
+						}
+						mu.Unlock()
+						// 同一批次内后面的序号更大，不用再看
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if best != nil {
+		best.VanityProgress = progress()
+		return best, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: %d-%d", ErrVanityNotFound, opts.Start, opts.MaxIndex)
+}
+
+// newMatcher 返回检查单个序号的函数，匹配时返回结果
+func newMatcher(seed []byte, opts VanityOptions) (func(uint32) (*VanityMatch, error), error) {
+	prefix, suffix := opts.Prefix, opts.Suffix
+	normalize := func(s string) string { return s }
+	if !opts.CaseSensitive {
+		prefix, suffix = strings.ToLower(prefix), strings.ToLower(suffix)
+		normalize = strings.ToLower
+	}
+	matches := func(addr string) bool {
+		addr = normalize(addr)
+		return strings.HasPrefix(addr, prefix) && strings.HasSuffix(addr, suffix)
+	}
+
+	switch opts.Chain {
+	case ChainEthereum:
+		d, err := NewEthereumDeriver(seed)
+		if err != nil {
+			return nil, err
+		}
+		return func(i uint32) (*VanityMatch, error) {
+			a, err := d.Account(i)
+			if err != nil {
+				// 极少数序号派生不出有效密钥，按不匹配处理
+				return nil, nil
+			}
+			if addr := a.Address.Hex()[2:]; matches(addr) {
+				return &VanityMatch{Index: i, Address: a.Address.Hex(), Eth: a}, nil
+			}
+			return nil, nil
+		}, nil
+	case ChainSolana:
+		d, err := NewSolanaDeriver(seed)
+		if err != nil {
+			return nil, err
+		}
+		return func(i uint32) (*VanityMatch, error) {
+			a, err := d.Account(i)
+			if err != nil {
+				return nil, err
+			}
+			if addr := a.Address(); matches(addr) {
+				return &VanityMatch{Index: i, Address: addr, Sol: a}, nil
+			}
+			return nil, nil
+		}, nil
+	}
+	return nil, fmt.Errorf("不支持的链: %s", opts.Chain)
+}
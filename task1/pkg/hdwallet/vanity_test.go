@@ -0,0 +1,169 @@
+package hdwallet
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidateVanity(t *testing.T) {
+	tests := []struct {
+		chain         Chain
+		prefix        string
+		suffix        string
+		caseSensitive bool
+		wantErr       bool
+	}{
+		{chain: ChainEthereum, prefix: "dead", suffix: "BEEF"},
+		{chain: ChainEthereum, prefix: "DeAd", caseSensitive: true},
+		{chain: ChainEthereum, prefix: "xyz", wantErr: true},
+		{chain: ChainEthereum, prefix: strings.Repeat("a", 41), wantErr: true},
+		{chain: ChainSolana, prefix: "Sol"},
+		// base58不含0、O、I、l
+		{chain: ChainSolana, prefix: "0", wantErr: true},
+		{chain: ChainSolana, prefix: "I", caseSensitive: true, wantErr: true},
+		// 不区分大小写时i可以匹配地址中的i
+		{chain: ChainSolana, prefix: "I"},
+		{chain: ChainSolana, wantErr: true},
+		{chain: "btc", prefix: "1", wantErr: true},
+	}
+	for _, tt := range tests {
+		err := ValidateVanity(tt.chain, tt.prefix, tt.suffix, tt.caseSensitive)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateVanity(%s, %q, %q, %v) err = %v", tt.chain, tt.prefix, tt.suffix, tt.caseSensitive, err)
+		}
+	}
+}
+
+func TestExpectedAttempts(t *testing.T) {
+	tests := []struct {
+		chain         Chain
+		prefix        string
+		caseSensitive bool
+		want          float64
+	}{
+		{ChainEthereum, "ab", false, 256},
+		// 校验和格式下字母还要猜对大小写
+		{ChainEthereum, "aB", true, 1024},
+		{ChainEthereum, "12", true, 256},
+		{ChainSolana, "9", false, 58},
+		// 不区分大小写时a和A都能匹配
+		{ChainSolana, "a", false, 29},
+		{ChainSolana, "a", true, 58},
+	}
+	for _, tt := range tests {
+		if got := ExpectedAttempts(tt.chain, tt.prefix, "", tt.caseSensitive); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("ExpectedAttempts(%s, %q, %v) = %v，期望 %v", tt.chain, tt.prefix, tt.caseSensitive, got, tt.want)
+		}
+	}
+}
+
+func TestSearchVanity(t *testing.T) {
+	seed, err := Seed(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const limit = 300
+	eth, err := DeriveEthereum(seed, 0, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, err := DeriveSolana(seed, 0, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 逐个派生找出期望的最小序号，与并行搜索的结果对照
+	first := func(matches func(i int) bool) int {
+		for i := 0; i < limit; i++ {
+			if matches(i) {
+				return i
+			}
+		}
+		t.Fatal("前300个账户中没有匹配，换一个前缀")
+		return -1
+	}
+
+	tests := []struct {
+		name string
+		opts VanityOptions
+		want int
+	}{
+		{
+			name: "以太坊前缀",
+			opts: VanityOptions{Chain: ChainEthereum, Prefix: "0xab"},
+			want: first(func(i int) bool { return strings.HasPrefix(strings.ToLower(eth[i].Address.Hex()), "0xab") }),
+		},
+		{
+			name: "以太坊区分大小写后缀",
+			opts: VanityOptions{Chain: ChainEthereum, Suffix: "F", CaseSensitive: true},
+			want: first(func(i int) bool { return strings.HasSuffix(eth[i].Address.Hex(), "F") }),
+		},
+		{
+			name: "Solana前缀",
+			opts: VanityOptions{Chain: ChainSolana, Prefix: "s"},
+			want: first(func(i int) bool { return strings.HasPrefix(strings.ToLower(sol[i].Address()), "s") }),
+		},
+		{
+			name: "从起始序号之后查找",
+			opts: VanityOptions{Chain: ChainSolana, Prefix: "s", Start: 10},
+			want: first(func(i int) bool { return i >= 10 && strings.HasPrefix(strings.ToLower(sol[i].Address()), "s") }),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 不同并行数得到同一个结果：范围内最小的匹配序号
+			for _, workers := range []int{1, 4} {
+				opts := tt.opts
+				opts.Workers = workers
+				m, err := SearchVanity(context.Background(), seed, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if int(m.Index) != tt.want {
+					t.Fatalf("%d个worker找到序号 %d，期望 %d", workers, m.Index, tt.want)
+				}
+				switch tt.opts.Chain {
+				case ChainEthereum:
+					if m.Eth == nil || m.Address != eth[tt.want].Address.Hex() {
+						t.Fatalf("结果 %+v", m)
+					}
+				case ChainSolana:
+					if m.Sol == nil || m.Address != sol[tt.want].Address() {
+						t.Fatalf("结果 %+v", m)
+					}
+				}
+				if m.Checked < uint64(tt.want-int(tt.opts.Start)+1) {
+					t.Fatalf("已检查 %d 个，少于找到的序号", m.Checked)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchVanityErrors(t *testing.T) {
+	seed, err := Seed(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 范围内没有匹配
+	_, err = SearchVanity(context.Background(), seed, VanityOptions{Chain: ChainEthereum, Prefix: "ffffffff", MaxIndex: 50})
+	if !errors.Is(err, ErrVanityNotFound) {
+		t.Fatalf("err = %v，期望没有找到", err)
+	}
+	// 起始序号超过最大序号
+	if _, err := SearchVanity(context.Background(), seed, VanityOptions{Chain: ChainSolana, Prefix: "s", Start: 10, MaxIndex: 5}); err == nil {
+		t.Fatal("起始序号超过最大序号应报错")
+	}
+	// 前缀无效时不开始搜索
+	if _, err := SearchVanity(context.Background(), seed, VanityOptions{Chain: ChainSolana, Prefix: "0"}); err == nil {
+		t.Fatal("无效前缀应报错")
+	}
+	// ctx取消时返回ctx的错误
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SearchVanity(ctx, seed, VanityOptions{Chain: ChainEthereum, Prefix: "ffffffffff"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ctx取消后 err = %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"DApp/counter"
 	"DApp/pkg/config"
+	"DApp/pkg/gas"
+	"DApp/pkg/provider"
 	"context"
 	"crypto/ecdsa"
 	"fmt"
@@ -11,23 +13,22 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 func main() {
 	// 1. 连接到Sepolia测试网络
 	// 替换为你的Infura Sepolia API URL
 	config.InitConfig("etc/config.yaml")
-	apIkey := config.GetConfig().Server.APIkey
-	infuraURL := "https://sepolia.infura.io/v3/" + apIkey
 
-	// 连接到Sepolia测试网络
-	client, err := ethclient.Dial(infuraURL)
+	// 连接到Sepolia测试网络，所有RPC请求经过限流器
+	client, limiter, err := provider.DialSepolia(context.Background())
 	if err != nil {
 		log.Fatalf("无法连接到以太坊客户端: %v", err)
 	}
 	defer client.Close()
+	defer func() { fmt.Println(limiter.Stats()) }()
 
 	fmt.Println("成功连接到Sepolia测试网络")
 
@@ -66,25 +67,35 @@ func main() {
 		log.Fatalf("创建交易签名器失败: %v", err)
 	}
 	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)     // 不需要发送以太币
-	auth.GasLimit = uint64(300000) // 足够的Gas限制
+	auth.Value = big.NewInt(0) // 不需要发送以太币
 	auth.GasPrice = gasPrice
+	// Gas上限不再写死，每次写操作前由gas.Transact模拟执行后按安全系数计算
 
 	// 3. 部署合约（如果尚未部署）
 	var contractAddress string
 	if contractAddress == "" {
 		// 部署新合约
-		address, tx, _, err := counter.DeployCounter(auth, client)
+		var address common.Address
+		tx, report, err := gas.Transact(context.Background(), client, auth, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			addr, tx, _, err := counter.DeployCounter(opts, client)
+			address = addr
+			return tx, err
+		})
 		if err != nil {
 			log.Fatalf("部署合约失败: %v", err)
 		}
+		fmt.Println(report)
 
 		contractAddress = address.Hex()
 		fmt.Printf("合约部署中，交易哈希: %s\n", tx.Hash().Hex())
 		fmt.Printf("合约地址: %s\n", contractAddress)
 		//fmt.Printf("counter: %s\n", instance)
 
-		// 等待部署完成（实际应用中需要轮询确认）
+		// 等待部署完成，之后对合约的模拟执行才有意义
+		if _, err := bind.WaitDeployed(context.Background(), client, tx); err != nil {
+			log.Fatalf("等待合约部署失败: %v", err)
+		}
+		auth.Nonce.Add(auth.Nonce, big.NewInt(1))
 	}
 
 	// 4. 连接到已部署的合约
@@ -101,10 +112,11 @@ func main() {
 	fmt.Printf("当前计数: %d\n", count)
 
 	// 6. 调用合约的写方法（increment）
-	tx, err := contract.Increment(auth)
+	tx, report, err := gas.Transact(context.Background(), client, auth, contract.Increment)
 	if err != nil {
 		log.Fatalf("调用increment失败: %v", err)
 	}
+	fmt.Println(report)
 	fmt.Printf("增加计数的交易已发送，哈希: %s\n", tx.Hash().Hex())
 
 	// 等待交易确认后再次查询（实际应用中需要轮询确认）
@@ -0,0 +1,142 @@
+package nft
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// IPFSGateway 解析ipfs://地址时使用的HTTP网关
+var IPFSGateway = "https://ipfs.io/ipfs/"
+
+// maxMetadataSize 元数据JSON的最大字节数
+const maxMetadataSize = 1 << 20
+
+// Attribute 元数据中的属性，value可能是字符串或数字
+type Attribute struct {
+	TraitType   string `json:"trait_type"`
+	Value       any    `json:"value"`
+	DisplayType string `json:"display_type,omitempty"`
+}
+
+// Metadata ERC-721 Metadata JSON Schema，Raw保留原始JSON以便读取非标准字段
+type Metadata struct {
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	Image        string          `json:"image"`
+	ExternalURL  string          `json:"external_url,omitempty"`
+	AnimationURL string          `json:"animation_url,omitempty"`
+	Attributes   []Attribute     `json:"attributes,omitempty"`
+	Raw          json.RawMessage `json:"-"`
+}
+
+// ParseMetadata 解析元数据JSON
+func ParseMetadata(data []byte) (*Metadata, error) {
+	var m Metadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("解析元数据失败: %w", err)
+	}
+	m.Raw = append(json.RawMessage(nil), data...)
+	return &m, nil
+}
+
+// FetchMetadata 读取tokenURI指向的元数据，支持http(s)、ipfs://、ar://和data: URI
+func FetchMetadata(ctx context.Context, client *http.Client, uri string) (*Metadata, error) {
+	if strings.HasPrefix(uri, "data:") {
+		mediaType, data, err := parseDataURI(uri)
+		if err != nil {
+			return nil, err
+		}
+		if !isJSONMediaType(mediaType) {
+			return nil, fmt.Errorf("data URI类型 %q 不是JSON元数据", mediaType)
+		}
+		return ParseMetadata(data)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ResolveURI(uri), nil)
+	if err != nil {
+		return nil, fmt.Errorf("无效的元数据地址 %q: %w", uri, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求元数据失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("请求元数据失败: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取元数据失败: %w", err)
+	}
+	if len(data) > maxMetadataSize {
+		return nil, fmt.Errorf("元数据超过%d字节", maxMetadataSize)
+	}
+	return ParseMetadata(data)
+}
+
+// ResolveURI 把ipfs://、ar://地址转换为HTTP网关地址，其他地址原样返回
+func ResolveURI(uri string) string {
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		path := strings.TrimPrefix(uri, "ipfs://")
+		path = strings.TrimPrefix(path, "ipfs/")
+		return IPFSGateway + path
+	case strings.HasPrefix(uri, "ar://"):
+		return "https://arweave.net/" + strings.TrimPrefix(uri, "ar://")
+	default:
+		return uri
+	}
+}
+
+// DecodeDataURI 解码RFC 2397 data: URI，如 data:application/json;base64,eyJ...
+func DecodeDataURI(uri string) ([]byte, error) {
+	_, data, err := parseDataURI(uri)
+	return data, err
+}
+
+// parseDataURI 解码data: URI，返回小写的媒体类型（不含参数）和内容
+func parseDataURI(uri string) (string, []byte, error) {
+	rest, ok := strings.CutPrefix(uri, "data:")
+	if !ok {
+		return "", nil, fmt.Errorf("不是data URI: %.32q", uri)
+	}
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", nil, fmt.Errorf("data URI缺少逗号分隔符")
+	}
+	header = strings.ToLower(header)
+	mediaType, _, _ := strings.Cut(header, ";")
+	if strings.HasSuffix(header, ";base64") {
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			// 部分合约生成的base64不带填充
+			if data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "=")); err != nil {
+				return "", nil, fmt.Errorf("data URI base64解码失败: %w", err)
+			}
+		}
+		return mediaType, data, nil
+	}
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("data URI解码失败: %w", err)
+	}
+	return mediaType, []byte(data), nil
+}
+
+// isJSONMediaType 元数据允许的data URI类型。省略类型时按RFC 2397视为text/plain，
+// 部分合约也会用text/plain返回JSON
+func isJSONMediaType(mediaType string) bool {
+	switch mediaType {
+	case "", "application/json", "text/json", "text/plain":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,109 @@
+package nft
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testMetadata = `{"name":"Test #1","image":"ipfs://Qm/1.png","attributes":[{"trait_type":"Level","value":5}]}`
+
+func TestDecodeDataURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    string
+		wantErr string
+	}{
+		{name: "base64", uri: "data:application/json;base64,eyJhIjoxfQ==", want: `{"a":1}`},
+		{name: "base64无填充", uri: "data:application/json;base64,eyJhIjoxfQ", want: `{"a":1}`},
+		{name: "base64大写标记", uri: "data:application/json;BASE64,eyJhIjoxfQ==", want: `{"a":1}`},
+		{name: "百分号编码", uri: "data:application/json,%7B%22a%22%3A%201%7D", want: `{"a": 1}`},
+		{name: "带charset参数", uri: "data:application/json;charset=utf-8,{\"a\":1}", want: `{"a":1}`},
+		{name: "非法base64", uri: "data:application/json;base64,!!!", wantErr: "base64解码失败"},
+		{name: "非法百分号编码", uri: "data:application/json,%zz", wantErr: "解码失败"},
+		{name: "缺少逗号", uri: "data:application/json;base64", wantErr: "缺少逗号"},
+		{name: "不是data URI", uri: "https://example.com/1.json", wantErr: "不是data URI"},
+	}
+	for _, tt := range tests {
+		got, err := DecodeDataURI(tt.uri)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: err = %v，期望包含 %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s: DecodeDataURI = %q, %v，期望 %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestFetchMetadata(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/ipfs/Qm/1.json", "/1.json":
+			w.Write([]byte(testMetadata))
+		case "/big.json":
+			w.Write([]byte(`{"name":"` + strings.Repeat("x", maxMetadataSize) + `"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	gateway := IPFSGateway
+	IPFSGateway = srv.URL + "/ipfs/"
+	defer func() { IPFSGateway = gateway }()
+
+	tests := []struct {
+		name     string
+		uri      string
+		wantName string
+		wantPath string
+		wantErr  string
+	}{
+		{name: "ipfs地址", uri: "ipfs://Qm/1.json", wantName: "Test #1", wantPath: "/ipfs/Qm/1.json"},
+		{name: "ipfs地址带ipfs前缀", uri: "ipfs://ipfs/Qm/1.json", wantName: "Test #1", wantPath: "/ipfs/Qm/1.json"},
+		{name: "http地址", uri: srv.URL + "/1.json", wantName: "Test #1", wantPath: "/1.json"},
+		{name: "404", uri: srv.URL + "/missing.json", wantErr: "404"},
+		{name: "超过大小限制", uri: srv.URL + "/big.json", wantErr: "超过"},
+		{name: "data URI base64", uri: "data:application/json;base64,eyJuYW1lIjoiT25jaGFpbiJ9", wantName: "Onchain"},
+		{name: "data URI百分号编码", uri: "data:application/json,%7B%22name%22%3A%22Onchain%22%7D", wantName: "Onchain"},
+		{name: "data URI省略类型", uri: `data:,{"name":"Onchain"}`, wantName: "Onchain"},
+		{name: "data URI是图片", uri: "data:image/svg+xml;base64,PHN2Zy8+", wantErr: "不是JSON元数据"},
+		{name: "data URI不是JSON", uri: "data:text/plain,hello", wantErr: "解析元数据失败"},
+	}
+	for _, tt := range tests {
+		paths = nil
+		m, err := FetchMetadata(context.Background(), srv.Client(), tt.uri)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: err = %v，期望包含 %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if m.Name != tt.wantName {
+			t.Errorf("%s: name = %q，期望 %q", tt.name, m.Name, tt.wantName)
+		}
+		// data URI不应发出HTTP请求
+		if (tt.wantPath == "" && len(paths) != 0) || (tt.wantPath != "" && (len(paths) != 1 || paths[0] != tt.wantPath)) {
+			t.Errorf("%s: 请求路径 %v，期望 %q", tt.name, paths, tt.wantPath)
+		}
+	}
+
+	m, err := ParseMetadata([]byte(testMetadata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Attributes) != 1 || m.Attributes[0].Value != float64(5) || string(m.Raw) != testMetadata {
+		t.Fatalf("解析结果: %+v", m)
+	}
+}
@@ -0,0 +1,201 @@
+package nft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+
+	"DApp/erc721"
+	"DApp/pkg/gas"
+	"DApp/pkg/ratelimit"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ERC-165接口ID
+var (
+	InterfaceERC721         = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	InterfaceERC721Metadata = [4]byte{0x5b, 0x5e, 0x13, 0x9f}
+)
+
+// Backend 查询和发送交易所需的链上接口
+type Backend interface {
+	bind.ContractBackend
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Collection 一个ERC-721合约
+type Collection struct {
+	Address common.Address
+	Name    string
+	Symbol  string
+
+	backend  Backend
+	contract *erc721.ERC721
+}
+
+// Load 绑定合约并读取name/symbol，合约不支持ERC-721接口时返回错误
+func Load(ctx context.Context, address common.Address, backend Backend) (*Collection, error) {
+	contract, err := erc721.NewERC721(address, backend)
+	if err != nil {
+		return nil, fmt.Errorf("绑定ERC-721合约失败: %w", err)
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	ok, err := contract.SupportsInterface(opts, InterfaceERC721)
+	if err != nil {
+		return nil, fmt.Errorf("调用supportsInterface失败: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s 不是ERC-721合约", address.Hex())
+	}
+	c := &Collection{Address: address, backend: backend, contract: contract}
+	// name和symbol属于可选的Metadata扩展
+	if c.Name, err = contract.Name(opts); err != nil {
+		c.Name = ""
+	}
+	if c.Symbol, err = contract.Symbol(opts); err != nil {
+		c.Symbol = ""
+	}
+	return c, nil
+}
+
+// Contract 返回底层合约绑定
+func (c *Collection) Contract() *erc721.ERC721 {
+	return c.contract
+}
+
+// OwnerOf 查询token的持有者
+func (c *Collection) OwnerOf(ctx context.Context, tokenID *big.Int) (common.Address, error) {
+	owner, err := c.contract.OwnerOf(&bind.CallOpts{Context: ctx}, tokenID)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("查询token %s 持有者失败: %w", tokenID, err)
+	}
+	return owner, nil
+}
+
+// BalanceOf 查询地址持有的token数量
+func (c *Collection) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+	balance, err := c.contract.BalanceOf(&bind.CallOpts{Context: ctx}, owner)
+	if err != nil {
+		return nil, fmt.Errorf("查询余额失败: %w", err)
+	}
+	return balance, nil
+}
+
+// TokenURI 查询token的元数据地址
+func (c *Collection) TokenURI(ctx context.Context, tokenID *big.Int) (string, error) {
+	uri, err := c.contract.TokenURI(&bind.CallOpts{Context: ctx}, tokenID)
+	if err != nil {
+		return "", fmt.Errorf("查询token %s 的tokenURI失败: %w", tokenID, err)
+	}
+	return uri, nil
+}
+
+// Metadata 读取并解析token的元数据
+func (c *Collection) Metadata(ctx context.Context, tokenID *big.Int) (*Metadata, error) {
+	uri, err := c.TokenURI(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	return FetchMetadata(ctx, nil, uri)
+}
+
+// TokensOf 从fromBlock开始扫描转入owner的Transfer日志，再用ownerOf确认当前仍由owner持有，
+// 返回按tokenId排序的结果。适用于没有实现Enumerable扩展的合约
+func (c *Collection) TokensOf(ctx context.Context, owner common.Address, fromBlock uint64) ([]*big.Int, error) {
+	latest, err := c.backend.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取最新区块失败: %w", err)
+	}
+	candidates := make(map[string]*big.Int)
+	if err := c.scanTransfers(ctx, owner, fromBlock, latest, candidates); err != nil {
+		return nil, err
+	}
+	var tokens []*big.Int
+	for _, id := range candidates {
+		current, err := c.OwnerOf(ctx, id)
+		if err != nil {
+			if gas.IsRevert(err) {
+				continue // 已销毁，ownerOf回滚
+			}
+			return nil, err
+		}
+		if current == owner {
+			tokens = append(tokens, id)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Cmp(tokens[j]) < 0 })
+	return tokens, nil
+}
+
+// scanTransfers 查询[from, to]区间内转入owner的日志，节点提示结果过多时二分区间重试
+func (c *Collection) scanTransfers(ctx context.Context, owner common.Address, from, to uint64, out map[string]*big.Int) error {
+	it, err := c.contract.FilterTransfer(&bind.FilterOpts{Start: from, End: &to, Context: ctx}, nil, []common.Address{owner}, nil)
+	if err == nil {
+		defer it.Close()
+		for it.Next() {
+			out[it.Event.TokenId.String()] = it.Event.TokenId
+		}
+		err = it.Error()
+	}
+	if err == nil {
+		return nil
+	}
+	if from >= to || !isRangeTooLarge(err) {
+		return fmt.Errorf("查询Transfer日志失败(区块 %d-%d): %w", from, to, err)
+	}
+	mid := from + (to-from)/2
+	if err := c.scanTransfers(ctx, owner, from, mid, out); err != nil {
+		return err
+	}
+	return c.scanTransfers(ctx, owner, mid+1, to, out)
+}
+
+// isRangeTooLarge 判断eth_getLogs是否因为区间或结果过大被拒绝。限流和额度用完的错误
+// 不属于此类，二分区间只会发出更多请求
+func isRangeTooLarge(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.Is(err, ratelimit.ErrQuotaExceeded) ||
+		(errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{
+		"query returned more than",   // Infura: query returned more than 10000 results
+		"log response size exceeded", // Alchemy
+		"block range",                // block range too large、exceed maximum block range等
+		"range is too large",
+		"eth_getlogs is limited to", // QuickNode: eth_getLogs is limited to a 10,000 range
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// SafeTransferFrom 把token从auth.From转给to，发送前确认auth.From是当前持有者并模拟执行，
+// 接收方合约未实现onERC721Received时会在模拟阶段给出回滚原因
+func (c *Collection) SafeTransferFrom(ctx context.Context, auth *bind.TransactOpts, to common.Address, tokenID *big.Int) (*types.Transaction, *gas.Report, error) {
+	owner, err := c.OwnerOf(ctx, tokenID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if owner != auth.From {
+		return nil, nil, fmt.Errorf("token %s 的持有者是 %s，不是 %s", tokenID, owner.Hex(), auth.From.Hex())
+	}
+	tx, report, err := gas.Transact(ctx, c.backend, auth, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.SafeTransferFrom(opts, auth.From, to, tokenID)
+	})
+	if err != nil {
+		return nil, report, fmt.Errorf("safeTransferFrom失败: %w", err)
+	}
+	return tx, report, nil
+}
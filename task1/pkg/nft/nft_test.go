@@ -0,0 +1,290 @@
+package nft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"DApp/erc20"
+	"DApp/erc721"
+	"DApp/internal/testchain"
+	"DApp/pkg/config"
+	"DApp/pkg/gas"
+	"DApp/pkg/ratelimit"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testChain 部署了MockERC721的模拟链，alice持有token 1、2、3
+type testChain struct {
+	*testchain.Chain
+	mock    *erc721.MockERC721
+	address common.Address
+}
+
+func newTestChain(t *testing.T) *testChain {
+	t.Helper()
+	c := &testChain{Chain: testchain.New(t)}
+	address, tx, mock, err := erc721.DeployMockERC721(c.Alice, c.Client, "Test NFT", "TNFT", "ipfs://base/")
+	c.Mined(t, tx, err)
+	c.mock, c.address = mock, address
+	for id := int64(1); id <= 3; id++ {
+		tx, err := mock.Mint(c.Alice, c.Alice.From, big.NewInt(id))
+		c.Mined(t, tx, err)
+	}
+	return c
+}
+
+func (c *testChain) load(t *testing.T, backend Backend) *Collection {
+	t.Helper()
+	col, err := Load(context.Background(), c.address, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return col
+}
+
+func TestLoadAndMint(t *testing.T) {
+	ctx := context.Background()
+	c := newTestChain(t)
+	col := c.load(t, c.Client)
+	if col.Name != "Test NFT" || col.Symbol != "TNFT" {
+		t.Fatalf("元数据 = %q %q", col.Name, col.Symbol)
+	}
+
+	owner, err := col.OwnerOf(ctx, big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner != c.Alice.From {
+		t.Fatalf("token 2 持有者 = %s", owner.Hex())
+	}
+	balance, err := col.BalanceOf(ctx, c.Alice.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Int64() != 3 {
+		t.Fatalf("余额 = %s", balance)
+	}
+	uri, err := col.TokenURI(ctx, big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri != "ipfs://base/3" {
+		t.Fatalf("tokenURI = %q", uri)
+	}
+	if _, err := col.OwnerOf(ctx, big.NewInt(99)); !gas.IsRevert(err) {
+		t.Fatalf("不存在的token应回滚，err = %v", err)
+	}
+
+	// 不是ERC-721的合约
+	erc20Address, tx, _, err := erc20.DeployMockERC20(c.Alice, c.Client, "Test USD", "TUSD", 6)
+	c.Mined(t, tx, err)
+	if _, err := Load(ctx, erc20Address, c.Client); err == nil {
+		t.Fatal("ERC-20合约不应通过ERC-721检查")
+	}
+}
+
+func TestSafeTransferFrom(t *testing.T) {
+	ctx := context.Background()
+	c := newTestChain(t)
+	col := c.load(t, c.Client)
+
+	tx, report, err := col.SafeTransferFrom(ctx, c.Alice, c.Bob.From, big.NewInt(2))
+	c.Mined(t, tx, err)
+	if report.GasLimit < report.Estimate {
+		t.Fatalf("Gas上限 %d 小于预估 %d", report.GasLimit, report.Estimate)
+	}
+	owner, err := col.OwnerOf(ctx, big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner != c.Bob.From {
+		t.Fatalf("token 2 持有者 = %s，期望bob", owner.Hex())
+	}
+
+	// 不是持有者时在发送前拦截
+	if _, _, err := col.SafeTransferFrom(ctx, c.Alice, c.Bob.From, big.NewInt(2)); err == nil {
+		t.Fatal("非持有者转出应报错")
+	}
+
+	// 接收方合约没有onERC721Received，模拟阶段回滚，交易不会发送
+	receiver, tx, _, err := erc20.DeployMockERC20(c.Alice, c.Client, "Test USD", "TUSD", 6)
+	c.Mined(t, tx, err)
+	if _, _, err := col.SafeTransferFrom(ctx, c.Alice, receiver, big.NewInt(3)); !errors.Is(err, gas.ErrReverted) {
+		t.Fatalf("转给非接收方合约 err = %v，期望回滚", err)
+	}
+	owner, err = col.OwnerOf(ctx, big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner != c.Alice.From {
+		t.Fatalf("回滚后token 3 持有者 = %s，期望alice", owner.Hex())
+	}
+}
+
+func TestTokensOf(t *testing.T) {
+	ctx := context.Background()
+	c := newTestChain(t)
+	col := c.load(t, c.Client)
+
+	tx, err := col.Contract().TransferFrom(c.Alice, c.Alice.From, c.Bob.From, big.NewInt(2))
+	c.Mined(t, tx, err)
+	tx, err = c.mock.Mint(c.Alice, c.Bob.From, big.NewInt(10))
+	c.Mined(t, tx, err)
+
+	tests := []struct {
+		owner common.Address
+		want  []int64
+	}{
+		{c.Alice.From, []int64{1, 3}},
+		{c.Bob.From, []int64{2, 10}},
+	}
+	for _, tt := range tests {
+		tokens, err := col.TokensOf(ctx, tt.owner, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tokens) != len(tt.want) {
+			t.Fatalf("%s 持有 %v，期望 %v", tt.owner.Hex(), tokens, tt.want)
+		}
+		for i, id := range tokens {
+			if id.Int64() != tt.want[i] {
+				t.Fatalf("%s 持有 %v，期望 %v", tt.owner.Hex(), tokens, tt.want)
+			}
+		}
+	}
+}
+
+// failingCalls 让Load之后的ownerOf调用返回指定错误
+type failingCalls struct {
+	Backend
+	err error
+}
+
+func (f *failingCalls) CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	return nil, f.err
+}
+
+// callRevert 节点对eth_call回滚返回的错误：code 3，data为回滚数据
+type callRevert struct{}
+
+func (callRevert) Error() string          { return "execution reverted: ERC721: invalid token ID" }
+func (callRevert) ErrorCode() int         { return 3 }
+func (callRevert) ErrorData() interface{} { return "0x" }
+
+func TestTokensOfErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newTestChain(t)
+	col := c.load(t, c.Client)
+
+	// ownerOf回滚视为已销毁，跳过
+	col.backend = &failingCalls{Backend: c.Client, err: callRevert{}}
+	col.contract, _ = erc721.NewERC721(c.address, col.backend)
+	tokens, err := col.TokensOf(ctx, c.Alice.From, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 0 {
+		t.Fatalf("ownerOf全部回滚时 tokens = %v", tokens)
+	}
+
+	// 其他错误不能当作已销毁，结果会不完整
+	col.backend = &failingCalls{Backend: c.Client, err: ratelimit.ErrQuotaExceeded}
+	col.contract, _ = erc721.NewERC721(c.address, col.backend)
+	if _, err := col.TokensOf(ctx, c.Alice.From, 0); !errors.Is(err, ratelimit.ErrQuotaExceeded) {
+		t.Fatalf("ownerOf失败时 err = %v，期望返回错误", err)
+	}
+}
+
+// getLogsBackend 查询日志走fake节点，其余调用走模拟链
+type getLogsBackend struct {
+	Backend
+	logs *ethclient.Client
+}
+
+func (b *getLogsBackend) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return b.logs.FilterLogs(ctx, q)
+}
+
+func TestTokensOfRateLimited(t *testing.T) {
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	// 额度只够1次、eth_getLogs需要2次时，请求不会发到服务商
+	quota, err := ratelimit.New("test", config.RateLimitConfig{DailyQuota: 1, MethodCost: map[string]int{"eth_getLogs": 2}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		transport    http.RoundTripper
+		wantRequests int64
+		check        func(error) bool
+	}{
+		{name: "429", transport: http.DefaultTransport, wantRequests: 1, check: func(err error) bool {
+			var httpErr rpc.HTTPError
+			return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests
+		}},
+		{name: "额度用完", transport: quota, check: func(err error) bool { return errors.Is(err, ratelimit.ErrQuotaExceeded) }},
+	}
+	ctx := context.Background()
+	c := newTestChain(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			rc, err := rpc.DialOptions(ctx, srv.URL, rpc.WithHTTPClient(&http.Client{Transport: tt.transport}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rc.Close()
+			col := c.load(t, c.Client)
+			col.backend = &getLogsBackend{Backend: c.Client, logs: ethclient.NewClient(rc)}
+			col.contract, _ = erc721.NewERC721(c.address, col.backend)
+
+			// 不能当作区间过大去二分，否则会成倍增加请求
+			_, err = col.TokensOf(ctx, c.Alice.From, 0)
+			if !tt.check(err) {
+				t.Fatalf("err = %v", err)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Fatalf("服务商收到 %d 个请求，期望 %d 个", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestIsRangeTooLarge(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("query returned more than 10000 results"), true},
+		{errors.New("Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"), true},
+		{errors.New("block range is too wide"), true},
+		{errors.New("exceed maximum block range: 5000"), true},
+		{errors.New("eth_getLogs is limited to a 10,000 range"), true},
+		{rpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}, false},
+		{fmt.Errorf("请求失败: %w", ratelimit.ErrQuotaExceeded), false},
+		{errors.New("daily request count exceeded, request rate limited"), false},
+		{errors.New("project ID request rate exceeded: too many requests"), false},
+	}
+	for _, tt := range tests {
+		if got := isRangeTooLarge(tt.err); got != tt.want {
+			t.Errorf("isRangeTooLarge(%q) = %v，期望 %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"DApp/pkg/units"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Payment CSV中的一行转账
+type Payment struct {
+	Row     int // CSV中的行号（从1开始，含表头）
+	Address common.Address
+	Amount  *big.Int // wei
+	Memo    string
+}
+
+// ReadPayments 读取并校验CSV（address,amount,memo），amount以ETH为单位。
+// 第一行若不是合法地址则视为表头跳过；所有错误汇总后一起返回
+func ReadPayments(path string) ([]Payment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开CSV失败: %w", err)
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	var (
+		payments []Payment
+		errs     []error
+		seen     = make(map[common.Address]int)
+	)
+	for row := 1; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取CSV失败: %w", err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if row == 1 && len(record) > 0 && !common.IsHexAddress(strings.TrimSpace(record[0])) {
+			continue // 表头
+		}
+		p, err := parseRecord(row, record)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if first, ok := seen[p.Address]; ok {
+			errs = append(errs, fmt.Errorf("第%d行: 地址 %s 与第%d行重复", row, p.Address.Hex(), first))
+			continue
+		}
+		seen[p.Address] = row
+		payments = append(payments, p)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(payments) == 0 {
+		return nil, fmt.Errorf("CSV中没有转账记录")
+	}
+	return payments, nil
+}
+
+func parseRecord(row int, record []string) (Payment, error) {
+	if len(record) < 2 || len(record) > 3 {
+		return Payment{}, fmt.Errorf("第%d行: 需要 address,amount[,memo] 共2~3列，实际%d列", row, len(record))
+	}
+	addr := strings.TrimSpace(record[0])
+	if !common.IsHexAddress(addr) {
+		return Payment{}, fmt.Errorf("第%d行: 无效地址 %q", row, addr)
+	}
+	address := common.HexToAddress(addr)
+	if err := checkChecksum(addr, address); err != nil {
+		return Payment{}, fmt.Errorf("第%d行: %w", row, err)
+	}
+	if address == (common.Address{}) {
+		return Payment{}, fmt.Errorf("第%d行: 不能转账到零地址", row)
+	}
+	amount, err := units.ParseEther(record[1])
+	if err != nil {
+		return Payment{}, fmt.Errorf("第%d行: %w", row, err)
+	}
+	if amount.Sign() == 0 {
+		return Payment{}, fmt.Errorf("第%d行: 金额必须大于0", row)
+	}
+	p := Payment{Row: row, Address: address, Amount: amount}
+	if len(record) == 3 {
+		p.Memo = strings.TrimSpace(record[2])
+	}
+	return p, nil
+}
+
+// checkChecksum 大小写混合的地址必须符合EIP-55校验和，全小写或全大写的地址不含校验信息
+func checkChecksum(raw string, address common.Address) error {
+	hex := strings.TrimPrefix(strings.TrimPrefix(raw, "0x"), "0X")
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return nil
+	}
+	if "0x"+hex != address.Hex() {
+		return fmt.Errorf("地址 %s 校验和错误，正确应为 %s", raw, address.Hex())
+	}
+	return nil
+}
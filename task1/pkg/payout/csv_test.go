@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 测试用地址，Hex()为EIP-55校验和格式
+var (
+	alice = common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	bob   = common.HexToAddress("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359")
+)
+
+func writeCSV(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "payouts.csv")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadPayments(t *testing.T) {
+	path := writeCSV(t, "address,amount,memo\n"+
+		"# 注释行\n"+
+		alice.Hex()+",1.5,工资\n"+
+		"\n"+
+		strings.ToLower(bob.Hex())+", 0.000000000000000001\n")
+	payments, err := ReadPayments(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 2 {
+		t.Fatalf("读到 %d 笔转账: %+v", len(payments), payments)
+	}
+	if p := payments[0]; p.Row != 2 || p.Address != alice || p.Amount.String() != "1500000000000000000" || p.Memo != "工资" {
+		t.Fatalf("第一笔: %+v", p)
+	}
+	if p := payments[1]; p.Address != bob || p.Amount.String() != "1" || p.Memo != "" {
+		t.Fatalf("第二笔: %+v", p)
+	}
+}
+
+func TestReadPaymentsErrors(t *testing.T) {
+	// 把alice地址中第一个小写字母改成大写，校验和不再正确
+	badChecksum := strings.Replace(alice.Hex(), "a", "A", 1)
+	tests := []struct {
+		name string
+		csv  string
+		want []string // 错误中应包含的内容
+	}{
+		{name: "校验和错误", csv: badChecksum + ",1\n", want: []string{"第1行", "校验和错误", alice.Hex()}},
+		{name: "无效地址", csv: alice.Hex() + ",1\n0x1234,1\n", want: []string{"第2行", "无效地址"}},
+		{name: "零地址", csv: "0x0000000000000000000000000000000000000000,1\n", want: []string{"零地址"}},
+		{name: "金额为0", csv: alice.Hex() + ",0\n", want: []string{"金额必须大于0"}},
+		{name: "金额无效", csv: alice.Hex() + ",abc\n", want: []string{"第1行"}},
+		{name: "列数错误", csv: alice.Hex() + "\n", want: []string{"2~3列"}},
+		{name: "地址重复", csv: alice.Hex() + ",1\n" + strings.ToLower(alice.Hex()) + ",2\n", want: []string{"第2行", "与第1行重复"}},
+		{name: "没有记录", csv: "address,amount\n", want: []string{"没有转账记录"}},
+		// 所有错误汇总后一起返回
+		{name: "多处错误", csv: alice.Hex() + ",0\n" + bob.Hex() + ",-1\n" + badChecksum + ",1\n", want: []string{"第1行", "第2行", "第3行"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadPayments(writeCSV(t, tt.csv))
+			if err == nil {
+				t.Fatal("期望返回错误")
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Fatalf("错误 %q 中没有 %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckChecksum(t *testing.T) {
+	hex := strings.TrimPrefix(alice.Hex(), "0x")
+	tests := []struct {
+		raw     string
+		wantErr bool
+	}{
+		{raw: alice.Hex()},
+		{raw: "0x" + strings.ToLower(hex)},
+		{raw: "0x" + strings.ToUpper(hex)},
+		{raw: "0X" + hex},
+		{raw: strings.Replace(alice.Hex(), "a", "A", 1), wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := checkChecksum(tt.raw, alice); (err != nil) != tt.wantErr {
+			t.Errorf("checkChecksum(%s) err = %v", tt.raw, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 账本中的转账状态
+const (
+	StatusSigned    = "signed"    // 已签名，广播前写入，崩溃后用同一笔交易重发，不会重复付款
+	StatusSent      = "sent"      // 已广播
+	StatusConfirmed = "confirmed" // 已上链且执行成功
+	StatusFailed    = "failed"    // 已上链但执行失败
+	StatusDropped   = "dropped"   // nonce被其他交易占用，转账未执行，重新运行时用新的nonce重发
+)
+
+// Entry 账本记录，每次状态变化追加一行JSON
+type Entry struct {
+	Row     int            `json:"row"`
+	Address common.Address `json:"address"`
+	Amount  string         `json:"amount"` // wei
+	Memo    string         `json:"memo,omitempty"`
+	Nonce   uint64         `json:"nonce"`
+	TxHash  common.Hash    `json:"tx_hash"`
+	RawTx   string         `json:"raw_tx,omitempty"` // 仅signed状态记录，用于恢复时重发
+	Status  string         `json:"status"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// Ledger 追加写入的转账结果账本，按地址保存每笔转账的最新状态
+type Ledger struct {
+	file   *os.File
+	latest map[common.Address]Entry
+}
+
+// OpenLedger 打开（或创建）账本并加载已有记录
+func OpenLedger(path string) (*Ledger, error) {
+	l := &Ledger{latest: make(map[common.Address]Entry)}
+	if err := l.load(path); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("打开账本失败: %w", err)
+	}
+	l.file = file
+	return l, nil
+}
+
+func (l *Ledger) load(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取账本失败: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// 最后一行可能因崩溃只写了一半
+			return fmt.Errorf("账本第%d行损坏: %w", line, err)
+		}
+		if prev, ok := l.latest[e.Address]; ok && e.RawTx == "" {
+			e.RawTx = prev.RawTx
+		}
+		l.latest[e.Address] = e
+	}
+	return scanner.Err()
+}
+
+// Lookup 返回某地址的最新记录
+func (l *Ledger) Lookup(address common.Address) (Entry, bool) {
+	e, ok := l.latest[address]
+	return e, ok
+}
+
+// MaxNonce 返回账本中用过的最大nonce
+func (l *Ledger) MaxNonce() (uint64, bool) {
+	var (
+		max   uint64
+		found bool
+	)
+	for _, e := range l.latest {
+		if !found || e.Nonce > max {
+			max, found = e.Nonce, true
+		}
+	}
+	return max, found
+}
+
+// Append 追加一条记录并立即落盘
+func (l *Ledger) Append(e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("写入账本失败: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("写入账本失败: %w", err)
+	}
+	if prev, ok := l.latest[e.Address]; ok && e.RawTx == "" {
+		e.RawTx = prev.RawTx
+	}
+	l.latest[e.Address] = e
+	return nil
+}
+
+func (l *Ledger) Close() error {
+	return l.file.Close()
+}
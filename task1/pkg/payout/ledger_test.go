@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func openLedger(t *testing.T, path string) *Ledger {
+	t.Helper()
+	l, err := OpenLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func TestLedgerResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payouts.csv.ledger.jsonl")
+	l := openLedger(t, path)
+	if _, ok := l.MaxNonce(); ok {
+		t.Fatal("空账本不应有nonce")
+	}
+	signed := Entry{Row: 2, Address: alice, Amount: "1", Nonce: 7, TxHash: common.HexToHash("0x01"), RawTx: "0xf86c", Status: StatusSigned}
+	for _, e := range []Entry{
+		signed,
+		{Row: 3, Address: bob, Amount: "2", Nonce: 8, TxHash: common.HexToHash("0x02"), RawTx: "0xf86d", Status: StatusSigned},
+	} {
+		if err := l.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// alice的交易已广播，bob的交易崩溃前只签了名
+	sent := signed
+	sent.RawTx, sent.Status = "", StatusSent
+	if err := l.Append(sent); err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	resumed := openLedger(t, path)
+	e, ok := resumed.Lookup(alice)
+	if !ok || e.Status != StatusSent || e.Nonce != 7 {
+		t.Fatalf("alice的最新记录: %+v", e)
+	}
+	// 后续记录不带RawTx时沿用签名时的交易数据
+	if e.RawTx != signed.RawTx {
+		t.Fatalf("alice的RawTx = %q", e.RawTx)
+	}
+	if e, _ := resumed.Lookup(bob); e.Status != StatusSigned || e.RawTx != "0xf86d" {
+		t.Fatalf("bob的最新记录: %+v", e)
+	}
+	if max, ok := resumed.MaxNonce(); !ok || max != 8 {
+		t.Fatalf("MaxNonce = %d", max)
+	}
+	if _, ok := resumed.Lookup(common.HexToAddress("0x03")); ok {
+		t.Fatal("不在账本中的地址")
+	}
+}
+
+func TestLedgerCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	// 崩溃时最后一行只写了一半
+	if err := os.WriteFile(path, []byte(`{"row":2,"status":"signed"}`+"\n"+`{"row":3,"sta`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenLedger(path); err == nil {
+		t.Fatal("损坏的账本应报错")
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"DApp/pkg/config"
+	"DApp/pkg/gas"
+	"DApp/pkg/provider"
+	"DApp/pkg/signer"
+	"DApp/pkg/units"
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// 批量ETH转账：读取CSV(address,amount,memo)，校验后估算总费用，确认后按顺序nonce发送，
+// 每笔转账的状态写入账本，中途崩溃后用同样的参数重新运行即可继续，不会重复付款。
+//
+//	go run ./pkg/payout -csv payouts.csv
+func main() {
+	csvPath := flag.String("csv", "", "转账CSV文件，每行 address,amount(ETH),memo")
+	ledgerPath := flag.String("ledger", "", "结果账本路径，默认为 <csv>.ledger.jsonl")
+	yes := flag.Bool("y", false, "跳过发送前的确认")
+	wait := flag.Bool("wait", true, "发送后等待交易确认")
+	flag.Parse()
+	if *csvPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *ledgerPath == "" {
+		*ledgerPath = *csvPath + ".ledger.jsonl"
+	}
+
+	config.InitConfig("etc/config.yaml")
+	ctx := context.Background()
+
+	payments, err := ReadPayments(*csvPath)
+	if err != nil {
+		log.Fatalf("CSV校验失败:\n%v", err)
+	}
+	ledger, err := OpenLedger(*ledgerPath)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	defer ledger.Close()
+
+	client, limiter, err := provider.DialSepolia(ctx)
+	if err != nil {
+		log.Fatalf("无法连接到以太坊客户端: %v", err)
+	}
+	defer client.Close()
+	defer func() { fmt.Println(limiter.Stats()) }()
+
+	s, err := signer.FromConfig()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		log.Fatalf("无法获取链ID: %v", err)
+	}
+
+	// 1. 根据账本区分已完成、需要重发和待发送的转账
+	var todo []Payment
+	for _, p := range payments {
+		e, ok := ledger.Lookup(p.Address)
+		if !ok {
+			todo = append(todo, p)
+			continue
+		}
+		if e.Amount != p.Amount.String() {
+			log.Fatalf("第%d行: 地址 %s 在账本中的金额 %s wei 与CSV中的 %s wei 不一致，请检查账本", p.Row, p.Address.Hex(), e.Amount, p.Amount)
+		}
+		switch e.Status {
+		case StatusSigned:
+			if err := rebroadcast(ctx, client, ledger, e); err != nil {
+				log.Fatalf("第%d行: 重发已签名交易失败: %v", p.Row, err)
+			}
+			if e, _ := ledger.Lookup(p.Address); e.Status == StatusDropped {
+				todo = append(todo, p)
+			}
+		case StatusDropped:
+			fmt.Printf("第%d行: 上次的交易（nonce %d）未执行，重新发送\n", p.Row, e.Nonce)
+			todo = append(todo, p)
+		case StatusSent:
+			fmt.Printf("第%d行: 已发送 %s，跳过\n", p.Row, e.TxHash.Hex())
+		default:
+			fmt.Printf("第%d行: 已%s %s，跳过\n", p.Row, e.Status, e.TxHash.Hex())
+		}
+	}
+	if len(todo) == 0 {
+		fmt.Println("没有待发送的转账")
+		if *wait {
+			waitAll(ctx, client, ledger, pendingEntries(ledger, payments))
+		}
+		return
+	}
+
+	// 2. 逐笔估算Gas并汇总总费用
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		log.Fatalf("无法获取Gas价格: %v", err)
+	}
+	gasLimits := make([]uint64, len(todo))
+	total, minFee, maxFee := new(big.Int), new(big.Int), new(big.Int)
+	fmt.Printf("%-5s %-42s %20s %8s %8s  %s\n", "行号", "地址", "金额(ETH)", "预估Gas", "Gas上限", "备注")
+	for i, p := range todo {
+		to := p.Address
+		report, err := gas.Simulate(ctx, client, ethereum.CallMsg{From: s.Address, To: &to, Value: p.Amount, GasPrice: gasPrice})
+		if err != nil {
+			log.Fatalf("第%d行: 模拟转账失败: %v", p.Row, err)
+		}
+		gasLimits[i] = report.GasLimit
+		total.Add(total, p.Amount)
+		minFee.Add(minFee, report.MinFee)
+		maxFee.Add(maxFee, report.MaxFee)
+		fmt.Printf("%-5d %-42s %20s %8d %8d  %s\n", p.Row, p.Address.Hex(), units.FormatEther(p.Amount), report.Estimate, report.GasLimit, p.Memo)
+	}
+	cost := new(big.Int).Add(total, maxFee)
+	balance, err := client.PendingBalanceAt(ctx, s.Address)
+	if err != nil {
+		log.Fatalf("无法获取余额: %v", err)
+	}
+	fmt.Printf("\n发送方: %s\n", s.Address.Hex())
+	fmt.Printf("转账笔数: %d\n", len(todo))
+	fmt.Printf("转账总额: %s ETH\n", units.FormatEther(total))
+	fmt.Printf("预计手续费: %s ~ %s ETH (Gas价格 %s gwei, 安全系数 x%.2f)\n", units.FormatEther(minFee), units.FormatEther(maxFee), units.FormatUnits(gasPrice, 9), gas.Multiplier())
+	fmt.Printf("最多花费: %s ETH\n", units.FormatEther(cost))
+	fmt.Printf("当前余额: %s ETH\n", units.FormatEther(balance))
+	if balance.Cmp(cost) < 0 {
+		log.Fatalf("余额不足，还差 %s ETH", units.FormatEther(new(big.Int).Sub(cost, balance)))
+	}
+	if !*yes && !confirm("确认发送以上转账? (yes/no): ") {
+		fmt.Println("已取消")
+		return
+	}
+
+	// 3. 按顺序nonce签名、先写账本再广播
+	nonce, err := client.PendingNonceAt(ctx, s.Address)
+	if err != nil {
+		log.Fatalf("无法获取nonce: %v", err)
+	}
+	if max, ok := ledger.MaxNonce(); ok && max+1 > nonce {
+		nonce = max + 1
+	}
+	for i, p := range todo {
+		tx := types.NewTransaction(nonce, p.Address, p.Amount, gasLimits[i], gasPrice, nil)
+		signedTx, err := s.SignTx(tx, chainID)
+		if err != nil {
+			log.Fatalf("第%d行: %v", p.Row, err)
+		}
+		raw, err := signedTx.MarshalBinary()
+		if err != nil {
+			log.Fatalf("第%d行: 序列化交易失败: %v", p.Row, err)
+		}
+		e := Entry{
+			Row:     p.Row,
+			Address: p.Address,
+			Amount:  p.Amount.String(),
+			Memo:    p.Memo,
+			Nonce:   nonce,
+			TxHash:  signedTx.Hash(),
+			RawTx:   hexutil.Encode(raw),
+			Status:  StatusSigned,
+		}
+		if err := ledger.Append(e); err != nil {
+			log.Fatalf("%v", err)
+		}
+		if err := client.SendTransaction(ctx, signedTx); err != nil {
+			log.Fatalf("第%d行: 发送交易失败: %v（账本已记录签名交易，修复后重新运行会重发同一笔交易）", p.Row, err)
+		}
+		e.RawTx, e.Status = "", StatusSent
+		if err := ledger.Append(e); err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("第%d行: 已发送 %s ETH 到 %s，nonce %d，哈希 %s\n", p.Row, units.FormatEther(p.Amount), p.Address.Hex(), nonce, signedTx.Hash().Hex())
+		nonce++
+	}
+
+	if *wait {
+		waitAll(ctx, client, ledger, pendingEntries(ledger, payments))
+	}
+}
+
+// rebroadcast 重发崩溃前已签名的交易。交易哈希不变，节点已知或已上链时视为已发送
+func rebroadcast(ctx context.Context, client *ethclient.Client, ledger *Ledger, e Entry) error {
+	raw, err := hexutil.Decode(e.RawTx)
+	if err != nil {
+		return fmt.Errorf("账本中的交易数据无效: %w", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return fmt.Errorf("账本中的交易数据无效: %w", err)
+	}
+	if sendErr := client.SendTransaction(ctx, tx); sendErr != nil {
+		if _, _, err := client.TransactionByHash(ctx, tx.Hash()); err != nil {
+			if !errors.Is(err, ethereum.NotFound) {
+				return sendErr
+			}
+			// 节点不认识这笔交易时，用账户的nonce判断它是否已被其他交易占用，不依赖各家节点不同的错误文本
+			from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+			if err != nil {
+				return fmt.Errorf("账本中的交易签名无效: %w", err)
+			}
+			next, err := client.PendingNonceAt(ctx, from)
+			if err != nil {
+				return fmt.Errorf("无法获取nonce: %w", err)
+			}
+			if next <= e.Nonce {
+				return sendErr
+			}
+			// nonce已被其他交易占用，这笔转账不会再上链，放回待发送队列用新的nonce重发
+			e.RawTx, e.Status = "", StatusDropped
+			e.Error = fmt.Sprintf("nonce %d 已被其他交易占用（当前nonce %d），转账未执行: %v", e.Nonce, next, sendErr)
+			fmt.Printf("第%d行: %s，将用新的nonce重发\n", e.Row, e.Error)
+			return ledger.Append(e)
+		}
+	}
+	e.RawTx, e.Status = "", StatusSent
+	if err := ledger.Append(e); err != nil {
+		return err
+	}
+	fmt.Printf("第%d行: 已重发 nonce %d，哈希 %s\n", e.Row, e.Nonce, tx.Hash().Hex())
+	return nil
+}
+
+// pendingEntries 返回CSV中处于已发送、尚未确认状态的记录
+func pendingEntries(ledger *Ledger, payments []Payment) []Entry {
+	var pending []Entry
+	for _, p := range payments {
+		if e, ok := ledger.Lookup(p.Address); ok && e.Status == StatusSent {
+			pending = append(pending, e)
+		}
+	}
+	return pending
+}
+
+// waitAll 等待交易上链并把最终结果写入账本
+func waitAll(ctx context.Context, client *ethclient.Client, ledger *Ledger, pending []Entry) {
+	if len(pending) == 0 {
+		return
+	}
+	fmt.Printf("等待 %d 笔交易确认...\n", len(pending))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+	var failed int
+	for _, e := range pending {
+		receipt, err := bind.WaitMinedHash(ctx, client, e.TxHash)
+		if err != nil {
+			log.Fatalf("第%d行: 等待交易 %s 确认失败: %v（重新运行可继续等待）", e.Row, e.TxHash.Hex(), err)
+		}
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			e.Status = StatusConfirmed
+		} else {
+			e.Status, e.Error = StatusFailed, "交易执行失败"
+			failed++
+		}
+		if err := ledger.Append(e); err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("第%d行: %s，区块 %d\n", e.Row, e.Status, receipt.BlockNumber.Uint64())
+	}
+	fmt.Printf("完成: 成功 %d 笔，失败 %d 笔\n", len(pending)-failed, failed)
+}
+
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"DApp/pkg/config"
+	"DApp/pkg/ratelimit"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Infura 服务商名称，对应配置文件rate_limit下的键
+const Infura = "infura"
+
+// SepoliaURL 根据配置中的APIkey拼接Infura Sepolia地址
+func SepoliaURL() string {
+	return "https://sepolia.infura.io/v3/" + config.GetConfig().Server.APIkey
+}
+
+// Dial 连接到指定服务商的RPC地址，所有请求都会经过该服务商的限流器。
+// 未配置限流时返回的Limiter仍会统计消耗，但不做限速和额度限制
+func Dial(ctx context.Context, name, url string) (*ethclient.Client, *ratelimit.Limiter, error) {
+	var cfg config.RateLimitConfig
+	if c := config.GetConfig(); c != nil {
+		cfg = c.RateLimit[name]
+	}
+	limiter, err := ratelimit.New(name, cfg, http.DefaultTransport)
+	if err != nil {
+		return nil, nil, err
+	}
+	rpcClient, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(&http.Client{Transport: limiter}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("连接RPC失败: %w", err)
+	}
+	return ethclient.NewClient(rpcClient), limiter, nil
+}
+
+// DialSepolia 通过Infura连接到Sepolia测试网络
+func DialSepolia(ctx context.Context) (*ethclient.Client, *ratelimit.Limiter, error) {
+	return Dial(ctx, Infura, SepoliaURL())
+}
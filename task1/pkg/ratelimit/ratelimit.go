@@ -0,0 +1,422 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"DApp/pkg/config"
+
+	"github.com/gofrs/flock"
+)
+
+// ErrQuotaExceeded 当日额度已用完时返回，请求不会发往服务商
+var ErrQuotaExceeded = errors.New("RPC每日额度已用完")
+
+// MethodStats 单个RPC方法的消耗统计
+type MethodStats struct {
+	Calls int64
+	Cost  int64
+}
+
+// Stats 限流器的消耗快照
+type Stats struct {
+	Provider   string
+	Day        string
+	Used       int64
+	Quota      int64
+	Throttled  int64         // 因限速而等待的请求数
+	WaitTime   time.Duration // 累计等待时长
+	Retried429 int64         // 收到429后重试的次数
+	Rejected   int64         // 因额度不足被拒绝的请求数
+	Methods    map[string]MethodStats
+}
+
+// Remaining 返回当日剩余额度，未设置额度时返回-1
+func (s Stats) Remaining() int64 {
+	if s.Quota <= 0 {
+		return -1
+	}
+	if s.Used >= s.Quota {
+		return 0
+	}
+	return s.Quota - s.Used
+}
+
+func (s Stats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s 已用额度: %d", s.Provider, s.Day, s.Used)
+	if s.Quota > 0 {
+		fmt.Fprintf(&b, "/%d (剩余 %d)", s.Quota, s.Remaining())
+	}
+	fmt.Fprintf(&b, ", 限速等待: %d次/%s, 429重试: %d, 拒绝: %d", s.Throttled, s.WaitTime.Round(time.Millisecond), s.Retried429, s.Rejected)
+	names := make([]string, 0, len(s.Methods))
+	for name := range s.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m := s.Methods[name]
+		fmt.Fprintf(&b, "\n  %-28s 调用 %d 次, 消耗 %d", name, m.Calls, m.Cost)
+	}
+	return b.String()
+}
+
+// usageRecord 持久化到UsageFile中的当日用量
+type usageRecord struct {
+	Day  string `json:"day"`
+	Used int64  `json:"used"`
+}
+
+// Limiter 对单个服务商的RPC请求做令牌桶限速和每日额度统计，
+// 实现了http.RoundTripper，可直接作为rpc客户端的Transport使用
+type Limiter struct {
+	provider string
+	cfg      config.RateLimitConfig
+	next     http.RoundTripper
+	now      func() time.Time
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	stats   Stats
+	unsaved int64 // 上次写入UsageFile之后本进程新增的用量，写入时与文件中的用量合并
+}
+
+// New 根据配置创建限流器，next为空时使用http.DefaultTransport
+func New(provider string, cfg config.RateLimitConfig, next http.RoundTripper) (*Limiter, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = 1
+	}
+	l := &Limiter{
+		provider: provider,
+		cfg:      cfg,
+		next:     next,
+		now:      time.Now,
+		tokens:   float64(cfg.Burst),
+	}
+	l.last = l.now()
+	l.stats = Stats{
+		Provider: provider,
+		Day:      day(l.last),
+		Quota:    cfg.DailyQuota,
+		Methods:  make(map[string]MethodStats),
+	}
+	if err := l.loadUsage(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Stats 返回当前消耗的快照
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollover()
+	s := l.stats
+	s.Methods = make(map[string]MethodStats, len(l.stats.Methods))
+	for k, v := range l.stats.Methods {
+		s.Methods[k] = v
+	}
+	return s
+}
+
+// Cost 返回方法的权重，未配置的方法为1
+func (l *Limiter) Cost(method string) int64 {
+	if c, ok := l.cfg.MethodCost[method]; ok {
+		return int64(c)
+	}
+	return 1
+}
+
+// RoundTrip 解析请求中的JSON-RPC方法，等待令牌并扣减额度后转发，收到429时退避重试
+func (l *Limiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, methods, err := readMethods(req)
+	if err != nil {
+		return nil, err
+	}
+	var cost int64
+	for _, m := range methods {
+		cost += l.Cost(m)
+	}
+	if err := l.reserve(cost); err != nil {
+		return nil, err
+	}
+	if err := l.wait(req.Context(), len(methods)); err != nil {
+		l.refund(cost)
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		r := req.Clone(req.Context())
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		resp, err := l.next.RoundTrip(r)
+		if err != nil {
+			l.refund(cost)
+			return nil, err
+		}
+		// 收到429的请求同样到达了服务商，额度不退还
+		l.record(methods)
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= l.cfg.MaxRetries {
+			return resp, nil
+		}
+		delay := retryAfter(resp, attempt)
+		resp.Body.Close()
+		l.mu.Lock()
+		l.stats.Retried429++
+		l.mu.Unlock()
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		// 每次重试都是一次新的请求，重新扣减额度并等待令牌
+		if err := l.reserve(cost); err != nil {
+			return nil, err
+		}
+		if err := l.wait(req.Context(), len(methods)); err != nil {
+			l.refund(cost)
+			return nil, err
+		}
+	}
+}
+
+// reserve 预扣额度，超出当日额度时拒绝
+func (l *Limiter) reserve(cost int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollover()
+	if l.cfg.DailyQuota > 0 && l.stats.Used+cost > l.cfg.DailyQuota {
+		l.stats.Rejected++
+		return fmt.Errorf("%w: %s 已用 %d/%d, 本次需要 %d", ErrQuotaExceeded, l.provider, l.stats.Used, l.cfg.DailyQuota, cost)
+	}
+	l.stats.Used += cost
+	l.unsaved += cost
+	return nil
+}
+
+// refund 请求未真正发出时退还额度
+func (l *Limiter) refund(cost int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stats.Used = max(l.stats.Used-cost, 0)
+	l.unsaved -= cost
+}
+
+// record 记录已发出请求的方法统计并持久化当日用量
+func (l *Limiter) record(methods []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, m := range methods {
+		ms := l.stats.Methods[m]
+		ms.Calls++
+		ms.Cost += l.Cost(m)
+		l.stats.Methods[m] = ms
+	}
+	if err := l.saveUsage(); err != nil {
+		fmt.Fprintf(os.Stderr, "保存RPC用量失败: %v\n", err)
+	}
+}
+
+// wait 从令牌桶中取n个令牌，不足时阻塞等待直到ctx结束
+func (l *Limiter) wait(ctx context.Context, n int) error {
+	if l.cfg.RPS <= 0 {
+		return nil
+	}
+	need := float64(n)
+	if need > float64(l.cfg.Burst) {
+		need = float64(l.cfg.Burst)
+	}
+	var waited time.Duration
+	for {
+		l.mu.Lock()
+		now := l.now()
+		l.tokens += now.Sub(l.last).Seconds() * l.cfg.RPS
+		if l.tokens > float64(l.cfg.Burst) {
+			l.tokens = float64(l.cfg.Burst)
+		}
+		l.last = now
+		if l.tokens >= need {
+			l.tokens -= need
+			if waited > 0 {
+				l.stats.Throttled++
+				l.stats.WaitTime += waited
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((need - l.tokens) / l.cfg.RPS * float64(time.Second))
+		l.mu.Unlock()
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+		waited += delay
+	}
+}
+
+// rollover 跨天（UTC）后重置额度，调用方需持有锁
+func (l *Limiter) rollover() {
+	today := day(l.now())
+	if l.stats.Day == today {
+		return
+	}
+	l.stats.Day = today
+	l.stats.Used = 0
+	l.unsaved = 0
+	l.stats.Methods = make(map[string]MethodStats)
+}
+
+func (l *Limiter) loadUsage() error {
+	if l.cfg.UsageFile == "" {
+		return nil
+	}
+	rec, err := readUsage(l.cfg.UsageFile)
+	if err != nil {
+		return err
+	}
+	if rec.Day == l.stats.Day {
+		l.stats.Used = rec.Used
+	}
+	return nil
+}
+
+// saveUsage 在文件锁内重新读取用量文件，加上本进程新增的用量后写回，
+// 多个进程共用同一个UsageFile时不会互相覆盖。调用方需持有锁
+func (l *Limiter) saveUsage() error {
+	if l.cfg.UsageFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(l.cfg.UsageFile), 0o755); err != nil {
+		return err
+	}
+	lock := flock.New(l.cfg.UsageFile + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("锁定RPC用量文件失败: %w", err)
+	}
+	defer lock.Unlock()
+
+	rec, err := readUsage(l.cfg.UsageFile)
+	if err != nil {
+		return err
+	}
+	used := l.unsaved
+	if rec.Day == l.stats.Day {
+		used += rec.Used
+	}
+	used = max(used, 0)
+	data, err := json.Marshal(usageRecord{Day: l.stats.Day, Used: used})
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再改名，其他进程不会读到写了一半的文件
+	tmp := l.cfg.UsageFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, l.cfg.UsageFile); err != nil {
+		return err
+	}
+	// 合并后的用量包含其他进程的消耗，之后的额度检查以它为准
+	l.stats.Used = used
+	l.unsaved = 0
+	return nil
+}
+
+// readUsage 读取用量文件，文件不存在时返回空记录
+func readUsage(path string) (usageRecord, error) {
+	var rec usageRecord
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rec, nil
+	}
+	if err != nil {
+		return rec, fmt.Errorf("读取RPC用量文件失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, fmt.Errorf("解析RPC用量文件失败: %w", err)
+	}
+	return rec, nil
+}
+
+// readMethods 读取请求体并解析出其中的JSON-RPC方法（支持批量请求）
+func readMethods(req *http.Request) ([]byte, []string, error) {
+	if req.Body == nil {
+		return nil, nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取RPC请求失败: %w", err)
+	}
+	type call struct {
+		Method string `json:"method"`
+	}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []call
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return nil, nil, fmt.Errorf("解析RPC批量请求失败: %w", err)
+		}
+		methods := make([]string, len(batch))
+		for i, c := range batch {
+			methods[i] = c.Method
+		}
+		return body, methods, nil
+	}
+	var c call
+	if err := json.Unmarshal(trimmed, &c); err != nil {
+		return nil, nil, fmt.Errorf("解析RPC请求失败: %w", err)
+	}
+	return body, []string{c.Method}, nil
+}
+
+// maxBackoff 指数退避的上限
+const maxBackoff = 30 * time.Second
+
+// retryAfter 优先使用服务端返回的Retry-After，否则指数退避；两者都不超过maxBackoff
+func retryAfter(resp *http.Response, attempt int) time.Duration {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			// 先和上限比较再换算，避免很大的秒数乘以time.Second溢出
+			return time.Duration(min(max(secs, 0), int(maxBackoff/time.Second))) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			if d := time.Until(t); d > 0 {
+				return min(d, maxBackoff)
+			}
+		}
+	}
+	// 500ms<<6已超过上限，先限制attempt，避免MaxRetries很大时移位溢出成0或负数
+	if attempt > 6 {
+		return maxBackoff
+	}
+	return min(500*time.Millisecond<<attempt, maxBackoff)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func day(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,226 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"DApp/pkg/config"
+)
+
+// rpcServer 记录收到的请求数，前fail429个请求返回429
+type rpcServer struct {
+	*httptest.Server
+	requests atomic.Int64
+	fail429  atomic.Int64
+}
+
+func newRPCServer(t *testing.T) *rpcServer {
+	t.Helper()
+	s := &rpcServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if s.fail429.Add(-1) >= 0 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// call 通过限流器发送一个JSON-RPC请求
+func call(l *Limiter, url, body string) (*http.Response, error) {
+	client := &http.Client{Transport: l}
+	resp, err := client.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+const (
+	blockNumber = `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`
+	getLogs     = `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[]}`
+	batch       = `[{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"},{"jsonrpc":"2.0","id":2,"method":"eth_getLogs"}]`
+)
+
+func TestQuota(t *testing.T) {
+	srv := newRPCServer(t)
+	l, err := New("test", config.RateLimitConfig{DailyQuota: 5, MethodCost: map[string]int{"eth_getLogs": 2}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, body := range []string{blockNumber, batch} {
+		if _, err := call(l, srv.URL, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 已用1+3，eth_getLogs需要2，超出额度，请求不会发出
+	if _, err := call(l, srv.URL, getLogs); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("err = %v，期望额度用完", err)
+	}
+	if got := srv.requests.Load(); got != 2 {
+		t.Fatalf("服务商收到 %d 个请求，期望2个", got)
+	}
+	stats := l.Stats()
+	if stats.Used != 4 || stats.Remaining() != 1 || stats.Rejected != 1 {
+		t.Fatalf("统计: %+v", stats)
+	}
+	if m := stats.Methods["eth_getLogs"]; m.Calls != 1 || m.Cost != 2 {
+		t.Fatalf("eth_getLogs统计: %+v", m)
+	}
+}
+
+func TestRetry429(t *testing.T) {
+	tests := []struct {
+		name       string
+		fail429    int64
+		quota      int64
+		maxRetries int
+		wantStatus int
+		wantErr    error
+		wantSent   int64
+		wantUsed   int64
+	}{
+		{name: "重试后成功", fail429: 2, maxRetries: 3, wantStatus: http.StatusOK, wantSent: 3, wantUsed: 3},
+		{name: "重试次数用完", fail429: 5, maxRetries: 1, wantStatus: http.StatusTooManyRequests, wantSent: 2, wantUsed: 2},
+		// 重试同样扣减额度，额度不够时不再重试
+		{name: "重试时额度用完", fail429: 5, quota: 2, maxRetries: 5, wantErr: ErrQuotaExceeded, wantSent: 2, wantUsed: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newRPCServer(t)
+			srv.fail429.Store(tt.fail429)
+			l, err := New("test", config.RateLimitConfig{DailyQuota: tt.quota, MaxRetries: tt.maxRetries}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := call(l, srv.URL, blockNumber)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v，期望 %v", err, tt.wantErr)
+				}
+			} else if err != nil || resp.StatusCode != tt.wantStatus {
+				t.Fatalf("状态 %v，err = %v", resp, err)
+			}
+			stats := l.Stats()
+			if got := srv.requests.Load(); got != tt.wantSent || stats.Used != tt.wantUsed {
+				t.Fatalf("服务商收到 %d 个请求，已用额度 %d，期望 %d/%d", got, stats.Used, tt.wantSent, tt.wantUsed)
+			}
+			if stats.Methods["eth_blockNumber"].Calls != tt.wantSent {
+				t.Fatalf("方法统计: %+v", stats.Methods)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := func(v string) *http.Response {
+		r := &http.Response{Header: http.Header{}}
+		if v != "" {
+			r.Header.Set("Retry-After", v)
+		}
+		return r
+	}
+	tests := []struct {
+		name    string
+		header  string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "秒数", header: "3", want: 3 * time.Second},
+		{name: "秒数超过上限", header: "86400", want: maxBackoff},
+		{name: "超大秒数", header: "9223372036854775807", want: maxBackoff},
+		{name: "负数", header: "-5", want: 0},
+		{name: "日期超过上限", header: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), want: maxBackoff},
+		{name: "指数退避", attempt: 2, want: 2 * time.Second},
+		{name: "退避超过上限", attempt: 6, want: maxBackoff},
+		{name: "退避次数很大", attempt: 100, want: maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(resp(tt.header), tt.attempt); got != tt.want {
+			t.Errorf("%s: retryAfter = %v，期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	srv := newRPCServer(t)
+	l, err := New("test", config.RateLimitConfig{RPS: 50, Burst: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := call(l, srv.URL, blockNumber); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 桶里只有1个令牌，后两个请求各等20ms
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Fatalf("3个请求用了 %v，没有限速", elapsed)
+	}
+	if stats := l.Stats(); stats.Throttled != 2 || stats.WaitTime <= 0 {
+		t.Fatalf("统计: %+v", stats)
+	}
+}
+
+func TestUsageFileShared(t *testing.T) {
+	srv := newRPCServer(t)
+	cfg := config.RateLimitConfig{DailyQuota: 10, UsageFile: filepath.Join(t.TempDir(), "usage", "infura.json")}
+	// 两个进程各自的限流器共用同一个用量文件
+	a, err := New("test", cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := New("test", cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := call(a, srv.URL, blockNumber); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := call(b, srv.URL, blockNumber); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 写入时合并了对方的用量，不会互相覆盖
+	if used := b.Stats().Used; used != 6 {
+		t.Fatalf("合并后已用 %d，期望6", used)
+	}
+	rec, err := readUsage(cfg.UsageFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Used != 6 {
+		t.Fatalf("用量文件记录 %d，期望6", rec.Used)
+	}
+
+	c, err := New("test", cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used := c.Stats().Used; used != 6 {
+		t.Fatalf("新进程读到已用 %d，期望6", used)
+	}
+
+	// 跨天后重新计数
+	c.now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+	if _, err := call(c, srv.URL, blockNumber); err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ := readUsage(cfg.UsageFile); rec.Used != 1 || rec.Day != day(c.now()) {
+		t.Fatalf("跨天后用量文件: %+v", rec)
+	}
+}
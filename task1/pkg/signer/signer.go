@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"DApp/pkg/config"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer 持有用于签名交易的私钥及对应地址
+type Signer struct {
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+}
+
+// FromHex 从十六进制私钥创建Signer，允许带0x前缀
+func FromHex(hexKey string) (*Signer, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("无法解析私钥: %w", err)
+	}
+	return New(key), nil
+}
+
+// FromConfig 使用配置文件中的server.private_key创建Signer
+func FromConfig() (*Signer, error) {
+	return FromHex(config.GetConfig().Server.PrivateKey)
+}
+
+// New 由已有私钥创建Signer
+func New(key *ecdsa.PrivateKey) *Signer {
+	return &Signer{Key: key, Address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// SignTx 使用EIP-155签名交易
+func (s *Signer) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), s.Key)
+	if err != nil {
+		return nil, fmt.Errorf("无法签名交易: %w", err)
+	}
+	return signed, nil
+}
+
+// TransactOpts 创建合约绑定使用的交易选项
+func (s *Signer) TransactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error) {
+	auth, err := bind.NewKeyedTransactorWithChainID(s.Key, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("创建交易签名器失败: %w", err)
+	}
+	auth.Context = ctx
+	return auth, nil
+}
@@ -0,0 +1,149 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"DApp/erc20"
+	"DApp/pkg/gas"
+	"DApp/pkg/units"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Token ERC-20合约及其元数据，金额的解析和格式化按合约的decimals进行
+type Token struct {
+	Address  common.Address
+	Name     string
+	Symbol   string
+	Decimals uint8
+
+	backend  bind.ContractBackend
+	contract *erc20.ERC20
+}
+
+// Load 绑定合约并读取name/symbol/decimals
+func Load(ctx context.Context, address common.Address, backend bind.ContractBackend) (*Token, error) {
+	contract, err := erc20.NewERC20(address, backend)
+	if err != nil {
+		return nil, fmt.Errorf("绑定ERC-20合约失败: %w", err)
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	t := &Token{Address: address, backend: backend, contract: contract}
+	if t.Decimals, err = contract.Decimals(opts); err != nil {
+		return nil, fmt.Errorf("读取decimals失败: %w", err)
+	}
+	// name和symbol在ERC-20中是可选的，读取失败不影响使用
+	if t.Name, err = contract.Name(opts); err != nil {
+		t.Name = ""
+	}
+	if t.Symbol, err = contract.Symbol(opts); err != nil {
+		t.Symbol = ""
+	}
+	return t, nil
+}
+
+// Contract 返回底层合约绑定
+func (t *Token) Contract() *erc20.ERC20 {
+	return t.contract
+}
+
+// ParseAmount 将"1.5"这样的金额按代币精度转换为最小单位
+func (t *Token) ParseAmount(s string) (*big.Int, error) {
+	return units.ParseUnits(s, t.Decimals)
+}
+
+// FormatAmount 将最小单位的金额格式化为带符号的可读字符串
+func (t *Token) FormatAmount(v *big.Int) string {
+	if t.Symbol == "" {
+		return units.FormatUnits(v, t.Decimals)
+	}
+	return units.FormatUnits(v, t.Decimals) + " " + t.Symbol
+}
+
+// BalanceOf 查询余额
+func (t *Token) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+	balance, err := t.contract.BalanceOf(&bind.CallOpts{Context: ctx}, owner)
+	if err != nil {
+		return nil, fmt.Errorf("查询余额失败: %w", err)
+	}
+	return balance, nil
+}
+
+// Allowance 查询owner授权给spender的额度
+func (t *Token) Allowance(ctx context.Context, owner, spender common.Address) (*big.Int, error) {
+	allowance, err := t.contract.Allowance(&bind.CallOpts{Context: ctx}, owner, spender)
+	if err != nil {
+		return nil, fmt.Errorf("查询授权额度失败: %w", err)
+	}
+	return allowance, nil
+}
+
+// TotalSupply 查询总供应量
+func (t *Token) TotalSupply(ctx context.Context) (*big.Int, error) {
+	supply, err := t.contract.TotalSupply(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("查询总供应量失败: %w", err)
+	}
+	return supply, nil
+}
+
+// Transfer 从auth.From转账到to，发送前检查余额并模拟执行
+func (t *Token) Transfer(ctx context.Context, auth *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, *gas.Report, error) {
+	if err := t.checkBalance(ctx, auth.From, amount); err != nil {
+		return nil, nil, err
+	}
+	tx, report, err := gas.Transact(ctx, t.backend, auth, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return t.contract.Transfer(opts, to, amount)
+	})
+	if err != nil {
+		return nil, report, fmt.Errorf("transfer失败: %w", err)
+	}
+	return tx, report, nil
+}
+
+// Approve 授权spender可以转走auth.From的amount代币
+func (t *Token) Approve(ctx context.Context, auth *bind.TransactOpts, spender common.Address, amount *big.Int) (*types.Transaction, *gas.Report, error) {
+	tx, report, err := gas.Transact(ctx, t.backend, auth, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return t.contract.Approve(opts, spender, amount)
+	})
+	if err != nil {
+		return nil, report, fmt.Errorf("approve失败: %w", err)
+	}
+	return tx, report, nil
+}
+
+// TransferFrom 由auth.From使用from授予的额度把代币转给to，发送前检查额度和余额并模拟执行
+func (t *Token) TransferFrom(ctx context.Context, auth *bind.TransactOpts, from, to common.Address, amount *big.Int) (*types.Transaction, *gas.Report, error) {
+	allowance, err := t.Allowance(ctx, from, auth.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	if allowance.Cmp(amount) < 0 {
+		return nil, nil, fmt.Errorf("授权额度不足: %s 仅授权 %s，需要 %s", from.Hex(), t.FormatAmount(allowance), t.FormatAmount(amount))
+	}
+	if err := t.checkBalance(ctx, from, amount); err != nil {
+		return nil, nil, err
+	}
+	tx, report, err := gas.Transact(ctx, t.backend, auth, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return t.contract.TransferFrom(opts, from, to, amount)
+	})
+	if err != nil {
+		return nil, report, fmt.Errorf("transferFrom失败: %w", err)
+	}
+	return tx, report, nil
+}
+
+func (t *Token) checkBalance(ctx context.Context, owner common.Address, amount *big.Int) error {
+	balance, err := t.BalanceOf(ctx, owner)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("余额不足: %s 余额 %s，需要 %s", owner.Hex(), t.FormatAmount(balance), t.FormatAmount(amount))
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package token
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"DApp/erc20"
+	"DApp/internal/testchain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// deployToken 部署decimals为6的MockERC20并给alice铸造1000个代币
+func deployToken(t *testing.T, c *testchain.Chain) *Token {
+	t.Helper()
+	ctx := context.Background()
+	address, tx, mock, err := erc20.DeployMockERC20(c.Alice, c.Client, "Test USD", "TUSD", 6)
+	c.Mined(t, tx, err)
+	tx, err = mock.Mint(c.Alice, c.Alice.From, big.NewInt(1000_000000))
+	c.Mined(t, tx, err)
+
+	tok, err := Load(ctx, address, c.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func TestLoad(t *testing.T) {
+	c := testchain.New(t)
+	tok := deployToken(t, c)
+	if tok.Name != "Test USD" || tok.Symbol != "TUSD" || tok.Decimals != 6 {
+		t.Fatalf("元数据 = %q %q %d", tok.Name, tok.Symbol, tok.Decimals)
+	}
+	supply, err := tok.TotalSupply(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if supply.Cmp(big.NewInt(1000_000000)) != 0 {
+		t.Fatalf("总供应量 = %s", supply)
+	}
+}
+
+func TestAmountDecimals(t *testing.T) {
+	tok := &Token{Symbol: "TUSD", Decimals: 6}
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"1", 1_000000},
+		{"1.5", 1_500000},
+		{"0.000001", 1},
+		{"1000", 1000_000000},
+	}
+	for _, tt := range tests {
+		got, err := tok.ParseAmount(tt.in)
+		if err != nil {
+			t.Fatalf("ParseAmount(%q): %v", tt.in, err)
+		}
+		if got.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Fatalf("ParseAmount(%q) = %s，期望 %d", tt.in, got, tt.want)
+		}
+	}
+	if _, err := tok.ParseAmount("0.0000001"); err == nil {
+		t.Fatal("超过6位小数应报错")
+	}
+	if got := tok.FormatAmount(big.NewInt(1_500000)); got != "1.5 TUSD" {
+		t.Fatalf("FormatAmount = %q", got)
+	}
+	tok.Symbol = ""
+	if got := tok.FormatAmount(big.NewInt(1)); got != "0.000001" {
+		t.Fatalf("FormatAmount = %q", got)
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	ctx := context.Background()
+	c := testchain.New(t)
+	tok := deployToken(t, c)
+
+	amount, err := tok.ParseAmount("250.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, report, err := tok.Transfer(ctx, c.Alice, c.Bob.From, amount)
+	c.Mined(t, tx, err)
+	if report.GasLimit < report.Estimate {
+		t.Fatalf("Gas上限 %d 小于预估 %d", report.GasLimit, report.Estimate)
+	}
+
+	balances := map[common.Address]int64{c.Alice.From: 749_500000, c.Bob.From: 250_500000}
+	for owner, want := range balances {
+		got, err := tok.BalanceOf(ctx, owner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(big.NewInt(want)) != 0 {
+			t.Fatalf("%s 余额 = %s，期望 %d", owner.Hex(), tok.FormatAmount(got), want)
+		}
+	}
+
+	// 余额不足在发送前拦截
+	tooMuch, _ := tok.ParseAmount("250.6")
+	if _, _, err := tok.Transfer(ctx, c.Bob, c.Alice.From, tooMuch); err == nil || !strings.Contains(err.Error(), "余额不足") {
+		t.Fatalf("余额不足时 err = %v", err)
+	}
+}
+
+func TestApproveAndTransferFrom(t *testing.T) {
+	ctx := context.Background()
+	c := testchain.New(t)
+	tok := deployToken(t, c)
+
+	limit, _ := tok.ParseAmount("100")
+	tx, _, err := tok.Approve(ctx, c.Alice, c.Bob.From, limit)
+	c.Mined(t, tx, err)
+	allowance, err := tok.Allowance(ctx, c.Alice.From, c.Bob.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowance.Cmp(limit) != 0 {
+		t.Fatalf("授权额度 = %s，期望 %s", allowance, limit)
+	}
+
+	spend, _ := tok.ParseAmount("40")
+	tx, _, err = tok.TransferFrom(ctx, c.Bob, c.Alice.From, c.Bob.From, spend)
+	c.Mined(t, tx, err)
+	allowance, err = tok.Allowance(ctx, c.Alice.From, c.Bob.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := big.NewInt(60_000000); allowance.Cmp(want) != 0 {
+		t.Fatalf("剩余授权额度 = %s，期望 %s", allowance, want)
+	}
+	balance, err := tok.BalanceOf(ctx, c.Bob.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Cmp(spend) != 0 {
+		t.Fatalf("bob余额 = %s，期望 %s", balance, spend)
+	}
+
+	// 超过剩余额度在发送前拦截
+	if _, _, err := tok.TransferFrom(ctx, c.Bob, c.Alice.From, c.Bob.From, limit); err == nil || !strings.Contains(err.Error(), "授权额度不足") {
+		t.Fatalf("额度不足时 err = %v", err)
+	}
+}
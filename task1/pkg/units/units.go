@@ -0,0 +1,72 @@
+package units
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// EtherDecimals ETH的精度，1 ETH = 1e18 wei
+const EtherDecimals = 18
+
+// ParseUnits 将十进制字符串（如"1.5"）按精度转换为最小单位的整数，小数位超过精度时报错
+func ParseUnits(s string, decimals uint8) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("金额为空")
+	}
+	if strings.HasPrefix(s, "-") {
+		return nil, fmt.Errorf("金额不能为负数: %s", s)
+	}
+	s = strings.TrimPrefix(s, "+")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return nil, fmt.Errorf("无效金额: %s", s)
+	}
+	if len(frac) > int(decimals) {
+		if strings.Trim(frac[decimals:], "0") != "" {
+			return nil, fmt.Errorf("金额 %s 超出精度（最多%d位小数）", s, decimals)
+		}
+		frac = frac[:decimals]
+	}
+	digits := whole + frac + strings.Repeat("0", int(decimals)-len(frac))
+	if strings.Trim(digits, "0123456789") != "" {
+		return nil, fmt.Errorf("无效金额: %s", s)
+	}
+	v, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("无效金额: %s", s)
+	}
+	return v, nil
+}
+
+// FormatUnits 将最小单位的整数按精度格式化为十进制字符串，去掉末尾多余的0
+func FormatUnits(v *big.Int, decimals uint8) string {
+	if v == nil {
+		return "0"
+	}
+	neg := v.Sign() < 0
+	digits := new(big.Int).Abs(v).String()
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-int(decimals)], strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	s := whole
+	if frac != "" {
+		s += "." + frac
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// ParseEther 将ETH金额字符串转换为wei
+func ParseEther(s string) (*big.Int, error) {
+	return ParseUnits(s, EtherDecimals)
+}
+
+// FormatEther 将wei格式化为ETH金额字符串
+func FormatEther(wei *big.Int) string {
+	return FormatUnits(wei, EtherDecimals)
+}
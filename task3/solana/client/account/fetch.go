@@ -0,0 +1,80 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// getMultipleAccounts每次最多100个账户
+const fetchBatchSize = 100
+
+// Account 读取到的账户，Layout和Value由Registry.Decode填写
+type Account struct {
+	Address    solana.PublicKey
+	Exists     bool
+	Owner      solana.PublicKey
+	Lamports   uint64
+	Executable bool
+	Data       []byte
+	Layout     string // 匹配的数据格式名，未识别时为空
+	Value      any    // 解码结果
+	DecodeErr  error  // 未识别或解码失败的原因，不影响其他账户
+}
+
+func (a *Account) rpcAccount() *rpc.Account {
+	return &rpc.Account{
+		Owner:      a.Owner,
+		Lamports:   a.Lamports,
+		Executable: a.Executable,
+		Data:       rpc.DataBytesOrJSONFromBytes(a.Data),
+	}
+}
+
+// Fetch 用getMultipleAccounts分批读取账户，结果与addresses一一对应；不存在的账户Exists为false
+func Fetch(ctx context.Context, rpcClient *rpc.Client, addresses ...solana.PublicKey) ([]*Account, error) {
+	accounts := make([]*Account, 0, len(addresses))
+	for start := 0; start < len(addresses); start += fetchBatchSize {
+		batch := addresses[start:min(start+fetchBatchSize, len(addresses))]
+		res, err := rpcClient.GetMultipleAccountsWithOpts(ctx, batch, &rpc.GetMultipleAccountsOpts{
+			Encoding:   solana.EncodingBase64,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("读取账户失败: %w", utils.ClassifyError(err))
+		}
+		if len(res.Value) != len(batch) {
+			return nil, fmt.Errorf("节点返回 %d 个账户，请求了 %d 个", len(res.Value), len(batch))
+		}
+		for i, info := range res.Value {
+			acc := &Account{Address: batch[i]}
+			if info != nil {
+				acc.Exists = true
+				acc.Owner = info.Owner
+				acc.Lamports = info.Lamports
+				acc.Executable = info.Executable
+				acc.Data = info.Data.GetBinary()
+			}
+			accounts = append(accounts, acc)
+		}
+	}
+	return accounts, nil
+}
+
+// Fetch 读取账户并用注册的数据格式解码，单个账户解码失败记录在DecodeErr中
+func (r *Registry) Fetch(ctx context.Context, rpcClient *rpc.Client, addresses ...solana.PublicKey) ([]*Account, error) {
+	accounts, err := Fetch(ctx, rpcClient, addresses...)
+	if err != nil {
+		return nil, err
+	}
+	for _, acc := range accounts {
+		if acc.Exists && len(acc.Data) > 0 {
+			acc.DecodeErr = r.Decode(acc)
+		}
+	}
+	return accounts, nil
+}
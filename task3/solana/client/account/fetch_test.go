@@ -0,0 +1,88 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"solana-go/client/nonce"
+	"solana-go/mockrpc"
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestRegistryFetch(t *testing.T) {
+	srv := mockrpc.NewServer(mockrpc.Options{})
+	defer srv.Close()
+	rpcClient := rpc.New(srv.URL)
+
+	mint, owner, program := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	tokenAcc, pool, nonceAcc, unknown, missing, wallet :=
+		solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(),
+		solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	srv.SetAccount(mint, solana.Token2022ProgramID, 1_000_000, withExtensions(mintData(t, 6), token2022AccountTypeMint))
+	srv.SetAccount(tokenAcc, solana.TokenProgramID, 2_000_000, tokenAccountData(t, mint, owner, 500))
+	srv.SetAccount(pool, program, 3_000_000, poolData(mint))
+	srv.SetAccount(unknown, program, 1_000_000, []byte{1, 2, 3})
+	srv.SetNonceAccount(nonceAcc, owner)
+	srv.SetBalance(wallet, solana.LAMPORTS_PER_SOL)
+
+	// 超过一批的地址分两次请求，结果仍与地址一一对应
+	addresses := []solana.PublicKey{mint, tokenAcc, pool, nonceAcc, unknown, missing, wallet}
+	for len(addresses) < fetchBatchSize+1 {
+		addresses = append(addresses, solana.NewWallet().PublicKey())
+	}
+	accounts, err := DefaultRegistry().Fetch(context.Background(), rpcClient, addresses...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != len(addresses) || srv.Calls("getMultipleAccounts") != 2 {
+		t.Fatalf("读取 %d 个账户，请求 %d 次", len(accounts), srv.Calls("getMultipleAccounts"))
+	}
+	for i, acc := range accounts {
+		if acc.Address != addresses[i] {
+			t.Fatalf("第%d个账户 %s，期望 %s", i, acc.Address, addresses[i])
+		}
+	}
+
+	tests := []struct {
+		name   string
+		acc    *Account
+		exists bool
+		layout string
+		owner  solana.PublicKey
+	}{
+		{name: "Token-2022 mint", acc: accounts[0], exists: true, layout: "spl-token-mint", owner: solana.Token2022ProgramID},
+		{name: "代币账户", acc: accounts[1], exists: true, layout: "spl-token-account", owner: solana.TokenProgramID},
+		{name: "池子", acc: accounts[2], exists: true, layout: "token-swap-pool", owner: program},
+		{name: "nonce账户", acc: accounts[3], exists: true, layout: "nonce", owner: solana.SystemProgramID},
+		{name: "未识别", acc: accounts[4], exists: true, owner: program},
+		{name: "不存在", acc: accounts[5]},
+		{name: "普通钱包", acc: accounts[6], exists: true, owner: solana.SystemProgramID},
+	}
+	for _, tt := range tests {
+		acc := tt.acc
+		if acc.Exists != tt.exists || acc.Layout != tt.layout || acc.Owner != tt.owner {
+			t.Errorf("%s: exists %v layout %q owner %s", tt.name, acc.Exists, acc.Layout, acc.Owner)
+		}
+		// 没有数据的账户不解码，也不算错误
+		switch {
+		case tt.layout != "" && acc.DecodeErr != nil:
+			t.Errorf("%s: %v", tt.name, acc.DecodeErr)
+		case tt.layout == "" && len(acc.Data) > 0 && !errors.Is(acc.DecodeErr, ErrUnknownLayout):
+			t.Errorf("%s: DecodeErr = %v", tt.name, acc.DecodeErr)
+		case len(acc.Data) == 0 && (acc.DecodeErr != nil || acc.Value != nil):
+			t.Errorf("%s: 空账户 DecodeErr = %v", tt.name, acc.DecodeErr)
+		}
+	}
+
+	if ta := accounts[1].Value.(*utils.TokenAccount); ta.Mint != mint || ta.Amount != 500 || ta.Lamports != 2_000_000 {
+		t.Fatalf("代币账户 %+v", ta)
+	}
+	nonceHash, _, _ := srv.Nonce(nonceAcc)
+	if n := accounts[3].Value.(*nonce.Account); n.Nonce != nonceHash || n.Authority != owner || n.Lamports != srv.Balance(nonceAcc) {
+		t.Fatalf("nonce账户 %+v", n)
+	}
+}
@@ -0,0 +1,188 @@
+package account
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"solana-go/client/anchor"
+	"solana-go/client/nonce"
+	"solana-go/client/tokenswap"
+	"solana-go/utils"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	addresslookuptable "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+)
+
+// 账户数据的解码：每种数据格式注册为一个Layout，按owner、数据长度和开头的discriminator匹配，
+// 第一个匹配的Layout负责解码。DefaultRegistry包含SPL Token账户和mint（Token与Token-2022）、
+// Token Swap池子、nonce账户和地址查找表；自己的程序用RegisterBorsh或RegisterAnchor注册
+
+const (
+	mintLen         = 82
+	tokenAccountLen = 165
+	// Token-2022带扩展的账户在第165字节记录账户类型，mint会先补齐到165字节
+	token2022AccountTypeMint    = 1
+	token2022AccountTypeAccount = 2
+)
+
+// ErrUnknownLayout 没有匹配的数据格式
+var ErrUnknownLayout = errors.New("未识别的账户数据格式")
+
+// Layout 一种账户数据格式
+type Layout struct {
+	Name string
+	// Owners 账户的owner必须是其中之一，为空时不限制
+	Owners []solana.PublicKey
+	// Size 数据长度必须等于Size，为0时不限制
+	Size int
+	// Discriminator 数据必须以这些字节开头
+	Discriminator []byte
+	// Match 额外的匹配条件，可以为nil
+	Match func(owner solana.PublicKey, data []byte) bool
+	// Decode 解码acc.Data，返回的通常是结构体指针
+	Decode func(acc *Account) (any, error)
+}
+
+func (l *Layout) matches(owner solana.PublicKey, data []byte) bool {
+	if len(l.Owners) > 0 && !slices.Contains(l.Owners, owner) {
+		return false
+	}
+	if l.Size > 0 && len(data) != l.Size {
+		return false
+	}
+	if !bytes.HasPrefix(data, l.Discriminator) {
+		return false
+	}
+	return l.Match == nil || l.Match(owner, data)
+}
+
+// Registry 按注册顺序匹配的数据格式列表
+type Registry struct {
+	layouts []Layout
+}
+
+// NewRegistry 创建只包含layouts的注册表
+func NewRegistry(layouts ...Layout) *Registry {
+	return &Registry{layouts: layouts}
+}
+
+// DefaultRegistry 包含内置数据格式的注册表，每次调用返回新的实例，可以继续注册
+func DefaultRegistry() *Registry {
+	return NewRegistry(TokenAccountLayout, MintLayout, TokenSwapPoolLayout, NonceLayout, LookupTableLayout)
+}
+
+// Register 追加数据格式，先注册的优先匹配
+func (r *Registry) Register(layouts ...Layout) {
+	r.layouts = append(r.layouts, layouts...)
+}
+
+// RegisterBorsh 注册Borsh编码的数据格式：数据以discriminator开头（可以为空），后面按newValue返回的结构体解码
+func (r *Registry) RegisterBorsh(name string, owner solana.PublicKey, discriminator []byte, newValue func() any) {
+	var owners []solana.PublicKey
+	if !owner.IsZero() {
+		owners = []solana.PublicKey{owner}
+	}
+	r.Register(Layout{
+		Name:          name,
+		Owners:        owners,
+		Discriminator: discriminator,
+		Decode: func(acc *Account) (any, error) {
+			v := newValue()
+			if err := bin.NewBorshDecoder(acc.Data[len(discriminator):]).Decode(v); err != nil {
+				return nil, fmt.Errorf("Borsh解码失败: %v", err)
+			}
+			return v, nil
+		},
+	})
+}
+
+// RegisterAnchor 注册Anchor程序的账户，discriminator和结构体通常来自anchorgen生成的代码，
+// 例如RegisterAnchor("counter.Counter", counter.ProgramID, counter.CounterAccountDiscriminator, func() any { return new(counter.Counter) })
+func (r *Registry) RegisterAnchor(name string, programID solana.PublicKey, discriminator anchor.Discriminator, newValue func() any) {
+	r.RegisterBorsh(name, programID, discriminator[:], newValue)
+}
+
+// Decode 用第一个匹配的数据格式解码acc.Data，结果写入acc.Layout和acc.Value；没有匹配时返回ErrUnknownLayout。
+// 离线解码时只需要填Address、Owner和Data
+func (r *Registry) Decode(acc *Account) error {
+	for i := range r.layouts {
+		l := &r.layouts[i]
+		if !l.matches(acc.Owner, acc.Data) {
+			continue
+		}
+		acc.Layout = l.Name
+		v, err := l.Decode(acc)
+		if err != nil {
+			return fmt.Errorf("按%s解码 %s 失败: %w", l.Name, acc.Address, err)
+		}
+		acc.Value = v
+		return nil
+	}
+	return fmt.Errorf("%w: %s（owner %s，%d 字节）", ErrUnknownLayout, acc.Address, acc.Owner, len(acc.Data))
+}
+
+var tokenPrograms = []solana.PublicKey{solana.TokenProgramID, solana.Token2022ProgramID}
+
+// TokenAccountLayout SPL Token和Token-2022的代币账户，解码为*utils.TokenAccount（Decimals需要另外读取mint）
+var TokenAccountLayout = Layout{
+	Name:   "spl-token-account",
+	Owners: tokenPrograms,
+	Match: func(_ solana.PublicKey, data []byte) bool {
+		return len(data) == tokenAccountLen || (len(data) > tokenAccountLen && data[tokenAccountLen] == token2022AccountTypeAccount)
+	},
+	Decode: func(acc *Account) (any, error) {
+		return utils.DecodeTokenAccount(acc.Address, acc.rpcAccount())
+	},
+}
+
+// MintLayout SPL Token和Token-2022的mint，解码为*utils.MintInfo
+var MintLayout = Layout{
+	Name:   "spl-token-mint",
+	Owners: tokenPrograms,
+	Match: func(_ solana.PublicKey, data []byte) bool {
+		return len(data) == mintLen || (len(data) > tokenAccountLen && data[tokenAccountLen] == token2022AccountTypeMint)
+	},
+	Decode: func(acc *Account) (any, error) {
+		return utils.DecodeMint(acc.Address, acc.rpcAccount())
+	},
+}
+
+// TokenSwapPoolLayout SPL Token Swap的池子状态，解码为*tokenswap.Pool。
+// Token Swap程序在各网络的部署地址不同，不限制owner，按长度和版本号识别
+var TokenSwapPoolLayout = Layout{
+	Name: "token-swap-pool",
+	Size: tokenswap.PoolStateLen,
+	Match: func(owner solana.PublicKey, data []byte) bool {
+		return data[0] == 1 && !slices.Contains(tokenPrograms, owner) && !owner.Equals(solana.SystemProgramID)
+	},
+	Decode: func(acc *Account) (any, error) {
+		return tokenswap.DecodePool(acc.Address, acc.Owner, acc.Data)
+	},
+}
+
+// NonceLayout System程序的durable nonce账户，解码为*nonce.Account
+var NonceLayout = Layout{
+	Name:   "nonce",
+	Owners: []solana.PublicKey{solana.SystemProgramID},
+	Size:   nonce.AccountSize,
+	Decode: func(acc *Account) (any, error) {
+		v, err := nonce.DecodeAccount(acc.Address, acc.Data)
+		if err != nil {
+			return nil, err
+		}
+		v.Lamports = acc.Lamports
+		return v, nil
+	},
+}
+
+// LookupTableLayout 地址查找表，解码为*addresslookuptable.AddressLookupTableState
+var LookupTableLayout = Layout{
+	Name:   "address-lookup-table",
+	Owners: []solana.PublicKey{solana.AddressLookupTableProgramID},
+	Decode: func(acc *Account) (any, error) {
+		return addresslookuptable.DecodeAddressLookupTableState(acc.Data)
+	},
+}
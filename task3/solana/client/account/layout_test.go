@@ -0,0 +1,223 @@
+package account
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"solana-go/client/nonce"
+	"solana-go/client/tokenswap"
+	"solana-go/utils"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+)
+
+func encode(t *testing.T, v any) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := bin.NewBinEncoder(&buf).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tokenAccountData(t *testing.T, mint, owner solana.PublicKey, amount uint64) []byte {
+	t.Helper()
+	data := encode(t, token.Account{Mint: mint, Owner: owner, Amount: amount, State: token.Initialized})
+	if len(data) != tokenAccountLen {
+		t.Fatalf("代币账户数据 %d 字节", len(data))
+	}
+	return data
+}
+
+func mintData(t *testing.T, decimals uint8) []byte {
+	t.Helper()
+	data := encode(t, token.Mint{Decimals: decimals, IsInitialized: true})
+	if len(data) != mintLen {
+		t.Fatalf("mint数据 %d 字节", len(data))
+	}
+	return data
+}
+
+// withExtensions Token-2022带扩展的账户：补齐到165字节，写入账户类型，后面跟扩展数据
+func withExtensions(data []byte, accountType byte) []byte {
+	out := make([]byte, tokenAccountLen, tokenAccountLen+8)
+	copy(out, data)
+	return append(out, accountType, 0, 0, 0, 0, 0, 0, 0)
+}
+
+// poolData 已初始化的SwapV1池子，tokenAMint写在mint位置方便核对
+func poolData(tokenAMint solana.PublicKey) []byte {
+	data := make([]byte, tokenswap.PoolStateLen)
+	data[0], data[1] = 1, 1
+	// 版本、初始化标记、bump之后依次是Token程序、A/B账户、LP mint、A mint
+	copy(data[3+4*32:], tokenAMint[:])
+	return data
+}
+
+func nonceData(t *testing.T, authority solana.PublicKey, state uint32) []byte {
+	t.Helper()
+	data := encode(t, system.NonceAccount{State: state, AuthorizedPubkey: authority, FeeCalculator: system.FeeCalculator{LamportsPerSignature: 5000}})
+	if len(data) != nonce.AccountSize {
+		t.Fatalf("nonce数据 %d 字节", len(data))
+	}
+	return data
+}
+
+func TestRegistryDecode(t *testing.T) {
+	mint, owner := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	program := solana.NewWallet().PublicKey()
+	tests := []struct {
+		name   string
+		owner  solana.PublicKey
+		data   []byte
+		layout string
+		check  func(t *testing.T, v any)
+	}{
+		{
+			name:   "Token代币账户",
+			owner:  solana.TokenProgramID,
+			data:   tokenAccountData(t, mint, owner, 42),
+			layout: "spl-token-account",
+			check: func(t *testing.T, v any) {
+				if ta := v.(*utils.TokenAccount); ta.Mint != mint || ta.Owner != owner || ta.Amount != 42 {
+					t.Fatalf("%+v", ta)
+				}
+			},
+		},
+		{
+			name:   "Token mint",
+			owner:  solana.TokenProgramID,
+			data:   mintData(t, 6),
+			layout: "spl-token-mint",
+			check: func(t *testing.T, v any) {
+				if m := v.(*utils.MintInfo); m.Decimals != 6 {
+					t.Fatalf("%+v", m)
+				}
+			},
+		},
+		{
+			name:   "Token-2022带扩展的代币账户",
+			owner:  solana.Token2022ProgramID,
+			data:   withExtensions(tokenAccountData(t, mint, owner, 7), token2022AccountTypeAccount),
+			layout: "spl-token-account",
+			check: func(t *testing.T, v any) {
+				if ta := v.(*utils.TokenAccount); ta.Amount != 7 || ta.ProgramID != solana.Token2022ProgramID {
+					t.Fatalf("%+v", ta)
+				}
+			},
+		},
+		{
+			// 长度超过165字节时只按账户类型区分，mint补齐后也是这个长度
+			name:   "Token-2022带扩展的mint",
+			owner:  solana.Token2022ProgramID,
+			data:   withExtensions(mintData(t, 9), token2022AccountTypeMint),
+			layout: "spl-token-mint",
+			check: func(t *testing.T, v any) {
+				if m := v.(*utils.MintInfo); m.Decimals != 9 {
+					t.Fatalf("%+v", m)
+				}
+			},
+		},
+		{
+			name:  "Token-2022账户类型未知",
+			owner: solana.Token2022ProgramID,
+			data:  withExtensions(mintData(t, 9), 0),
+		},
+		{
+			name:   "Token Swap池子",
+			owner:  program,
+			data:   poolData(mint),
+			layout: "token-swap-pool",
+			check: func(t *testing.T, v any) {
+				if p := v.(*tokenswap.Pool); p.ProgramID != program || p.TokenAMint != mint {
+					t.Fatalf("%+v", p)
+				}
+			},
+		},
+		{
+			// 池子不限制owner，但System和Token程序的账户不可能是池子
+			name:  "System程序的324字节账户",
+			owner: solana.SystemProgramID,
+			data:  poolData(mint),
+		},
+		{
+			name:  "池子版本不是1",
+			owner: program,
+			data:  make([]byte, tokenswap.PoolStateLen),
+		},
+		{
+			name:   "nonce账户",
+			owner:  solana.SystemProgramID,
+			data:   nonceData(t, owner, 1),
+			layout: "nonce",
+			check: func(t *testing.T, v any) {
+				if n := v.(*nonce.Account); n.Authority != owner || n.LamportsPerSignature != 5000 || n.Lamports != 1000 {
+					t.Fatalf("%+v", n)
+				}
+			},
+		},
+		{
+			name:  "其他程序的80字节账户不是nonce",
+			owner: program,
+			data:  nonceData(t, owner, 1),
+		},
+	}
+	r := DefaultRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acc := &Account{Address: solana.NewWallet().PublicKey(), Owner: tt.owner, Lamports: 1000, Data: tt.data}
+			err := r.Decode(acc)
+			if tt.layout == "" {
+				if !errors.Is(err, ErrUnknownLayout) || acc.Layout != "" {
+					t.Fatalf("匹配到 %q，err = %v，期望未识别", acc.Layout, err)
+				}
+				return
+			}
+			if err != nil || acc.Layout != tt.layout {
+				t.Fatalf("匹配到 %q，err = %v，期望 %q", acc.Layout, err, tt.layout)
+			}
+			tt.check(t, acc.Value)
+		})
+	}
+}
+
+func TestRegistryOrder(t *testing.T) {
+	type state struct {
+		Count uint64
+	}
+	program := solana.NewWallet().PublicKey()
+	custom := func(r *Registry) {
+		// 不带discriminator、只按owner匹配的格式会匹配该程序的所有账户
+		r.RegisterBorsh("custom", program, nil, func() any { return new(state) })
+	}
+	pool := &Account{Address: solana.NewWallet().PublicKey(), Owner: program, Data: poolData(solana.PublicKey{})}
+	counter := &Account{Address: solana.NewWallet().PublicKey(), Owner: program, Data: []byte{5, 0, 0, 0, 0, 0, 0, 0}}
+
+	// 后注册的格式排在内置格式之后，池子仍按内置格式解码
+	r := DefaultRegistry()
+	custom(r)
+	if err := r.Decode(pool); err != nil || pool.Layout != "token-swap-pool" {
+		t.Fatalf("池子匹配到 %q, %v", pool.Layout, err)
+	}
+	if err := r.Decode(counter); err != nil || counter.Layout != "custom" || counter.Value.(*state).Count != 5 {
+		t.Fatalf("匹配到 %q, %v, %+v", counter.Layout, err, counter.Value)
+	}
+
+	// 先注册的优先
+	r = NewRegistry()
+	custom(r)
+	r.Register(TokenSwapPoolLayout)
+	if err := r.Decode(pool); err != nil || pool.Layout != "custom" {
+		t.Fatalf("池子匹配到 %q, %v", pool.Layout, err)
+	}
+
+	// 匹配后解码失败时报错，不再尝试后面的格式
+	uninit := &Account{Address: solana.NewWallet().PublicKey(), Owner: solana.SystemProgramID, Data: nonceData(t, program, 0)}
+	if err := DefaultRegistry().Decode(uninit); !errors.Is(err, nonce.ErrNotNonceAccount) || uninit.Layout != "nonce" {
+		t.Fatalf("未初始化的nonce账户匹配到 %q, err = %v", uninit.Layout, err)
+	}
+}
@@ -0,0 +1,133 @@
+package account
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// 程序派生地址（PDA）：由种子和程序ID推导，不在ed25519曲线上，没有私钥，只有程序能代它签名。
+// FindPDA从bump=255往下找第一个不在曲线上的地址（规范bump），程序端用同样的种子和bump校验。
+// 种子按程序中的写法构造：字符串取UTF-8字节，公钥取32字节，整数为小端序
+
+// Seed PDA的一个种子，最长32字节
+type Seed []byte
+
+// SeedString 字符串种子，例如b"vault"
+func SeedString(s string) Seed { return Seed(s) }
+
+// SeedPubkey 公钥种子，例如user.key().as_ref()
+func SeedPubkey(key solana.PublicKey) Seed { return Seed(key[:]) }
+
+// SeedBytes 原始字节种子
+func SeedBytes(b []byte) Seed { return Seed(b) }
+
+// SeedU8 单字节种子
+func SeedU8(v uint8) Seed { return Seed{v} }
+
+// SeedU16 小端序u16种子，例如id.to_le_bytes()
+func SeedU16(v uint16) Seed { return binary.LittleEndian.AppendUint16(nil, v) }
+
+// SeedU32 小端序u32种子
+func SeedU32(v uint32) Seed { return binary.LittleEndian.AppendUint32(nil, v) }
+
+// SeedU64 小端序u64种子
+func SeedU64(v uint64) Seed { return binary.LittleEndian.AppendUint64(nil, v) }
+
+// ParseSeed 解析命令行中"类型:值"形式的种子，类型为string、pubkey、hex、u8、u16、u32、u64
+func ParseSeed(s string) (Seed, error) {
+	kind, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, fmt.Errorf("种子 %q 应为\"类型:值\"，例如string:vault、pubkey:<地址>、u64:1", s)
+	}
+	switch kind {
+	case "string", "str":
+		return SeedString(value), nil
+	case "pubkey":
+		key, err := solana.PublicKeyFromBase58(value)
+		if err != nil {
+			return nil, fmt.Errorf("无效的公钥种子 %s: %v", value, err)
+		}
+		return SeedPubkey(key), nil
+	case "hex":
+		b, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("无效的hex种子 %s: %v", value, err)
+		}
+		return SeedBytes(b), nil
+	case "u8", "u16", "u32", "u64":
+		bits, _ := strconv.Atoi(kind[1:])
+		n, err := strconv.ParseUint(value, 10, bits)
+		if err != nil {
+			return nil, fmt.Errorf("无效的%s种子 %s: %v", kind, value, err)
+		}
+		switch bits {
+		case 8:
+			return SeedU8(uint8(n)), nil
+		case 16:
+			return SeedU16(uint16(n)), nil
+		case 32:
+			return SeedU32(uint32(n)), nil
+		default:
+			return SeedU64(n), nil
+		}
+	default:
+		return nil, fmt.Errorf("未知的种子类型 %s（可选 string/pubkey/hex/u8/u16/u32/u64）", kind)
+	}
+}
+
+// PDA 推导出的地址和规范bump
+type PDA struct {
+	Address solana.PublicKey
+	Bump    uint8
+}
+
+func (p PDA) String() string {
+	return fmt.Sprintf("%s (bump %d)", p.Address, p.Bump)
+}
+
+// FindPDA 用种子和programID推导PDA及规范bump
+func FindPDA(programID solana.PublicKey, seeds ...Seed) (PDA, error) {
+	// bump本身也占一个种子位置
+	raw, err := checkSeeds(seeds, solana.MaxSeeds-1)
+	if err != nil {
+		return PDA{}, err
+	}
+	address, bump, err := solana.FindProgramAddress(raw, programID)
+	if err != nil {
+		return PDA{}, fmt.Errorf("推导PDA失败: %v", err)
+	}
+	return PDA{Address: address, Bump: bump}, nil
+}
+
+// CreatePDA 用已知的bump计算PDA，例如账户里保存的bump；种子和bump组合落在曲线上时报错
+func CreatePDA(programID solana.PublicKey, bump uint8, seeds ...Seed) (solana.PublicKey, error) {
+	raw, err := checkSeeds(seeds, solana.MaxSeeds-1)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	address, err := solana.CreateProgramAddress(append(raw, []byte{bump}), programID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("用bump %d 计算PDA失败: %v", bump, err)
+	}
+	return address, nil
+}
+
+// checkSeeds 检查种子数量和长度，给出比solana-go更明确的错误
+func checkSeeds(seeds []Seed, maxSeeds int) ([][]byte, error) {
+	if len(seeds) > maxSeeds {
+		return nil, fmt.Errorf("种子最多 %d 个，实际 %d 个", maxSeeds, len(seeds))
+	}
+	raw := make([][]byte, len(seeds))
+	for i, seed := range seeds {
+		if len(seed) > solana.MaxSeedLength {
+			return nil, fmt.Errorf("第%d个种子长 %d 字节，超过 %d 字节", i+1, len(seed), solana.MaxSeedLength)
+		}
+		raw[i] = seed
+	}
+	return raw, nil
+}
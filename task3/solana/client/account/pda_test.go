@@ -0,0 +1,116 @@
+package account
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestParseSeed(t *testing.T) {
+	key := solana.NewWallet().PublicKey()
+	tests := []struct {
+		in      string
+		want    Seed
+		wantErr string
+	}{
+		{in: "string:vault", want: Seed("vault")},
+		{in: "str:a:b", want: Seed("a:b")},
+		{in: "string:", want: Seed{}},
+		{in: "pubkey:" + key.String(), want: Seed(key[:])},
+		{in: "hex:00ff10", want: Seed{0x00, 0xff, 0x10}},
+		{in: "u8:255", want: Seed{0xff}},
+		{in: "u16:258", want: Seed{0x02, 0x01}},
+		{in: "u32:1", want: Seed{1, 0, 0, 0}},
+		{in: "u64:1", want: Seed{1, 0, 0, 0, 0, 0, 0, 0}},
+		{in: "u8:256", wantErr: "无效的u8种子"},
+		{in: "u64:-1", wantErr: "无效的u64种子"},
+		{in: "hex:0g", wantErr: "无效的hex种子"},
+		{in: "pubkey:abc", wantErr: "无效的公钥种子"},
+		{in: "vault", wantErr: "类型:值"},
+		{in: "i64:1", wantErr: "未知的种子类型"},
+	}
+	for _, tt := range tests {
+		got, err := ParseSeed(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseSeed(%q) err = %v，期望包含 %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("ParseSeed(%q) = %x, %v，期望 %x", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFindPDA(t *testing.T) {
+	owner, mint := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	// 关联代币账户就是以所有者、Token程序和mint为种子的PDA
+	pda, err := FindPDA(solana.SPLAssociatedTokenAccountProgramID, SeedPubkey(owner), SeedPubkey(solana.TokenProgramID), SeedPubkey(mint))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ata, bump, err := solana.FindAssociatedTokenAddress(owner, mint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pda.Address != ata || pda.Bump != bump {
+		t.Fatalf("FindPDA = %s，期望 %s (bump %d)", pda, ata, bump)
+	}
+
+	// 用规范bump重新计算得到同一个地址
+	program := solana.NewWallet().PublicKey()
+	seeds := []Seed{SeedString("vault"), SeedPubkey(owner), SeedU64(7)}
+	pda, err = FindPDA(program, seeds...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr, err := CreatePDA(program, pda.Bump, seeds...); err != nil || addr != pda.Address {
+		t.Fatalf("CreatePDA = %s, %v，期望 %s", addr, err, pda.Address)
+	}
+}
+
+func TestCreatePDAOnCurve(t *testing.T) {
+	program := solana.NewWallet().PublicKey()
+	// 规范bump以上的bump都落在曲线上，找一组规范bump小于255的种子
+	for i := 0; i < 100; i++ {
+		seed := SeedString(fmt.Sprintf("vault-%d", i))
+		pda, err := FindPDA(program, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pda.Bump == 255 {
+			continue
+		}
+		if _, err := CreatePDA(program, pda.Bump+1, seed); err == nil {
+			t.Fatalf("bump %d 落在曲线上，应报错", pda.Bump+1)
+		}
+		return
+	}
+	t.Fatal("没有找到规范bump小于255的种子")
+}
+
+func TestSeedLimits(t *testing.T) {
+	program := solana.NewWallet().PublicKey()
+	// bump占一个种子位置，最多15个种子
+	seeds := make([]Seed, solana.MaxSeeds)
+	for i := range seeds {
+		seeds[i] = SeedU8(uint8(i))
+	}
+	if _, err := FindPDA(program, seeds[:solana.MaxSeeds-1]...); err != nil {
+		t.Fatalf("15个种子: %v", err)
+	}
+	if _, err := FindPDA(program, seeds...); err == nil || !strings.Contains(err.Error(), "种子最多") {
+		t.Fatalf("16个种子 err = %v", err)
+	}
+	if _, err := CreatePDA(program, 255, seeds...); err == nil || !strings.Contains(err.Error(), "种子最多") {
+		t.Fatalf("CreatePDA 16个种子 err = %v", err)
+	}
+	long := SeedBytes(make([]byte, solana.MaxSeedLength+1))
+	if _, err := FindPDA(program, SeedString("ok"), long); err == nil || !strings.Contains(err.Error(), "第2个种子") {
+		t.Fatalf("超长种子 err = %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package anchor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"solana-go/utils"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Anchor程序的运行时支持，供anchorgen生成的客户端代码使用（见client/anchor/gen和cmd/anchorgen）。
+// Anchor用8字节的discriminator区分指令、账户和事件，后面跟Borsh编码的数据：
+// 指令为sha256("global:<snake_case名>")[:8]，账户为sha256("account:<类型名>")[:8]，
+// 事件为sha256("event:<类型名>")[:8]
+
+// DiscriminatorSize discriminator的长度
+const DiscriminatorSize = 8
+
+var (
+	// ErrDiscriminatorMismatch 数据的discriminator与期望的类型不符，通常是读错了账户
+	ErrDiscriminatorMismatch = errors.New("discriminator不匹配")
+	// ErrAccountNotFound 账户不存在
+	ErrAccountNotFound = errors.New("账户不存在")
+)
+
+// Discriminator 指令、账户或事件数据开头的8字节标识
+type Discriminator [DiscriminatorSize]byte
+
+func (d Discriminator) String() string {
+	return hex.EncodeToString(d[:])
+}
+
+// InstructionDiscriminator 指令的discriminator，name可以是IDL中的snake_case或camelCase名字
+func InstructionDiscriminator(name string) Discriminator {
+	return sighash("global", SnakeCase(name))
+}
+
+// AccountDiscriminator 账户的discriminator，name为账户类型名，例如Counter
+func AccountDiscriminator(name string) Discriminator {
+	return sighash("account", name)
+}
+
+// EventDiscriminator 事件的discriminator，name为事件类型名
+func EventDiscriminator(name string) Discriminator {
+	return sighash("event", name)
+}
+
+func sighash(namespace, name string) Discriminator {
+	sum := sha256.Sum256([]byte(namespace + ":" + name))
+	var d Discriminator
+	copy(d[:], sum[:DiscriminatorSize])
+	return d
+}
+
+// SnakeCase 把camelCase转成snake_case，与Anchor计算指令discriminator时的规则一致
+func SnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// 连续大写（如ID）只在单词边界处断开
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// EncodeInstruction 指令数据：discriminator加Borsh编码的参数，args为参数结构体的指针，没有参数时为nil
+func EncodeInstruction(discriminator Discriminator, args any) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.Write(discriminator[:])
+	if args == nil {
+		return buf.Bytes(), nil
+	}
+	if err := bin.NewBorshEncoder(buf).Encode(args); err != nil {
+		return nil, fmt.Errorf("编码指令参数失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode 检查data开头的discriminator并把后面的Borsh数据解码到v
+func Decode(data []byte, discriminator Discriminator, v any) error {
+	if len(data) < DiscriminatorSize {
+		return fmt.Errorf("%w: 数据只有 %d 字节", ErrDiscriminatorMismatch, len(data))
+	}
+	if !bytes.Equal(data[:DiscriminatorSize], discriminator[:]) {
+		return fmt.Errorf("%w: 期望 %s，实际为 %x", ErrDiscriminatorMismatch, discriminator, data[:DiscriminatorSize])
+	}
+	if err := bin.NewBorshDecoder(data[DiscriminatorSize:]).Decode(v); err != nil {
+		return fmt.Errorf("Borsh解码失败: %v", err)
+	}
+	return nil
+}
+
+// FetchAccount 读取address的数据并解码到v，账户的owner必须是programID
+func FetchAccount(ctx context.Context, rpcClient *rpc.Client, programID, address solana.PublicKey, discriminator Discriminator, v any) error {
+	res, err := rpcClient.GetMultipleAccountsWithOpts(ctx, []solana.PublicKey{address}, &rpc.GetMultipleAccountsOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return fmt.Errorf("读取账户 %s 失败: %w", address, utils.ClassifyError(err))
+	}
+	if len(res.Value) == 0 || res.Value[0] == nil {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+	acc := res.Value[0]
+	if !acc.Owner.Equals(programID) {
+		return fmt.Errorf("账户 %s 的owner为 %s，不属于程序 %s", address, acc.Owner, programID)
+	}
+	if err := Decode(acc.Data.GetBinary(), discriminator, v); err != nil {
+		return fmt.Errorf("解析账户 %s 失败: %w", address, err)
+	}
+	return nil
+}
+
+// OptionalMeta 可选账户的AccountMeta：Anchor约定未提供的可选账户用程序ID占位，只读且不签名
+func OptionalMeta(account, programID solana.PublicKey, writable, signer bool) *solana.AccountMeta {
+	if account.IsZero() {
+		return solana.NewAccountMeta(programID, false, false)
+	}
+	return solana.NewAccountMeta(account, writable, signer)
+}
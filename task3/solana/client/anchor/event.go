@@ -0,0 +1,88 @@
+package anchor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Anchor的emit!把事件以"Program data: <base64>"的形式写进交易日志，内容为事件discriminator加Borsh数据。
+// 日志里混有CPI调用的其他程序的输出，按"Program <id> invoke"/"success"/"failed"维护调用栈，
+// 只解析当前正在执行目标程序时写出的事件
+
+const (
+	invokePrefix = "Program "
+	dataPrefix   = "Program data: "
+)
+
+// EventType 一种事件的名字和构造函数，New返回用于解码的结构体指针
+type EventType struct {
+	Name string
+	New  func() any
+}
+
+// Event 从日志中解析出的事件
+type Event struct {
+	Name string
+	Data any // EventType.New返回的结构体指针
+}
+
+// ParseEvents 从交易日志中解析programID的事件，types中没有的discriminator被忽略（例如IDL比程序旧）。
+// 日志被节点截断（"Log truncated"）时截断之后的事件会丢失
+func ParseEvents(logs []string, programID solana.PublicKey, types map[Discriminator]EventType) ([]Event, error) {
+	program := programID.String()
+	var (
+		stack  []string
+		events []Event
+	)
+	for i, line := range logs {
+		if data, ok := strings.CutPrefix(line, dataPrefix); ok {
+			if len(stack) == 0 || stack[len(stack)-1] != program {
+				continue
+			}
+			event, ok, err := decodeEvent(data, types)
+			if err != nil {
+				return events, fmt.Errorf("解析第%d行日志的事件失败: %w", i+1, err)
+			}
+			if ok {
+				events = append(events, event)
+			}
+			continue
+		}
+		rest, ok := strings.CutPrefix(line, invokePrefix)
+		if !ok {
+			continue
+		}
+		id, status, _ := strings.Cut(rest, " ")
+		switch {
+		case strings.HasPrefix(status, "invoke ["):
+			stack = append(stack, id)
+		case status == "success" || strings.HasPrefix(status, "failed"):
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return events, nil
+}
+
+func decodeEvent(data string, types map[Discriminator]EventType) (Event, bool, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+	if err != nil || len(raw) < DiscriminatorSize {
+		// 不是事件，例如程序用sol_log_data写出的其他数据
+		return Event{}, false, nil
+	}
+	var d Discriminator
+	copy(d[:], raw)
+	typ, ok := types[d]
+	if !ok {
+		return Event{}, false, nil
+	}
+	v := typ.New()
+	if err := Decode(raw, d, v); err != nil {
+		return Event{}, false, fmt.Errorf("%s: %w", typ.Name, err)
+	}
+	return Event{Name: typ.Name, Data: v}, true, nil
+}
@@ -0,0 +1,517 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"strings"
+	"unicode"
+
+	"solana-go/client/anchor"
+)
+
+// 由Anchor IDL生成Go客户端：每条指令生成账户结构体、参数结构体和NewXxxInstruction构造函数，
+// 每种账户生成结构体和带discriminator检查的DecodeXxx/FetchXxx，事件生成结构体和ParseEvents，
+// 自定义类型生成对应的结构体或枚举。生成的代码依赖运行时包client/anchor
+//
+// 类型对应关系：u8~u64/i8~i64/f32/f64/bool/string为同名Go类型，u128/i128为bin.Uint128/bin.Int128，
+// bytes为[]byte，pubkey为solana.PublicKey，vec<T>为[]T，[T; N]为[N]T，option<T>为带bin:"optional"标签的*T；
+// 只有unit变体的枚举生成uint8常量，带数据的枚举生成bin.BorshEnum结构体，Enum字段表示当前变体
+
+// 修改生成器后用go generate或go test -update更新golden文件，go test会确认新旧两种格式IDL的生成结果都与之相同
+//go:generate go run ../../../cmd/anchorgen -idl testdata/counter.json -out testdata/counter.golden
+
+// DefaultRuntime 运行时包的导入路径
+const DefaultRuntime = "solana-go/client/anchor"
+
+// Options 生成参数
+type Options struct {
+	Package string // 包名，默认由程序名转换
+	Runtime string // 运行时包的导入路径，默认DefaultRuntime
+}
+
+// Generate 生成gofmt格式化后的Go源码
+func Generate(idl *IDL, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = packageName(idl.Name)
+	}
+	if opts.Runtime == "" {
+		opts.Runtime = DefaultRuntime
+	}
+	g := &generator{idl: idl, opts: opts, imports: make(map[string]bool), defined: make(map[string]bool)}
+	for _, list := range [][]TypeDef{idl.Types, idl.Accounts, idl.Events} {
+		for _, def := range list {
+			if g.defined[def.Name] {
+				return nil, fmt.Errorf("类型%s重复定义", def.Name)
+			}
+			g.defined[def.Name] = true
+		}
+	}
+	if err := g.generate(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by anchorgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "// Package %s 是Anchor程序%s的Go客户端，由IDL生成\n", opts.Package, idl.Name)
+	fmt.Fprintf(&out, "package %s\n\n", opts.Package)
+	out.WriteString(g.importBlock())
+	out.Write(g.body.Bytes())
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化生成的代码失败: %v", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	idl     *IDL
+	opts    Options
+	body    bytes.Buffer
+	imports map[string]bool
+	defined map[string]bool
+}
+
+func (g *generator) p(format string, args ...any) {
+	fmt.Fprintf(&g.body, format, args...)
+	g.body.WriteByte('\n')
+}
+
+func (g *generator) use(path string) {
+	g.imports[path] = true
+}
+
+// importBlock 标准库、运行时包、第三方库分组，与仓库中其他代码一致
+func (g *generator) importBlock() string {
+	var std, local, external []string
+	for path := range g.imports {
+		switch {
+		case path == g.opts.Runtime:
+			local = append(local, path)
+		case !strings.Contains(strings.Split(path, "/")[0], "."):
+			std = append(std, path)
+		default:
+			external = append(external, path)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("import (\n")
+	first := true
+	for _, group := range [][]string{std, local, external} {
+		if len(group) == 0 {
+			continue
+		}
+		if !first {
+			b.WriteByte('\n')
+		}
+		first = false
+		slices.Sort(group)
+		for _, path := range group {
+			if path == "github.com/gagliardetto/binary" {
+				fmt.Fprintf(&b, "\tbin %q\n", path)
+			} else {
+				fmt.Fprintf(&b, "\t%q\n", path)
+			}
+		}
+	}
+	b.WriteString(")\n\n")
+	return b.String()
+}
+
+func (g *generator) generate() error {
+	g.use("github.com/gagliardetto/solana-go")
+	if g.idl.Address != "" {
+		g.p("// ProgramID 程序地址，部署到其他地址时可以修改")
+		g.p("var ProgramID = solana.MustPublicKeyFromBase58(%q)", g.idl.Address)
+	} else {
+		g.p("// ProgramID 程序地址，IDL中没有给出，使用前必须设置")
+		g.p("var ProgramID solana.PublicKey")
+	}
+	g.p("")
+
+	for _, inst := range g.idl.Instructions {
+		if err := g.instruction(inst); err != nil {
+			return fmt.Errorf("指令%s: %v", inst.Name, err)
+		}
+	}
+	for _, def := range g.idl.Accounts {
+		if err := g.account(def); err != nil {
+			return fmt.Errorf("账户%s: %v", def.Name, err)
+		}
+	}
+	if len(g.idl.Events) > 0 {
+		if err := g.events(); err != nil {
+			return err
+		}
+	}
+	for _, def := range g.idl.Types {
+		if err := g.typeDef(def, ""); err != nil {
+			return fmt.Errorf("类型%s: %v", def.Name, err)
+		}
+	}
+	return nil
+}
+
+func (g *generator) instruction(inst Instruction) error {
+	g.use(g.opts.Runtime)
+	name := goName(inst.Name)
+	disc, err := discriminator(inst.Discriminator, anchor.InstructionDiscriminator(inst.Name))
+	if err != nil {
+		return err
+	}
+
+	g.p("// %sInstructionDiscriminator %s指令的discriminator", name, name)
+	g.p("var %sInstructionDiscriminator = %s", name, disc)
+	g.p("")
+
+	g.p("// %sAccounts %s指令的账户", name, name)
+	g.p("type %sAccounts struct {", name)
+	for _, a := range inst.Accounts {
+		g.docs("\t", a.Docs)
+		var notes []string
+		if a.Writable {
+			notes = append(notes, "可写")
+		}
+		if a.Signer {
+			notes = append(notes, "签名")
+		}
+		if a.Optional {
+			notes = append(notes, "可选，留空时传程序ID")
+		}
+		if a.Address != "" {
+			notes = append(notes, "留空时使用"+a.Address)
+		}
+		comment := ""
+		if len(notes) > 0 {
+			comment = " // " + strings.Join(notes, "，")
+		}
+		g.p("\t%s solana.PublicKey%s", goName(a.Name), comment)
+	}
+	g.p("}")
+	g.p("")
+
+	hasArgs := len(inst.Args) > 0
+	if hasArgs {
+		g.p("// %sArgs %s指令的参数", name, name)
+		if err := g.structType(name+"Args", inst.Args); err != nil {
+			return err
+		}
+		g.p("")
+	}
+
+	if len(inst.Docs) > 0 {
+		g.docs("", append([]string{fmt.Sprintf("New%sInstruction 构造%s指令", name, name), ""}, inst.Docs...))
+	} else {
+		g.p("// New%sInstruction 构造%s指令", name, name)
+	}
+	args, argsValue := "", "nil"
+	if hasArgs {
+		args, argsValue = fmt.Sprintf(", args %sArgs", name), "&args"
+	}
+	g.p("func New%sInstruction(accounts %sAccounts%s) (solana.Instruction, error) {", name, name, args)
+	for _, a := range inst.Accounts {
+		if a.Address == "" {
+			continue
+		}
+		field := goName(a.Name)
+		g.p("\tif accounts.%s.IsZero() {", field)
+		g.p("\t\taccounts.%s = solana.MustPublicKeyFromBase58(%q)", field, a.Address)
+		g.p("\t}")
+	}
+	g.p("\tdata, err := anchor.EncodeInstruction(%sInstructionDiscriminator, %s)", name, argsValue)
+	g.p("\tif err != nil {")
+	g.p("\t\treturn nil, err")
+	g.p("\t}")
+	g.p("\tmetas := solana.AccountMetaSlice{")
+	for _, a := range inst.Accounts {
+		if a.Optional {
+			g.p("\t\tanchor.OptionalMeta(accounts.%s, ProgramID, %t, %t),", goName(a.Name), a.Writable, a.Signer)
+		} else {
+			g.p("\t\tsolana.NewAccountMeta(accounts.%s, %t, %t),", goName(a.Name), a.Writable, a.Signer)
+		}
+	}
+	g.p("\t}")
+	g.p("\treturn solana.NewInstruction(ProgramID, metas, data), nil")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+func (g *generator) account(def TypeDef) error {
+	g.use(g.opts.Runtime)
+	g.use("context")
+	g.use("github.com/gagliardetto/solana-go/rpc")
+	name := goName(def.Name)
+	disc, err := discriminator(def.Discriminator, anchor.AccountDiscriminator(def.Name))
+	if err != nil {
+		return err
+	}
+	g.p("// %sAccountDiscriminator %s账户数据开头的discriminator", name, name)
+	g.p("var %sAccountDiscriminator = %s", name, disc)
+	g.p("")
+	if err := g.typeDef(def, name+"账户"); err != nil {
+		return err
+	}
+	g.p("// Decode%s 解析%s账户数据，discriminator不符时返回anchor.ErrDiscriminatorMismatch", name, name)
+	g.p("func Decode%s(data []byte) (*%s, error) {", name, name)
+	g.p("\tv := new(%s)", name)
+	g.p("\tif err := anchor.Decode(data, %sAccountDiscriminator, v); err != nil {", name)
+	g.p("\t\treturn nil, err")
+	g.p("\t}")
+	g.p("\treturn v, nil")
+	g.p("}")
+	g.p("")
+	g.p("// Fetch%s 读取并解析%s账户，账户必须属于ProgramID", name, name)
+	g.p("func Fetch%s(ctx context.Context, rpcClient *rpc.Client, address solana.PublicKey) (*%s, error) {", name, name)
+	g.p("\tv := new(%s)", name)
+	g.p("\tif err := anchor.FetchAccount(ctx, rpcClient, ProgramID, address, %sAccountDiscriminator, v); err != nil {", name)
+	g.p("\t\treturn nil, err")
+	g.p("\t}")
+	g.p("\treturn v, nil")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+func (g *generator) events() error {
+	g.use(g.opts.Runtime)
+	names := make([]string, 0, len(g.idl.Events))
+	for _, def := range g.idl.Events {
+		name := goName(def.Name)
+		disc, err := discriminator(def.Discriminator, anchor.EventDiscriminator(def.Name))
+		if err != nil {
+			return fmt.Errorf("事件%s: %v", def.Name, err)
+		}
+		g.p("// %sEventDiscriminator %s事件的discriminator", name, name)
+		g.p("var %sEventDiscriminator = %s", name, disc)
+		g.p("")
+		if err := g.typeDef(def, name+"事件"); err != nil {
+			return fmt.Errorf("事件%s: %v", def.Name, err)
+		}
+		names = append(names, name)
+	}
+
+	g.p("// EventTypes 程序的所有事件，按discriminator索引")
+	g.p("var EventTypes = map[anchor.Discriminator]anchor.EventType{")
+	for _, name := range names {
+		g.p("\t%sEventDiscriminator: {Name: %q, New: func() any { return new(%s) }},", name, name, name)
+	}
+	g.p("}")
+	g.p("")
+	g.p("// ParseEvents 从交易日志中解析程序的事件，Event.Data为*%s等事件结构体", names[0])
+	g.p("func ParseEvents(logs []string) ([]anchor.Event, error) {")
+	g.p("\treturn anchor.ParseEvents(logs, ProgramID, EventTypes)")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// typeDef 生成结构体或枚举，what是没有文档时的说明
+func (g *generator) typeDef(def TypeDef, what string) error {
+	name := goName(def.Name)
+	switch {
+	case len(def.Docs) > 0:
+		g.docs("", append([]string{name + " " + def.Docs[0]}, def.Docs[1:]...))
+	case what != "":
+		g.p("// %s %s", name, what)
+	default:
+		g.p("// %s IDL中定义的%s类型", name, def.Name)
+	}
+	if def.Kind == "struct" {
+		if err := g.structType(name, def.Fields); err != nil {
+			return err
+		}
+		g.p("")
+		return nil
+	}
+	if len(def.Variants) == 0 {
+		return fmt.Errorf("枚举没有变体")
+	}
+	if !slices.ContainsFunc(def.Variants, func(v Variant) bool { return len(v.Fields) > 0 }) {
+		g.unitEnum(name, def.Variants)
+		return nil
+	}
+	return g.dataEnum(name, def.Variants)
+}
+
+func (g *generator) structType(name string, fields []Field) error {
+	g.p("type %s struct {", name)
+	for _, f := range fields {
+		typ, tag, err := g.fieldType(f.Type)
+		if err != nil {
+			return fmt.Errorf("字段%s: %v", f.Name, err)
+		}
+		g.docs("\t", f.Docs)
+		g.p("\t%s %s%s", goName(f.Name), typ, tag)
+	}
+	g.p("}")
+	return nil
+}
+
+// unitEnum 只有unit变体的枚举，Borsh编码为一个字节
+func (g *generator) unitEnum(name string, variants []Variant) {
+	g.use("fmt")
+	g.p("type %s uint8", name)
+	g.p("")
+	g.p("const (")
+	for i, v := range variants {
+		if i == 0 {
+			g.p("\t%s%s %s = iota", name, goName(v.Name), name)
+		} else {
+			g.p("\t%s%s", name, goName(v.Name))
+		}
+	}
+	g.p(")")
+	g.p("")
+	g.p("func (v %s) String() string {", name)
+	g.p("\tswitch v {")
+	for _, v := range variants {
+		g.p("\tcase %s%s:", name, goName(v.Name))
+		g.p("\t\treturn %q", v.Name)
+	}
+	g.p("\tdefault:")
+	g.p("\t\treturn fmt.Sprintf(\"%s(%%d)\", uint8(v))", name)
+	g.p("\t}")
+	g.p("}")
+	g.p("")
+}
+
+// dataEnum 带数据的枚举：Enum为当前变体的序号，只有对应变体的字段有效
+func (g *generator) dataEnum(name string, variants []Variant) error {
+	g.use("github.com/gagliardetto/binary")
+	g.p("type %s struct {", name)
+	g.p("\tEnum bin.BorshEnum `borsh_enum:\"true\"`")
+	for _, v := range variants {
+		if len(v.Fields) == 0 {
+			g.p("\t%s bin.EmptyVariant", goName(v.Name))
+		} else {
+			g.p("\t%s %s%s", goName(v.Name), name, goName(v.Name))
+		}
+	}
+	g.p("}")
+	g.p("")
+	g.p("// %s的变体序号", name)
+	g.p("const (")
+	for i, v := range variants {
+		if i == 0 {
+			g.p("\t%s%sVariant bin.BorshEnum = iota", name, goName(v.Name))
+		} else {
+			g.p("\t%s%sVariant", name, goName(v.Name))
+		}
+	}
+	g.p(")")
+	g.p("")
+	for _, v := range variants {
+		if len(v.Fields) == 0 {
+			continue
+		}
+		g.p("// %s%s %s的%s变体", name, goName(v.Name), name, v.Name)
+		if err := g.structType(name+goName(v.Name), v.Fields); err != nil {
+			return fmt.Errorf("变体%s: %v", v.Name, err)
+		}
+		g.p("")
+	}
+	return nil
+}
+
+// fieldType 字段的Go类型和结构体标签，option只能直接作为字段
+func (g *generator) fieldType(t *Type) (string, string, error) {
+	if t.Kind == "option" {
+		elem, err := g.goType(t.Elem)
+		if err != nil {
+			return "", "", err
+		}
+		return "*" + elem, " `bin:\"optional\"`", nil
+	}
+	typ, err := g.goType(t)
+	return typ, "", err
+}
+
+func (g *generator) goType(t *Type) (string, error) {
+	switch t.Kind {
+	case "bool", "string":
+		return t.Kind, nil
+	case "u8", "u16", "u32", "u64":
+		return "uint" + t.Kind[1:], nil
+	case "i8", "i16", "i32", "i64":
+		return "int" + t.Kind[1:], nil
+	case "f32", "f64":
+		return "float" + t.Kind[1:], nil
+	case "u128":
+		g.use("github.com/gagliardetto/binary")
+		return "bin.Uint128", nil
+	case "i128":
+		g.use("github.com/gagliardetto/binary")
+		return "bin.Int128", nil
+	case "bytes":
+		return "[]byte", nil
+	case "pubkey":
+		return "solana.PublicKey", nil
+	case "vec":
+		elem, err := g.goType(t.Elem)
+		return "[]" + elem, err
+	case "array":
+		elem, err := g.goType(t.Elem)
+		return fmt.Sprintf("[%d]%s", t.Len, elem), err
+	case "defined":
+		if !g.defined[t.Defined] {
+			return "", fmt.Errorf("引用了未定义的类型%s", t.Defined)
+		}
+		return goName(t.Defined), nil
+	case "option":
+		return "", fmt.Errorf("不支持嵌套在vec或array中的option")
+	}
+	return "", fmt.Errorf("不支持的类型%s", t.Kind)
+}
+
+// docs 输出IDL中的文档注释
+func (g *generator) docs(indent string, lines []string) {
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line == "" {
+			g.p("%s//", indent)
+		} else {
+			g.p("%s// %s", indent, line)
+		}
+	}
+}
+
+// discriminator IDL给出的discriminator与按名字计算的不一致时以IDL为准
+func discriminator(given []byte, computed anchor.Discriminator) (string, error) {
+	d := computed
+	if given != nil {
+		if len(given) != anchor.DiscriminatorSize {
+			return "", fmt.Errorf("discriminator长度为%d，只支持%d字节", len(given), anchor.DiscriminatorSize)
+		}
+		copy(d[:], given)
+	}
+	parts := make([]string, len(d))
+	for i, b := range d {
+		parts[i] = fmt.Sprintf("%d", b)
+	}
+	return "anchor.Discriminator{" + strings.Join(parts, ", ") + "}", nil
+}
+
+// goName 把snake_case或camelCase名字转换为导出的Go名字
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// packageName 程序名转换为包名：小写，去掉下划线和连字符
+func packageName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
@@ -0,0 +1,65 @@
+package gen
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "用testdata/counter.json的生成结果更新golden文件")
+
+const goldenFile = "testdata/counter.golden"
+
+func generateFile(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idl, err := Parse(data)
+	if err != nil {
+		t.Fatalf("解析 %s 失败: %v", path, err)
+	}
+	src, err := Generate(idl, Options{})
+	if err != nil {
+		t.Fatalf("由 %s 生成代码失败: %v", path, err)
+	}
+	return src
+}
+
+// TestGolden 新格式（Anchor 0.30+）和旧格式的IDL生成的代码都与golden文件一致
+func TestGolden(t *testing.T) {
+	if *update {
+		if err := os.WriteFile(goldenFile, generateFile(t, "testdata/counter.json"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"counter.json", "counter_legacy.json"} {
+		t.Run(name, func(t *testing.T) {
+			got := generateFile(t, filepath.Join("testdata", name))
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%s 的生成结果与 %s 不一致（第%d行起），确认改动后用 go test -update 重新生成", name, goldenFile, firstDiffLine(got, want))
+			}
+		})
+	}
+}
+
+// firstDiffLine 第一处不同所在的行号，从1开始
+func firstDiffLine(a, b []byte) int {
+	line := 1
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return line
+		}
+		if a[i] == '\n' {
+			line++
+		}
+	}
+	return line
+}
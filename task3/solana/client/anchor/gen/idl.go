@@ -0,0 +1,425 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// IDL解析：同时支持Anchor 0.30+的新格式和之前的旧格式，解析后统一为下面的结构。
+// 两种格式的主要区别：
+//   - 程序地址：新格式为顶层address，旧格式为metadata.address
+//   - 账户标志：新格式为writable/signer/optional，旧格式为isMut/isSigner/isOptional
+//   - 公钥类型：新格式为pubkey，旧格式为publicKey
+//   - 自定义类型引用：新格式为{"defined": {"name": "X"}}，旧格式为{"defined": "X"}
+//   - 账户和事件：新格式只列出名字和discriminator，结构定义在types中；旧格式直接带type或fields，没有discriminator
+
+// IDL 统一后的程序接口描述
+type IDL struct {
+	Name         string
+	Address      string // 程序地址，可以为空
+	Instructions []Instruction
+	Accounts     []TypeDef // 账户类型，Discriminator已填好
+	Events       []TypeDef // 事件类型，Discriminator已填好
+	Types        []TypeDef // 其他自定义类型（不含账户和事件）
+}
+
+// Instruction 一条指令
+type Instruction struct {
+	Name          string
+	Docs          []string
+	Discriminator []byte // IDL中没有时为nil，由生成器计算
+	Accounts      []AccountItem
+	Args          []Field
+}
+
+// AccountItem 指令需要的一个账户，嵌套的账户组已展开
+type AccountItem struct {
+	Name     string
+	Docs     []string
+	Writable bool
+	Signer   bool
+	Optional bool
+	Address  string // 固定地址，例如system_program
+}
+
+// TypeDef 自定义类型
+type TypeDef struct {
+	Name          string
+	Docs          []string
+	Discriminator []byte
+	Kind          string // struct或enum
+	Fields        []Field
+	Variants      []Variant
+}
+
+// Variant 枚举的一个变体；Fields为空时是unit变体，Tuple时字段没有名字
+type Variant struct {
+	Name   string
+	Fields []Field
+	Tuple  bool
+}
+
+// Field 结构体字段或指令参数
+type Field struct {
+	Name string
+	Docs []string
+	Type *Type
+}
+
+// Type 类型引用，Kind为以下之一：
+// 基本类型名（bool、u8~u128、i8~i128、f32、f64、string、bytes、pubkey），或vec、option、array、defined
+type Type struct {
+	Kind    string
+	Elem    *Type  // vec、option、array的元素类型
+	Len     int    // array的长度
+	Defined string // defined引用的类型名
+}
+
+// Parse 解析IDL JSON
+func Parse(data []byte) (*IDL, error) {
+	var raw rawIDL
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("解析IDL失败: %v", err)
+	}
+	idl := &IDL{Name: raw.Name, Address: raw.Address}
+	if raw.Metadata != nil {
+		if idl.Name == "" {
+			idl.Name = raw.Metadata.Name
+		}
+		if idl.Address == "" {
+			idl.Address = raw.Metadata.Address
+		}
+	}
+	if idl.Name == "" {
+		return nil, fmt.Errorf("IDL缺少程序名")
+	}
+
+	for _, ri := range raw.Instructions {
+		inst := Instruction{Name: ri.Name, Docs: ri.Docs, Discriminator: ri.Discriminator}
+		if err := flattenAccounts(&inst.Accounts, "", ri.Accounts); err != nil {
+			return nil, fmt.Errorf("指令%s: %v", ri.Name, err)
+		}
+		args, err := convertFields(ri.Args)
+		if err != nil {
+			return nil, fmt.Errorf("指令%s的参数: %v", ri.Name, err)
+		}
+		inst.Args = args
+		idl.Instructions = append(idl.Instructions, inst)
+	}
+
+	types := make(map[string]TypeDef, len(raw.Types))
+	var order []string
+	for _, rt := range raw.Types {
+		def, err := convertTypeDef(rt.Name, rt.Docs, rt.Type)
+		if err != nil {
+			return nil, err
+		}
+		types[def.Name] = def
+		order = append(order, def.Name)
+	}
+
+	// 新格式的账户和事件只有名字，结构定义从types中取出；旧格式自带定义
+	used := make(map[string]bool)
+	resolve := func(r rawNamed, kind string) (TypeDef, error) {
+		var def TypeDef
+		switch {
+		case r.Type != nil:
+			d, err := convertTypeDef(r.Name, r.Docs, r.Type)
+			if err != nil {
+				return def, err
+			}
+			def = d
+		case r.Fields != nil:
+			fields, err := convertFields(r.Fields)
+			if err != nil {
+				return def, fmt.Errorf("%s %s: %v", kind, r.Name, err)
+			}
+			def = TypeDef{Name: r.Name, Docs: r.Docs, Kind: "struct", Fields: fields}
+		default:
+			d, ok := types[r.Name]
+			if !ok {
+				return def, fmt.Errorf("%s %s 在types中没有定义", kind, r.Name)
+			}
+			def = d
+			used[r.Name] = true
+		}
+		def.Discriminator = r.Discriminator
+		return def, nil
+	}
+	for _, r := range raw.Accounts {
+		def, err := resolve(r, "账户")
+		if err != nil {
+			return nil, err
+		}
+		idl.Accounts = append(idl.Accounts, def)
+	}
+	for _, r := range raw.Events {
+		def, err := resolve(r, "事件")
+		if err != nil {
+			return nil, err
+		}
+		idl.Events = append(idl.Events, def)
+	}
+	for _, name := range order {
+		if !used[name] {
+			idl.Types = append(idl.Types, types[name])
+		}
+	}
+	return idl, nil
+}
+
+type rawIDL struct {
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+	Metadata *struct {
+		Name    string `json:"name"`
+		Address string `json:"address"`
+	} `json:"metadata"`
+	Instructions []rawInstruction `json:"instructions"`
+	Accounts     []rawNamed       `json:"accounts"`
+	Events       []rawNamed       `json:"events"`
+	Types        []rawNamed       `json:"types"`
+}
+
+type rawInstruction struct {
+	Name          string            `json:"name"`
+	Docs          []string          `json:"docs"`
+	Discriminator []byte            `json:"-"`
+	Accounts      []rawAccount      `json:"accounts"`
+	Args          []json.RawMessage `json:"args"`
+}
+
+// rawNamed 账户、事件或自定义类型
+type rawNamed struct {
+	Name          string            `json:"name"`
+	Docs          []string          `json:"docs"`
+	Discriminator []byte            `json:"-"`
+	Type          *rawTypeDef       `json:"type"`
+	Fields        []json.RawMessage `json:"fields"` // 旧格式的事件
+}
+
+type rawAccount struct {
+	Name       string       `json:"name"`
+	Docs       []string     `json:"docs"`
+	Writable   bool         `json:"writable"`
+	IsMut      bool         `json:"isMut"`
+	Signer     bool         `json:"signer"`
+	IsSigner   bool         `json:"isSigner"`
+	Optional   bool         `json:"optional"`
+	IsOptional bool         `json:"isOptional"`
+	Address    string       `json:"address"`
+	Accounts   []rawAccount `json:"accounts"` // 嵌套的账户组
+}
+
+type rawTypeDef struct {
+	Kind     string            `json:"kind"`
+	Fields   []json.RawMessage `json:"fields"`
+	Variants []struct {
+		Name   string            `json:"name"`
+		Fields []json.RawMessage `json:"fields"`
+	} `json:"variants"`
+}
+
+// discriminator在JSON中是数字数组，[]byte默认按base64解析，需要单独处理
+func (r *rawInstruction) UnmarshalJSON(data []byte) error {
+	type plain rawInstruction
+	var v struct {
+		plain
+		Discriminator []int `json:"discriminator"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = rawInstruction(v.plain)
+	var err error
+	r.Discriminator, err = toBytes(v.Discriminator)
+	return err
+}
+
+func (r *rawNamed) UnmarshalJSON(data []byte) error {
+	type plain rawNamed
+	var v struct {
+		plain
+		Discriminator []int `json:"discriminator"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = rawNamed(v.plain)
+	var err error
+	r.Discriminator, err = toBytes(v.Discriminator)
+	return err
+}
+
+func toBytes(list []int) ([]byte, error) {
+	if list == nil {
+		return nil, nil
+	}
+	b := make([]byte, len(list))
+	for i, n := range list {
+		if n < 0 || n > 255 {
+			return nil, fmt.Errorf("discriminator中的值超出字节范围: %d", n)
+		}
+		b[i] = byte(n)
+	}
+	return b, nil
+}
+
+// flattenAccounts 展开嵌套的账户组，组内账户名加上组名前缀
+func flattenAccounts(out *[]AccountItem, prefix string, list []rawAccount) error {
+	for _, a := range list {
+		name := a.Name
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		if len(a.Accounts) > 0 {
+			if err := flattenAccounts(out, name, a.Accounts); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" {
+			return fmt.Errorf("账户缺少名字")
+		}
+		*out = append(*out, AccountItem{
+			Name:     name,
+			Docs:     a.Docs,
+			Writable: a.Writable || a.IsMut,
+			Signer:   a.Signer || a.IsSigner,
+			Optional: a.Optional || a.IsOptional,
+			Address:  a.Address,
+		})
+	}
+	return nil
+}
+
+func convertTypeDef(name string, docs []string, rt *rawTypeDef) (TypeDef, error) {
+	def := TypeDef{Name: name, Docs: docs, Kind: rt.Kind}
+	switch rt.Kind {
+	case "struct":
+		fields, err := convertFields(rt.Fields)
+		if err != nil {
+			return def, fmt.Errorf("类型%s: %v", name, err)
+		}
+		def.Fields = fields
+	case "enum":
+		for _, rv := range rt.Variants {
+			v := Variant{Name: rv.Name}
+			if len(rv.Fields) > 0 {
+				fields, tuple, err := convertVariantFields(rv.Fields)
+				if err != nil {
+					return def, fmt.Errorf("类型%s的变体%s: %v", name, rv.Name, err)
+				}
+				v.Fields, v.Tuple = fields, tuple
+			}
+			def.Variants = append(def.Variants, v)
+		}
+	default:
+		return def, fmt.Errorf("类型%s: 不支持的类型种类%q", name, rt.Kind)
+	}
+	return def, nil
+}
+
+func convertFields(list []json.RawMessage) ([]Field, error) {
+	fields := make([]Field, 0, len(list))
+	for _, raw := range list {
+		var f struct {
+			Name string          `json:"name"`
+			Docs []string        `json:"docs"`
+			Type json.RawMessage `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, fmt.Errorf("无效的字段 %s: %v", raw, err)
+		}
+		t, err := parseType(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("字段%s: %v", f.Name, err)
+		}
+		fields = append(fields, Field{Name: f.Name, Docs: f.Docs, Type: t})
+	}
+	return fields, nil
+}
+
+// convertVariantFields 枚举变体的字段：带名字的结构体字段，或只有类型的元组字段
+func convertVariantFields(list []json.RawMessage) ([]Field, bool, error) {
+	var probe struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(list[0], &probe) == nil && probe.Name != "" {
+		fields, err := convertFields(list)
+		return fields, false, err
+	}
+	fields := make([]Field, 0, len(list))
+	for i, raw := range list {
+		t, err := parseType(raw)
+		if err != nil {
+			return nil, false, fmt.Errorf("第%d个字段: %v", i, err)
+		}
+		fields = append(fields, Field{Name: fmt.Sprintf("elem%d", i), Type: t})
+	}
+	return fields, true, nil
+}
+
+// parseType 解析类型引用，兼容两种IDL格式的写法
+func parseType(raw json.RawMessage) (*Type, error) {
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		switch name {
+		case "publicKey", "pubkey":
+			return &Type{Kind: "pubkey"}, nil
+		case "bool", "u8", "i8", "u16", "i16", "u32", "i32", "u64", "i64", "u128", "i128",
+			"f32", "f64", "string", "bytes":
+			return &Type{Kind: name}, nil
+		}
+		return nil, fmt.Errorf("不支持的类型%q", name)
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil || len(obj) != 1 {
+		return nil, fmt.Errorf("无效的类型 %s", raw)
+	}
+	var kind string
+	for k := range obj {
+		kind = k
+	}
+	v := obj[kind]
+	switch kind {
+	case "vec", "option":
+		elem, err := parseType(v)
+		if err != nil {
+			return nil, err
+		}
+		return &Type{Kind: kind, Elem: elem}, nil
+	case "array":
+		var pair []json.RawMessage
+		if err := json.Unmarshal(v, &pair); err != nil || len(pair) != 2 {
+			return nil, fmt.Errorf("无效的数组类型 %s", raw)
+		}
+		elem, err := parseType(pair[0])
+		if err != nil {
+			return nil, err
+		}
+		var n int
+		if err := json.Unmarshal(pair[1], &n); err != nil || n <= 0 {
+			return nil, fmt.Errorf("数组长度必须是正整数常量: %s", pair[1])
+		}
+		return &Type{Kind: "array", Elem: elem, Len: n}, nil
+	case "defined":
+		var ref string
+		if json.Unmarshal(v, &ref) != nil {
+			var named struct {
+				Name     string            `json:"name"`
+				Generics []json.RawMessage `json:"generics"`
+			}
+			if err := json.Unmarshal(v, &named); err != nil || named.Name == "" {
+				return nil, fmt.Errorf("无效的类型引用 %s", raw)
+			}
+			if len(named.Generics) > 0 {
+				return nil, fmt.Errorf("不支持泛型类型 %s", named.Name)
+			}
+			ref = named.Name
+		}
+		return &Type{Kind: "defined", Defined: ref}, nil
+	}
+	return nil, fmt.Errorf("不支持的类型 %s", raw)
+}
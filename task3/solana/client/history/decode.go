@@ -0,0 +1,350 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// 指令解码：按原始字节解析常用程序的指令，输出一行可读的描述。
+// 代币数量优先用交易元数据中的代币余额得到精度和mint，拿不到时按最小单位显示
+
+// DefaultTokenSwapPrograms 默认识别的SPL Token Swap程序：主网和devnet上的官方部署
+var DefaultTokenSwapPrograms = []solana.PublicKey{
+	solana.TokenSwapProgramID,
+	solana.MustPublicKeyFromBase58("SwapsVeCiPHMUAtzQWZw7RjsKjgCjhwU55QGu4U1Szw"),
+}
+
+// Instruction 解码后的一条指令
+type Instruction struct {
+	Index       string           `json:"index"` // 顶层指令为"1"，内部指令为"1.2"
+	ProgramID   solana.PublicKey `json:"program_id"`
+	Program     string           `json:"program"` // 程序名，未知程序为空
+	Type        string           `json:"type"`    // 指令名，无法解析时为空
+	Description string           `json:"description"`
+}
+
+func (i *Instruction) String() string {
+	program := i.Program
+	if program == "" {
+		program = short(i.ProgramID)
+	}
+	if i.Type == "" {
+		return fmt.Sprintf("#%s %s: %s", i.Index, program, i.Description)
+	}
+	return fmt.Sprintf("#%s %s.%s: %s", i.Index, program, i.Type, i.Description)
+}
+
+// tokenAccount 交易元数据中记录的代币账户信息
+type tokenAccount struct {
+	Mint     solana.PublicKey
+	Decimals uint8
+}
+
+// tokenInfo 代币账户和mint的精度，用于格式化代币数量
+type tokenInfo struct {
+	accounts map[solana.PublicKey]tokenAccount
+	mints    map[solana.PublicKey]uint8
+}
+
+// amount 按账户（或mint）的精度格式化数量，未知精度时显示最小单位
+func (t tokenInfo) amount(amount uint64, account solana.PublicKey) string {
+	if acc, ok := t.accounts[account]; ok {
+		return fmt.Sprintf("%s (mint %s)", utils.FormatTokenAmount(amount, acc.Decimals), short(acc.Mint))
+	}
+	if decimals, ok := t.mints[account]; ok {
+		return fmt.Sprintf("%s (mint %s)", utils.FormatTokenAmount(amount, decimals), short(account))
+	}
+	return fmt.Sprintf("%d (最小单位)", amount)
+}
+
+// Decoder 指令解码器
+type Decoder struct {
+	tokenSwapPrograms map[solana.PublicKey]bool
+}
+
+// NewDecoder 创建解码器，tokenSwapPrograms为额外识别的Token Swap程序（自行部署的池子），默认程序总会识别
+func NewDecoder(tokenSwapPrograms ...solana.PublicKey) *Decoder {
+	d := &Decoder{tokenSwapPrograms: make(map[solana.PublicKey]bool)}
+	for _, p := range append(append([]solana.PublicKey{}, DefaultTokenSwapPrograms...), tokenSwapPrograms...) {
+		d.tokenSwapPrograms[p] = true
+	}
+	return d
+}
+
+// decode 解码一条指令，accounts为指令引用的账户（已按下标展开）
+func (d *Decoder) decode(programID solana.PublicKey, accounts []solana.PublicKey, data []byte, tokens tokenInfo) Instruction {
+	inst := Instruction{ProgramID: programID}
+	r := &reader{data: data, accounts: accounts}
+	switch {
+	case programID.Equals(solana.SystemProgramID):
+		inst.Program = "system"
+		inst.Type, inst.Description = decodeSystem(r)
+	case programID.Equals(solana.TokenProgramID) || programID.Equals(solana.Token2022ProgramID):
+		inst.Program = "spl-token"
+		if programID.Equals(solana.Token2022ProgramID) {
+			inst.Program = "spl-token-2022"
+		}
+		inst.Type, inst.Description = decodeToken(r, tokens)
+	case programID.Equals(solana.SPLAssociatedTokenAccountProgramID):
+		inst.Program = "associated-token"
+		inst.Type, inst.Description = decodeAssociatedToken(r)
+	case programID.Equals(solana.ComputeBudget):
+		inst.Program = "compute-budget"
+		inst.Type, inst.Description = decodeComputeBudget(r)
+	case programID.Equals(solana.MemoProgramID) || programID.Equals(memoV1ProgramID):
+		inst.Program = "memo"
+		inst.Type, inst.Description = "Memo", memoText(data)
+	case programID.Equals(solana.AddressLookupTableProgramID):
+		inst.Program = "address-lookup-table"
+		inst.Type, inst.Description = decodeLookupTable(r)
+	case d.tokenSwapPrograms[programID]:
+		inst.Program = "token-swap"
+		inst.Type, inst.Description = decodeTokenSwap(r, tokens)
+	}
+	// 数据不足说明不是预期的指令格式，按未知指令显示
+	if inst.Type == "" || r.short {
+		inst.Type = ""
+		inst.Description = fmt.Sprintf("%d 个账户, 数据 %s", len(accounts), hexData(data))
+	}
+	return inst
+}
+
+var memoV1ProgramID = solana.MustPublicKeyFromBase58("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+
+func decodeSystem(r *reader) (string, string) {
+	switch r.u32() {
+	case 0:
+		lamports, space, owner := r.u64(), r.u64(), r.key()
+		return "CreateAccount", fmt.Sprintf("%s 创建账户 %s，%s SOL，%d 字节，所有者 %s",
+			r.acc(0), r.acc(1), sol(lamports), space, short(owner))
+	case 1:
+		return "Assign", fmt.Sprintf("%s 的所有者改为 %s", r.acc(0), short(r.key()))
+	case 2:
+		return "Transfer", fmt.Sprintf("%s -> %s %s SOL", r.acc(0), r.acc(1), sol(r.u64()))
+	case 3:
+		return "CreateAccountWithSeed", fmt.Sprintf("%s 创建派生账户 %s", r.acc(0), r.acc(1))
+	case 4:
+		return "AdvanceNonceAccount", fmt.Sprintf("推进nonce账户 %s，授权人 %s", r.acc(0), r.acc(2))
+	case 5:
+		return "WithdrawNonceAccount", fmt.Sprintf("从nonce账户 %s 提取 %s SOL 到 %s", r.acc(0), sol(r.u64()), r.acc(1))
+	case 6:
+		return "InitializeNonceAccount", fmt.Sprintf("初始化nonce账户 %s，授权人 %s", r.acc(0), short(r.key()))
+	case 7:
+		return "AuthorizeNonceAccount", fmt.Sprintf("nonce账户 %s 的授权人改为 %s", r.acc(0), short(r.key()))
+	case 8:
+		return "Allocate", fmt.Sprintf("为 %s 分配 %d 字节", r.acc(0), r.u64())
+	case 11:
+		return "TransferWithSeed", fmt.Sprintf("%s -> %s %s SOL", r.acc(0), r.acc(2), sol(r.u64()))
+	}
+	return "", ""
+}
+
+func decodeToken(r *reader, tokens tokenInfo) (string, string) {
+	switch r.u8() {
+	case 0, 20:
+		decimals := r.u8()
+		return "InitializeMint", fmt.Sprintf("初始化mint %s，精度 %d，铸币权限 %s", r.acc(0), decimals, short(r.key()))
+	case 1, 16, 18:
+		// InitializeAccount的所有者在账户列表中，2/3版本在数据中
+		return "InitializeAccount", fmt.Sprintf("初始化代币账户 %s，mint %s", r.acc(0), r.acc(1))
+	case 3:
+		amount := r.u64()
+		return "Transfer", fmt.Sprintf("%s -> %s %s，授权 %s", r.acc(0), r.acc(1), tokens.amount(amount, r.key0(0)), r.acc(2))
+	case 4:
+		amount := r.u64()
+		return "Approve", fmt.Sprintf("%s 授权 %s 使用 %s", r.acc(0), r.acc(1), tokens.amount(amount, r.key0(0)))
+	case 5:
+		return "Revoke", fmt.Sprintf("撤销 %s 的授权", r.acc(0))
+	case 6:
+		return "SetAuthority", fmt.Sprintf("修改 %s 的权限，原权限 %s", r.acc(0), r.acc(1))
+	case 7:
+		amount := r.u64()
+		return "MintTo", fmt.Sprintf("铸造 %s 到 %s", tokens.amount(amount, r.key0(0)), r.acc(1))
+	case 8:
+		amount := r.u64()
+		return "Burn", fmt.Sprintf("从 %s 销毁 %s", r.acc(0), tokens.amount(amount, r.key0(1)))
+	case 9:
+		return "CloseAccount", fmt.Sprintf("关闭 %s，租金退回 %s", r.acc(0), r.acc(1))
+	case 10:
+		return "FreezeAccount", fmt.Sprintf("冻结 %s", r.acc(0))
+	case 11:
+		return "ThawAccount", fmt.Sprintf("解冻 %s", r.acc(0))
+	case 12:
+		amount, decimals := r.u64(), r.u8()
+		return "TransferChecked", fmt.Sprintf("%s -> %s %s (mint %s)，授权 %s",
+			r.acc(0), r.acc(2), utils.FormatTokenAmount(amount, decimals), r.acc(1), r.acc(3))
+	case 13:
+		amount, decimals := r.u64(), r.u8()
+		return "ApproveChecked", fmt.Sprintf("%s 授权 %s 使用 %s (mint %s)",
+			r.acc(0), r.acc(2), utils.FormatTokenAmount(amount, decimals), r.acc(1))
+	case 14:
+		amount, decimals := r.u64(), r.u8()
+		return "MintToChecked", fmt.Sprintf("铸造 %s (mint %s) 到 %s", utils.FormatTokenAmount(amount, decimals), r.acc(0), r.acc(1))
+	case 15:
+		amount, decimals := r.u64(), r.u8()
+		return "BurnChecked", fmt.Sprintf("从 %s 销毁 %s (mint %s)", r.acc(0), utils.FormatTokenAmount(amount, decimals), r.acc(1))
+	case 17:
+		return "SyncNative", fmt.Sprintf("同步wSOL账户 %s 的余额", r.acc(0))
+	}
+	return "", ""
+}
+
+func decodeAssociatedToken(r *reader) (string, string) {
+	// 旧版客户端发送的Create指令没有数据
+	typ := "Create"
+	if len(r.data) > 0 {
+		switch r.u8() {
+		case 0:
+		case 1:
+			typ = "CreateIdempotent"
+		case 2:
+			return "RecoverNested", fmt.Sprintf("恢复嵌套的关联账户 %s", r.acc(0))
+		default:
+			return "", ""
+		}
+	}
+	return typ, fmt.Sprintf("为 %s 创建 mint %s 的关联账户 %s，付款 %s", r.acc(2), r.acc(3), r.acc(1), r.acc(0))
+}
+
+func decodeComputeBudget(r *reader) (string, string) {
+	switch r.u8() {
+	case 1:
+		return "RequestHeapFrame", fmt.Sprintf("堆大小 %d 字节", r.u32())
+	case 2:
+		return "SetComputeUnitLimit", fmt.Sprintf("计算单元上限 %d", r.u32())
+	case 3:
+		return "SetComputeUnitPrice", fmt.Sprintf("计算单元价格 %d micro-lamports", r.u64())
+	case 4:
+		return "SetLoadedAccountsDataSizeLimit", fmt.Sprintf("加载账户数据上限 %d 字节", r.u32())
+	}
+	return "", ""
+}
+
+func decodeLookupTable(r *reader) (string, string) {
+	switch r.u32() {
+	case 0:
+		return "CreateLookupTable", fmt.Sprintf("创建查找表 %s，权限 %s", r.acc(0), r.acc(1))
+	case 1:
+		return "FreezeLookupTable", fmt.Sprintf("冻结查找表 %s", r.acc(0))
+	case 2:
+		n := r.u64()
+		return "ExtendLookupTable", fmt.Sprintf("查找表 %s 追加 %d 个地址", r.acc(0), n)
+	case 3:
+		return "DeactivateLookupTable", fmt.Sprintf("停用查找表 %s", r.acc(0))
+	case 4:
+		return "CloseLookupTable", fmt.Sprintf("关闭查找表 %s，租金退回 %s", r.acc(0), r.acc(2))
+	}
+	return "", ""
+}
+
+func decodeTokenSwap(r *reader, tokens tokenInfo) (string, string) {
+	switch r.u8() {
+	case 0:
+		return "Initialize", fmt.Sprintf("初始化池子 %s", r.acc(0))
+	case 1:
+		// 账户顺序见tokenswap.SwapAccounts：0池子 3源账户 6目标账户 9源mint 10目标mint
+		// 11/12源和目标的Token程序 13 LP代币的Token程序 14可选的host手续费账户
+		amountIn, minOut := r.u64(), r.u64()
+		desc := fmt.Sprintf("池子 %s 兑换：%s 支付 %s，%s 至少收到 %s",
+			r.acc(0), r.acc(3), tokens.amount(amountIn, r.key0(9)), r.acc(6), tokens.amount(minOut, r.key0(10)))
+		if len(r.accounts) > 14 {
+			desc += fmt.Sprintf("，host手续费账户 %s", r.acc(14))
+		}
+		return "Swap", desc
+	case 2:
+		lp, maxA, maxB := r.u64(), r.u64(), r.u64()
+		return "DepositAllTokenTypes", fmt.Sprintf("池子 %s 存入，获得LP %d，最多支付 A %d / B %d", r.acc(0), lp, maxA, maxB)
+	case 3:
+		lp, minA, minB := r.u64(), r.u64(), r.u64()
+		return "WithdrawAllTokenTypes", fmt.Sprintf("池子 %s 取出，销毁LP %d，至少收到 A %d / B %d", r.acc(0), lp, minA, minB)
+	case 4:
+		amount, minLP := r.u64(), r.u64()
+		return "DepositSingleTokenTypeExactAmountIn", fmt.Sprintf("池子 %s 单币存入 %d，至少获得LP %d", r.acc(0), amount, minLP)
+	case 5:
+		amount, maxLP := r.u64(), r.u64()
+		return "WithdrawSingleTokenTypeExactAmountOut", fmt.Sprintf("池子 %s 单币取出 %d，最多销毁LP %d", r.acc(0), amount, maxLP)
+	}
+	return "", ""
+}
+
+// reader 顺序读取指令数据，数据不足时记录错误，由调用方统一判断
+type reader struct {
+	data     []byte
+	pos      int
+	short    bool
+	accounts []solana.PublicKey
+}
+
+func (r *reader) take(n int) []byte {
+	if r.pos+n > len(r.data) {
+		r.short = true
+		r.pos = len(r.data)
+		return make([]byte, n)
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *reader) u8() uint8   { return r.take(1)[0] }
+func (r *reader) u32() uint32 { return binary.LittleEndian.Uint32(r.take(4)) }
+func (r *reader) u64() uint64 { return binary.LittleEndian.Uint64(r.take(8)) }
+
+func (r *reader) key() solana.PublicKey {
+	return solana.PublicKeyFromBytes(r.take(solana.PublicKeyLength))
+}
+
+// key0 第i个账户，不存在时为零值
+func (r *reader) key0(i int) solana.PublicKey {
+	if i < len(r.accounts) {
+		return r.accounts[i]
+	}
+	return solana.PublicKey{}
+}
+
+// acc 第i个账户的缩写
+func (r *reader) acc(i int) string {
+	if i < len(r.accounts) {
+		return short(r.accounts[i])
+	}
+	return "?"
+}
+
+// short 缩写地址，如 7xKX…sAsU
+func short(key solana.PublicKey) string {
+	s := key.String()
+	if len(s) <= 10 {
+		return s
+	}
+	return s[:4] + "…" + s[len(s)-4:]
+}
+
+func sol(lamports uint64) string {
+	return utils.FormatTokenAmount(lamports, 9)
+}
+
+func memoText(data []byte) string {
+	if utf8.Valid(data) {
+		return fmt.Sprintf("%q", strings.TrimSpace(string(data)))
+	}
+	return hexData(data)
+}
+
+// hexData 未知指令的数据，过长时截断
+func hexData(data []byte) string {
+	const max = 32
+	if len(data) > max {
+		return hex.EncodeToString(data[:max]) + fmt.Sprintf("…(%d 字节)", len(data))
+	}
+	if len(data) == 0 {
+		return "(空)"
+	}
+	return hex.EncodeToString(data)
+}
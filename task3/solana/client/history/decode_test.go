@@ -0,0 +1,187 @@
+package history
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// 按小端拼接指令数据，参数可以是uint8/uint32/uint64
+func instData(fields ...any) []byte {
+	var data []byte
+	for _, f := range fields {
+		switch v := f.(type) {
+		case uint8:
+			data = append(data, v)
+		case uint32:
+			data = binary.LittleEndian.AppendUint32(data, v)
+		case uint64:
+			data = binary.LittleEndian.AppendUint64(data, v)
+		}
+	}
+	return data
+}
+
+func newKeys(n int) []solana.PublicKey {
+	keys := make([]solana.PublicKey, n)
+	for i := range keys {
+		keys[i] = solana.NewWallet().PublicKey()
+	}
+	return keys
+}
+
+func TestDecode(t *testing.T) {
+	keys := newKeys(15)
+	mintA, mintB := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	// keys[0]是mintA的代币账户，swap的源/目标mint在账户9/10
+	tokens := tokenInfo{
+		accounts: map[solana.PublicKey]tokenAccount{keys[0]: {Mint: mintA, Decimals: 6}},
+		mints:    map[solana.PublicKey]uint8{mintA: 6, mintB: 9},
+	}
+	swapAccounts := append([]solana.PublicKey{}, keys...)
+	swapAccounts[9], swapAccounts[10] = mintA, mintB
+
+	tests := []struct {
+		name      string
+		programID solana.PublicKey
+		accounts  []solana.PublicKey
+		data      []byte
+		program   string
+		typ       string
+		contains  []string
+	}{
+		{
+			name:      "system转账",
+			programID: solana.SystemProgramID,
+			accounts:  keys[:2],
+			data:      instData(uint32(2), uint64(1_500_000_000)),
+			program:   "system",
+			typ:       "Transfer",
+			contains:  []string{short(keys[0]) + " -> " + short(keys[1]), "1.5 SOL"},
+		},
+		{
+			name:      "system数据不足",
+			programID: solana.SystemProgramID,
+			accounts:  keys[:2],
+			data:      instData(uint32(2), uint32(1)),
+			program:   "system",
+			contains:  []string{"2 个账户", "0200000001000000"},
+		},
+		{
+			name:      "spl-token转账按账户精度显示",
+			programID: solana.TokenProgramID,
+			accounts:  keys[:3],
+			data:      instData(uint8(3), uint64(2_500_000)),
+			program:   "spl-token",
+			typ:       "Transfer",
+			contains:  []string{"2.5 (mint " + short(mintA) + ")", "授权 " + short(keys[2])},
+		},
+		{
+			name:      "spl-token未知精度显示最小单位",
+			programID: solana.TokenProgramID,
+			accounts:  keys[1:4],
+			data:      instData(uint8(3), uint64(2_500_000)),
+			program:   "spl-token",
+			typ:       "Transfer",
+			contains:  []string{"2500000 (最小单位)"},
+		},
+		{
+			name:      "spl-token-2022 TransferChecked",
+			programID: solana.Token2022ProgramID,
+			accounts:  keys[:4],
+			data:      instData(uint8(12), uint64(1234), uint8(2)),
+			program:   "spl-token-2022",
+			typ:       "TransferChecked",
+			contains:  []string{short(keys[0]) + " -> " + short(keys[2]) + " 12.34 (mint " + short(keys[1]) + ")"},
+		},
+		{
+			name:      "spl-token未知指令",
+			programID: solana.TokenProgramID,
+			accounts:  keys[:1],
+			data:      instData(uint8(200)),
+			program:   "spl-token",
+			contains:  []string{"1 个账户", "c8"},
+		},
+		{
+			name:      "计算单元上限",
+			programID: solana.ComputeBudget,
+			data:      instData(uint8(2), uint32(200_000)),
+			program:   "compute-budget",
+			typ:       "SetComputeUnitLimit",
+			contains:  []string{"计算单元上限 200000"},
+		},
+		{
+			name:      "计算单元价格",
+			programID: solana.ComputeBudget,
+			data:      instData(uint8(3), uint64(5000)),
+			program:   "compute-budget",
+			typ:       "SetComputeUnitPrice",
+			contains:  []string{"5000 micro-lamports"},
+		},
+		{
+			name:      "token-swap兑换",
+			programID: solana.TokenSwapProgramID,
+			accounts:  swapAccounts[:14],
+			data:      instData(uint8(1), uint64(1_000_000), uint64(2_000_000_000)),
+			program:   "token-swap",
+			typ:       "Swap",
+			contains: []string{
+				"池子 " + short(keys[0]),
+				short(keys[3]) + " 支付 1 (mint " + short(mintA) + ")",
+				short(keys[6]) + " 至少收到 2 (mint " + short(mintB) + ")",
+			},
+		},
+		{
+			name:      "token-swap带host手续费账户",
+			programID: solana.TokenSwapProgramID,
+			accounts:  swapAccounts,
+			data:      instData(uint8(1), uint64(1_000_000), uint64(2_000_000_000)),
+			program:   "token-swap",
+			typ:       "Swap",
+			contains:  []string{"host手续费账户 " + short(keys[14])},
+		},
+		{
+			name:      "token-swap数据不足",
+			programID: solana.TokenSwapProgramID,
+			accounts:  swapAccounts,
+			data:      instData(uint8(1), uint64(1_000_000)),
+			program:   "token-swap",
+			contains:  []string{"15 个账户"},
+		},
+		{
+			name:      "未知程序",
+			programID: keys[14],
+			accounts:  keys[:2],
+			program:   "",
+			contains:  []string{"2 个账户, 数据 (空)"},
+		},
+	}
+	d := NewDecoder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := d.decode(tt.programID, tt.accounts, tt.data, tokens)
+			if inst.Program != tt.program || inst.Type != tt.typ {
+				t.Fatalf("程序 %q 指令 %q，期望 %q %q", inst.Program, inst.Type, tt.program, tt.typ)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(inst.Description, s) {
+					t.Errorf("描述 %q 不包含 %q", inst.Description, s)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoderExtraTokenSwapProgram(t *testing.T) {
+	program := solana.NewWallet().PublicKey()
+	data := instData(uint8(0))
+	// 自行部署的Swap程序需要显式传入才能识别
+	if inst := NewDecoder().decode(program, nil, data, tokenInfo{}); inst.Program != "" {
+		t.Fatalf("未注册的程序被识别为 %q", inst.Program)
+	}
+	if inst := NewDecoder(program).decode(program, nil, data, tokenInfo{}); inst.Program != "token-swap" || inst.Type != "Initialize" {
+		t.Fatalf("程序 %q 指令 %q", inst.Program, inst.Type)
+	}
+}
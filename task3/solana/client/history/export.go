@@ -0,0 +1,114 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 导出：text为便于阅读的多行格式，json保留全部字段，csv每笔交易一行，指令和余额变化用"; "连接
+
+// Formats 支持的导出格式
+var Formats = []string{"text", "json", "csv"}
+
+// Write 按format写出交易列表
+func Write(w io.Writer, format string, entries []*Entry) error {
+	switch format {
+	case "text":
+		for _, e := range entries {
+			if err := WriteText(w, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			return fmt.Errorf("写入JSON失败: %w", err)
+		}
+		return nil
+	case "csv":
+		return writeCSV(w, entries)
+	}
+	return fmt.Errorf("未知的导出格式: %s（可选 %s）", format, strings.Join(Formats, "/"))
+}
+
+// WriteText 以多行文本写出一笔交易
+func WriteText(w io.Writer, e *Entry) error {
+	status := "成功"
+	if !e.Success() {
+		status = "失败: " + e.Err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", e.Signature)
+	fmt.Fprintf(&b, "  slot %d  %s  手续费 %s SOL  %s\n", e.Slot, blockTime(e), sol(e.Fee), status)
+	if e.Memo != "" {
+		fmt.Fprintf(&b, "  memo: %s\n", e.Memo)
+	}
+	for _, inst := range e.Instructions {
+		indent := "  "
+		if strings.Contains(inst.Index, ".") {
+			indent = "    "
+		}
+		fmt.Fprintf(&b, "%s%s\n", indent, inst.String())
+	}
+	for _, c := range e.BalanceChanges {
+		fmt.Fprintf(&b, "  余额 %s\n", c)
+	}
+	for _, c := range e.TokenBalanceChanges {
+		fmt.Fprintf(&b, "  代币 %s\n", c)
+	}
+	b.WriteString("\n")
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("写入失败: %w", err)
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, entries []*Entry) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"signature", "slot", "block_time", "status", "error", "fee", "memo", "instructions", "balance_changes", "token_balance_changes"})
+	for _, e := range entries {
+		status := "success"
+		if !e.Success() {
+			status = "failed"
+		}
+		cw.Write([]string{
+			e.Signature.String(),
+			strconv.FormatUint(e.Slot, 10),
+			blockTime(e),
+			status,
+			e.Err,
+			strconv.FormatUint(e.Fee, 10),
+			e.Memo,
+			joinStrings(e.Instructions, func(i Instruction) string { return i.String() }),
+			joinStrings(e.BalanceChanges, BalanceChange.String),
+			joinStrings(e.TokenBalanceChanges, TokenChange.String),
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("写入CSV失败: %w", err)
+	}
+	return nil
+}
+
+func joinStrings[T any](items []T, str func(T) string) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = str(item)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func blockTime(e *Entry) string {
+	if e.BlockTime == nil {
+		return ""
+	}
+	return e.BlockTime.Local().Format(time.DateTime)
+}
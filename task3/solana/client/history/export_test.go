@@ -0,0 +1,119 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func testEntries() []*Entry {
+	keys := newKeys(3)
+	blockTime := time.Unix(1_700_000_000, 0)
+	return []*Entry{
+		{
+			Signature: solana.Signature{1},
+			Slot:      100,
+			BlockTime: &blockTime,
+			Fee:       5000,
+			Memo:      "工资, 10月",
+			Instructions: []Instruction{
+				{Index: "1", ProgramID: solana.SystemProgramID, Program: "system", Type: "Transfer", Description: "a -> b 1 SOL"},
+				{Index: "1.1", ProgramID: keys[2], Description: "0 个账户, 数据 (空)"},
+			},
+			BalanceChanges: []BalanceChange{
+				{Account: keys[0], Pre: 2_000_000_000, Post: 999_995_000, Delta: -1_000_005_000},
+				{Account: keys[1], Pre: 0, Post: 1_000_000_000, Delta: 1_000_000_000},
+			},
+			TokenBalanceChanges: []TokenChange{},
+		},
+		{
+			Signature:           solana.Signature{2},
+			Slot:                99,
+			Err:                 `{"InstructionError":[0,"Custom"]}`,
+			Fee:                 5000,
+			Instructions:        []Instruction{},
+			BalanceChanges:      []BalanceChange{},
+			TokenBalanceChanges: []TokenChange{},
+		},
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	entries := testEntries()
+	var buf bytes.Buffer
+	if err := Write(&buf, "json", entries); err != nil {
+		t.Fatal(err)
+	}
+	var got []*Entry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("解析导出的JSON失败: %v\n%s", err, buf.String())
+	}
+	if len(got) != len(entries) {
+		t.Fatalf("导出 %d 笔，期望 %d 笔", len(got), len(entries))
+	}
+	for i, want := range entries {
+		g := got[i]
+		if g.Signature != want.Signature || g.Slot != want.Slot || g.Err != want.Err || g.Fee != want.Fee || g.Memo != want.Memo {
+			t.Errorf("第%d笔: %+v，期望 %+v", i, g, want)
+		}
+		if (g.BlockTime == nil) != (want.BlockTime == nil) || (g.BlockTime != nil && !g.BlockTime.Equal(*want.BlockTime)) {
+			t.Errorf("第%d笔区块时间 %v，期望 %v", i, g.BlockTime, want.BlockTime)
+		}
+		if len(g.Instructions) != len(want.Instructions) || len(g.BalanceChanges) != len(want.BalanceChanges) {
+			t.Errorf("第%d笔指令 %d 条、余额变化 %d 条", i, len(g.Instructions), len(g.BalanceChanges))
+		}
+	}
+	if got[0].Instructions[0] != entries[0].Instructions[0] || got[0].BalanceChanges[0] != entries[0].BalanceChanges[0] {
+		t.Errorf("指令或余额变化不一致: %+v %+v", got[0].Instructions[0], got[0].BalanceChanges[0])
+	}
+	// 区块时间为空时省略字段
+	if strings.Count(buf.String(), `"block_time"`) != 1 {
+		t.Errorf("block_time字段出现次数不对:\n%s", buf.String())
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	entries := testEntries()
+	var buf bytes.Buffer
+	if err := Write(&buf, "csv", entries); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("解析导出的CSV失败: %v", err)
+	}
+	header := []string{"signature", "slot", "block_time", "status", "error", "fee", "memo", "instructions", "balance_changes", "token_balance_changes"}
+	if len(records) != len(entries)+1 || strings.Join(records[0], ",") != strings.Join(header, ",") {
+		t.Fatalf("CSV内容: %q", records)
+	}
+
+	ok, failed := records[1], records[2]
+	want := []string{
+		entries[0].Signature.String(), "100", entries[0].BlockTime.Local().Format(time.DateTime), "success", "", "5000",
+		"工资, 10月", entries[0].Instructions[0].String() + "; " + entries[0].Instructions[1].String(),
+		entries[0].BalanceChanges[0].String() + "; " + entries[0].BalanceChanges[1].String(), "",
+	}
+	for i := range header {
+		if ok[i] != want[i] {
+			t.Errorf("成功交易的 %s = %q，期望 %q", header[i], ok[i], want[i])
+		}
+	}
+	if failed[2] != "" || failed[3] != "failed" || failed[4] != entries[1].Err {
+		t.Errorf("失败交易: %q", failed)
+	}
+	if !strings.Contains(ok[8], "-1.000005 SOL") {
+		t.Errorf("余额变化 %q", ok[8])
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "xml", testEntries()); err == nil || buf.Len() != 0 {
+		t.Fatalf("err = %v，输出 %q", err, buf.String())
+	}
+}
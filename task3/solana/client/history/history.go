@@ -0,0 +1,290 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"solana-go/client/lookuptable"
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// 交易历史：按getSignaturesForAddress从新到旧分页读取签名，逐个读取交易（包括v0交易），
+// 解码顶层和内部指令，并根据交易元数据计算每个账户的SOL和代币余额变化
+
+const (
+	// MaxPageSize getSignaturesForAddress单页最多返回的签名数
+	MaxPageSize  = 1000
+	defaultLimit = 20
+)
+
+// Options 查询参数
+type Options struct {
+	Limit    int              // 最多读取的交易数，默认20
+	PageSize int              // 每页签名数，默认与Limit相同，最大1000
+	Before   solana.Signature // 从该签名之前开始（不含），零值表示从最新开始
+	Until    solana.Signature // 读到该签名为止（不含）
+	Decoder  *Decoder         // 默认NewDecoder()
+	// OnEntry 每解析完一笔交易回调一次，可用于显示进度
+	OnEntry func(*Entry)
+}
+
+// Entry 一笔交易的解析结果
+type Entry struct {
+	Signature           solana.Signature `json:"signature"`
+	Slot                uint64           `json:"slot"`
+	BlockTime           *time.Time       `json:"block_time,omitempty"`
+	Err                 string           `json:"error,omitempty"` // 失败交易的错误，成功时为空
+	Fee                 uint64           `json:"fee"`
+	Memo                string           `json:"memo,omitempty"`
+	Instructions        []Instruction    `json:"instructions"`
+	BalanceChanges      []BalanceChange  `json:"balance_changes"`
+	TokenBalanceChanges []TokenChange    `json:"token_balance_changes"`
+}
+
+// Success 交易是否执行成功
+func (e *Entry) Success() bool {
+	return e.Err == ""
+}
+
+// BalanceChange 账户的SOL余额变化（lamports），包括手续费
+type BalanceChange struct {
+	Account solana.PublicKey `json:"account"`
+	Pre     uint64           `json:"pre"`
+	Post    uint64           `json:"post"`
+	Delta   int64            `json:"delta"`
+}
+
+func (c BalanceChange) String() string {
+	return fmt.Sprintf("%s %s SOL", short(c.Account), signed(c.Delta, 9))
+}
+
+// TokenChange 代币账户的余额变化（最小单位）
+type TokenChange struct {
+	Account  solana.PublicKey `json:"account"`
+	Owner    solana.PublicKey `json:"owner"`
+	Mint     solana.PublicKey `json:"mint"`
+	Decimals uint8            `json:"decimals"`
+	Pre      uint64           `json:"pre"`
+	Post     uint64           `json:"post"`
+	Delta    int64            `json:"delta"`
+}
+
+func (c TokenChange) String() string {
+	return fmt.Sprintf("%s(所有者 %s) %s (mint %s)", short(c.Account), short(c.Owner), signed(c.Delta, c.Decimals), short(c.Mint))
+}
+
+// Fetch 读取address最近的交易，按从新到旧返回
+func Fetch(ctx context.Context, rpcClient *rpc.Client, address solana.PublicKey, opts Options) ([]*Entry, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultLimit
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = opts.Limit
+	}
+	opts.PageSize = min(opts.PageSize, MaxPageSize)
+	if opts.Decoder == nil {
+		opts.Decoder = NewDecoder()
+	}
+
+	var entries []*Entry
+	before := opts.Before
+	for len(entries) < opts.Limit {
+		limit := min(opts.PageSize, opts.Limit-len(entries))
+		sigs, err := rpcClient.GetSignaturesForAddressWithOpts(ctx, address, &rpc.GetSignaturesForAddressOpts{
+			Limit:      &limit,
+			Before:     before,
+			Until:      opts.Until,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		if err != nil {
+			return entries, fmt.Errorf("查询 %s 的交易签名失败: %w", address, utils.ClassifyError(err))
+		}
+		for _, sig := range sigs {
+			entry, err := FetchTransaction(ctx, rpcClient, sig.Signature, opts.Decoder)
+			if err != nil {
+				return entries, err
+			}
+			if sig.Memo != nil {
+				entry.Memo = *sig.Memo
+			}
+			entries = append(entries, entry)
+			if opts.OnEntry != nil {
+				opts.OnEntry(entry)
+			}
+		}
+		if len(sigs) < limit {
+			break
+		}
+		before = sigs[len(sigs)-1].Signature
+	}
+	return entries, nil
+}
+
+// FetchTransaction 读取并解析一笔交易，decoder为nil时使用NewDecoder()
+func FetchTransaction(ctx context.Context, rpcClient *rpc.Client, signature solana.Signature, decoder *Decoder) (*Entry, error) {
+	if decoder == nil {
+		decoder = NewDecoder()
+	}
+	tx, res, err := lookuptable.GetTransaction(ctx, rpcClient, signature)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(tx, res, decoder)
+}
+
+// Parse 解析GetTransaction的结果，tx的查找表引用须已展开（见lookuptable.GetTransaction）
+func Parse(tx *solana.Transaction, res *rpc.GetTransactionResult, decoder *Decoder) (*Entry, error) {
+	if res.Meta == nil {
+		return nil, fmt.Errorf("交易 %s 缺少元数据", tx.Signatures[0])
+	}
+	meta := res.Meta
+	keys := tx.Message.AccountKeys
+	entry := &Entry{Signature: tx.Signatures[0], Slot: res.Slot, Fee: meta.Fee}
+	if res.BlockTime != nil {
+		t := res.BlockTime.Time()
+		entry.BlockTime = &t
+	}
+	if meta.Err != nil {
+		entry.Err = transactionError(meta.Err, meta.LogMessages)
+	}
+
+	tokens := tokenInfo{accounts: make(map[solana.PublicKey]tokenAccount), mints: make(map[solana.PublicKey]uint8)}
+	for _, b := range append(append([]rpc.TokenBalance{}, meta.PreTokenBalances...), meta.PostTokenBalances...) {
+		if int(b.AccountIndex) < len(keys) && b.UiTokenAmount != nil {
+			tokens.accounts[keys[b.AccountIndex]] = tokenAccount{Mint: b.Mint, Decimals: b.UiTokenAmount.Decimals}
+			tokens.mints[b.Mint] = b.UiTokenAmount.Decimals
+		}
+	}
+
+	inner := make(map[uint16][]solana.CompiledInstruction, len(meta.InnerInstructions))
+	for _, ii := range meta.InnerInstructions {
+		for _, ci := range ii.Instructions {
+			inner[ii.Index] = append(inner[ii.Index], solana.CompiledInstruction{
+				ProgramIDIndex: ci.ProgramIDIndex,
+				Accounts:       ci.Accounts,
+				Data:           ci.Data,
+			})
+		}
+	}
+	for i, ci := range tx.Message.Instructions {
+		index := strconv.Itoa(i + 1)
+		inst, err := decodeCompiled(decoder, keys, ci, tokens)
+		if err != nil {
+			return nil, fmt.Errorf("交易 %s 的指令 #%s: %v", entry.Signature, index, err)
+		}
+		inst.Index = index
+		entry.Instructions = append(entry.Instructions, inst)
+		for j, ci := range inner[uint16(i)] {
+			inst, err := decodeCompiled(decoder, keys, ci, tokens)
+			if err != nil {
+				return nil, fmt.Errorf("交易 %s 的内部指令 #%s.%d: %v", entry.Signature, index, j+1, err)
+			}
+			inst.Index = fmt.Sprintf("%s.%d", index, j+1)
+			entry.Instructions = append(entry.Instructions, inst)
+		}
+	}
+
+	for i := range keys {
+		if i >= len(meta.PreBalances) || i >= len(meta.PostBalances) {
+			break
+		}
+		if pre, post := meta.PreBalances[i], meta.PostBalances[i]; pre != post {
+			entry.BalanceChanges = append(entry.BalanceChanges, BalanceChange{
+				Account: keys[i], Pre: pre, Post: post, Delta: int64(post) - int64(pre),
+			})
+		}
+	}
+	entry.TokenBalanceChanges = tokenChanges(keys, meta.PreTokenBalances, meta.PostTokenBalances)
+	return entry, nil
+}
+
+// decodeCompiled 按交易的账户列表展开下标后解码
+func decodeCompiled(decoder *Decoder, keys solana.PublicKeySlice, ci solana.CompiledInstruction, tokens tokenInfo) (Instruction, error) {
+	if int(ci.ProgramIDIndex) >= len(keys) {
+		return Instruction{}, fmt.Errorf("程序下标 %d 超出账户列表长度 %d", ci.ProgramIDIndex, len(keys))
+	}
+	accounts := make([]solana.PublicKey, len(ci.Accounts))
+	for i, idx := range ci.Accounts {
+		if int(idx) >= len(keys) {
+			return Instruction{}, fmt.Errorf("账户下标 %d 超出账户列表长度 %d", idx, len(keys))
+		}
+		accounts[i] = keys[idx]
+	}
+	return decoder.decode(keys[ci.ProgramIDIndex], accounts, ci.Data, tokens), nil
+}
+
+// tokenChanges 对比交易前后的代币余额，交易中新建或关闭的账户只出现在一侧，另一侧按0计算
+func tokenChanges(keys solana.PublicKeySlice, pre, post []rpc.TokenBalance) []TokenChange {
+	changes := make(map[uint16]*TokenChange)
+	get := func(b rpc.TokenBalance) *TokenChange {
+		c, ok := changes[b.AccountIndex]
+		if !ok {
+			c = &TokenChange{Mint: b.Mint}
+			if int(b.AccountIndex) < len(keys) {
+				c.Account = keys[b.AccountIndex]
+			}
+			if b.Owner != nil {
+				c.Owner = *b.Owner
+			}
+			if b.UiTokenAmount != nil {
+				c.Decimals = b.UiTokenAmount.Decimals
+			}
+			changes[b.AccountIndex] = c
+		}
+		return c
+	}
+	for _, b := range pre {
+		get(b).Pre = rawAmount(b)
+	}
+	for _, b := range post {
+		get(b).Post = rawAmount(b)
+	}
+	indexes := make([]uint16, 0, len(changes))
+	for idx, c := range changes {
+		if c.Pre != c.Post {
+			c.Delta = int64(c.Post) - int64(c.Pre)
+			indexes = append(indexes, idx)
+		}
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	result := make([]TokenChange, 0, len(indexes))
+	for _, idx := range indexes {
+		result = append(result, *changes[idx])
+	}
+	return result
+}
+
+// transactionError 失败交易的错误描述：能归类的用归类后的错误，否则用节点返回的原始错误
+func transactionError(txErr interface{}, logs []string) string {
+	if kind := utils.TransactionErrorKind(txErr, logs); kind != nil {
+		return kind.Error()
+	}
+	data, err := json.Marshal(txErr)
+	if err != nil {
+		return fmt.Sprint(txErr)
+	}
+	return string(data)
+}
+
+func rawAmount(b rpc.TokenBalance) uint64 {
+	if b.UiTokenAmount == nil {
+		return 0
+	}
+	v, _ := strconv.ParseUint(b.UiTokenAmount.Amount, 10, 64)
+	return v
+}
+
+// signed 带符号地按精度格式化变化量
+func signed(delta int64, decimals uint8) string {
+	if delta < 0 {
+		return "-" + utils.FormatTokenAmount(uint64(-delta), decimals)
+	}
+	return "+" + utils.FormatTokenAmount(uint64(delta), decimals)
+}
@@ -0,0 +1,91 @@
+package lookuptable
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// 地址查找表程序的指令，数据为bincode编码：u32小端序的指令编号加参数
+
+const (
+	InstructionCreate     uint32 = 0
+	InstructionFreeze     uint32 = 1
+	InstructionExtend     uint32 = 2
+	InstructionDeactivate uint32 = 3
+	InstructionClose      uint32 = 4
+)
+
+// MaxAddresses 一张查找表最多保存的地址数
+const MaxAddresses = 256
+
+// MaxExtendAddresses 单条Extend指令建议携带的地址数，再多会超过交易大小限制
+const MaxExtendAddresses = 30
+
+// DeriveAddress 查找表地址由authority和创建时引用的slot推导
+func DeriveAddress(authority solana.PublicKey, recentSlot uint64) (solana.PublicKey, uint8, error) {
+	slot := binary.LittleEndian.AppendUint64(nil, recentSlot)
+	table, bump, err := solana.FindProgramAddress([][]byte{authority[:], slot}, solana.AddressLookupTableProgramID)
+	if err != nil {
+		return solana.PublicKey{}, 0, fmt.Errorf("推导查找表地址失败: %v", err)
+	}
+	return table, bump, nil
+}
+
+// NewCreateInstruction 创建查找表，recentSlot必须是最近的slot（仍在SlotHashes中）
+func NewCreateInstruction(authority, payer solana.PublicKey, recentSlot uint64) (solana.Instruction, solana.PublicKey, error) {
+	table, bump, err := DeriveAddress(authority, recentSlot)
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+	data := binary.LittleEndian.AppendUint32(nil, InstructionCreate)
+	data = binary.LittleEndian.AppendUint64(data, recentSlot)
+	data = append(data, bump)
+	return solana.NewInstruction(solana.AddressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(payer).WRITE().SIGNER(),
+		solana.Meta(solana.SystemProgramID),
+	}, data), table, nil
+}
+
+// NewExtendInstruction 向查找表追加地址，新增部分的租金由payer支付
+func NewExtendInstruction(table, authority, payer solana.PublicKey, addresses []solana.PublicKey) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32(nil, InstructionExtend)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(addresses)))
+	for _, addr := range addresses {
+		data = append(data, addr[:]...)
+	}
+	return solana.NewInstruction(solana.AddressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(payer).WRITE().SIGNER(),
+		solana.Meta(solana.SystemProgramID),
+	}, data)
+}
+
+// NewFreezeInstruction 冻结查找表，之后不能再修改
+func NewFreezeInstruction(table, authority solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(solana.AddressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+	}, binary.LittleEndian.AppendUint32(nil, InstructionFreeze))
+}
+
+// NewDeactivateInstruction 停用查找表，冷却期结束后才能关闭
+func NewDeactivateInstruction(table, authority solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(solana.AddressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+	}, binary.LittleEndian.AppendUint32(nil, InstructionDeactivate))
+}
+
+// NewCloseInstruction 关闭已停用的查找表，租金返还给recipient
+func NewCloseInstruction(table, authority, recipient solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(solana.AddressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(recipient).WRITE(),
+	}, binary.LittleEndian.AppendUint32(nil, InstructionClose))
+}
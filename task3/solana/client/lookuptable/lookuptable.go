@@ -0,0 +1,217 @@
+package lookuptable
+
+import (
+	"context"
+	"fmt"
+
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+	addresslookuptable "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// 地址查找表（ALT）与v0交易：legacy交易每个账户占32字节，单笔交易最多容纳约35个账户；
+// 把常用账户放进查找表后，v0交易只需引用表地址和1字节下标，兑换等涉及大量账户的交易才能放得下。
+// 新创建或追加的地址要等下一个slot才能被交易引用。
+
+// Tables 查找表地址到表内地址列表，即solana.TransactionAddressTables需要的格式
+type Tables map[solana.PublicKey]solana.PublicKeySlice
+
+// Fetch 读取查找表账户。已停用的表仍可读取，但不能再被新交易引用
+func Fetch(ctx context.Context, rpcClient *rpc.Client, tables ...solana.PublicKey) (Tables, error) {
+	if len(tables) == 0 {
+		return Tables{}, nil
+	}
+	res, err := rpcClient.GetMultipleAccountsWithOpts(ctx, tables, &rpc.GetMultipleAccountsOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取查找表失败: %w", utils.ClassifyError(err))
+	}
+	result := make(Tables, len(tables))
+	for i, acc := range res.Value {
+		if acc == nil {
+			return nil, fmt.Errorf("查找表 %s 不存在", tables[i])
+		}
+		if !acc.Owner.Equals(solana.AddressLookupTableProgramID) {
+			return nil, fmt.Errorf("%s 不是查找表账户，owner为 %s", tables[i], acc.Owner)
+		}
+		state, err := addresslookuptable.DecodeAddressLookupTableState(acc.Data.GetBinary())
+		if err != nil {
+			return nil, fmt.Errorf("解析查找表 %s 失败: %v", tables[i], err)
+		}
+		if !state.IsActive() {
+			return nil, fmt.Errorf("查找表 %s 已停用", tables[i])
+		}
+		result[tables[i]] = state.Addresses
+	}
+	return result, nil
+}
+
+// Create 创建由payer管理的查找表并写入addresses，返回表地址。
+// 地址超过MaxExtendAddresses时分多笔Extend交易写入，每笔都等待确认
+func Create(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	payer solana.PrivateKey,
+	addresses []solana.PublicKey,
+) (solana.PublicKey, error) {
+	if len(addresses) > MaxAddresses {
+		return solana.PublicKey{}, fmt.Errorf("查找表最多保存%d个地址: %d", MaxAddresses, len(addresses))
+	}
+	// 创建指令引用的slot必须已经产生区块，用finalized的slot更稳妥
+	slot, err := rpcClient.GetSlot(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("获取slot失败: %w", utils.ClassifyError(err))
+	}
+	owner := payer.PublicKey()
+	create, table, err := NewCreateInstruction(owner, owner, slot)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+
+	// 第一批地址和创建指令放在同一笔交易里
+	first := min(len(addresses), MaxExtendAddresses)
+	instructions := []solana.Instruction{create}
+	if first > 0 {
+		instructions = append(instructions, NewExtendInstruction(table, owner, owner, addresses[:first]))
+	}
+	if err := send(ctx, rpcClient, wsClient, payer, instructions); err != nil {
+		return solana.PublicKey{}, fmt.Errorf("创建查找表失败: %w", err)
+	}
+	if err := Extend(ctx, rpcClient, wsClient, payer, table, addresses[first:]); err != nil {
+		return table, err
+	}
+	return table, nil
+}
+
+// Extend 向已有的查找表追加地址
+func Extend(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	payer solana.PrivateKey,
+	table solana.PublicKey,
+	addresses []solana.PublicKey,
+) error {
+	owner := payer.PublicKey()
+	for start := 0; start < len(addresses); start += MaxExtendAddresses {
+		end := min(start+MaxExtendAddresses, len(addresses))
+		extend := NewExtendInstruction(table, owner, owner, addresses[start:end])
+		if err := send(ctx, rpcClient, wsClient, payer, []solana.Instruction{extend}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewV0Transaction 构造引用查找表的v0交易，tables中出现的非签名账户改为按下标引用。
+// tables为空时构造的仍是legacy交易
+func NewV0Transaction(instructions []solana.Instruction, recentBlockhash solana.Hash, payer solana.PublicKey, tables Tables) (*solana.Transaction, error) {
+	opts := []solana.TransactionOption{solana.TransactionPayer(payer)}
+	if len(tables) > 0 {
+		opts = append(opts, solana.TransactionAddressTables(tables))
+	}
+	tx, err := solana.NewTransaction(instructions, recentBlockhash, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("构造交易失败: %v", err)
+	}
+	return tx, nil
+}
+
+// GetTransaction 读取交易（包括v0交易），并用交易元数据中的loadedAddresses展开查找表引用，
+// 返回交易的AccountKeys包含查找表中的账户，可直接按指令中的下标取账户
+func GetTransaction(ctx context.Context, rpcClient *rpc.Client, signature solana.Signature) (*solana.Transaction, *rpc.GetTransactionResult, error) {
+	version := uint64(0)
+	res, err := rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &version,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询交易 %s 失败: %w", signature, utils.ClassifyError(err))
+	}
+	if res == nil || res.Transaction == nil {
+		return nil, nil, fmt.Errorf("交易 %s 不存在", signature)
+	}
+	tx, err := res.Transaction.GetTransaction()
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析交易 %s 失败: %v", signature, err)
+	}
+	if tx.Message.IsVersioned() && tx.Message.NumLookups() > 0 {
+		if res.Meta == nil {
+			return nil, nil, fmt.Errorf("交易 %s 缺少元数据，无法展开查找表", signature)
+		}
+		if err := ResolveLoadedAddresses(&tx.Message, res.Meta.LoadedAddresses); err != nil {
+			return nil, nil, err
+		}
+	}
+	return tx, res, nil
+}
+
+// ResolveLoadedAddresses 用交易元数据中的loadedAddresses展开v0消息的查找表引用。
+// 节点按查找表顺序先返回全部可写地址再返回只读地址，据此还原出各表被引用下标处的地址，
+// 设置后消息的AccountMetaList、IsWritable等方法才能使用
+func ResolveLoadedAddresses(message *solana.Message, loaded rpc.LoadedAddresses) error {
+	lookups := message.GetAddressTableLookups()
+	if lookups.NumWritableLookups() != len(loaded.Writable) || lookups.NumLookups()-lookups.NumWritableLookups() != len(loaded.ReadOnly) {
+		return fmt.Errorf("loadedAddresses与查找表引用数量不一致: 可写 %d/%d，只读 %d/%d",
+			len(loaded.Writable), lookups.NumWritableLookups(), len(loaded.ReadOnly), lookups.NumLookups()-lookups.NumWritableLookups())
+	}
+	tables := make(Tables, len(lookups))
+	put := func(table solana.PublicKey, idx uint8, addr solana.PublicKey) {
+		if int(idx) >= len(tables[table]) {
+			tables[table] = append(tables[table], make(solana.PublicKeySlice, int(idx)+1-len(tables[table]))...)
+		}
+		tables[table][idx] = addr
+	}
+	w, r := 0, 0
+	for _, lookup := range lookups {
+		for _, idx := range lookup.WritableIndexes {
+			put(lookup.AccountKey, idx, loaded.Writable[w])
+			w++
+		}
+		for _, idx := range lookup.ReadonlyIndexes {
+			put(lookup.AccountKey, idx, loaded.ReadOnly[r])
+			r++
+		}
+	}
+	if err := message.SetAddressTables(tables); err != nil {
+		return fmt.Errorf("设置查找表失败: %v", err)
+	}
+	if err := message.ResolveLookups(); err != nil {
+		return fmt.Errorf("展开查找表失败: %v", err)
+	}
+	return nil
+}
+
+// send 由payer签名发送查找表程序的指令并等待确认
+func send(ctx context.Context, rpcClient *rpc.Client, wsClient *ws.Client, payer solana.PrivateKey, instructions []solana.Instruction) error {
+	latest, err := rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		return fmt.Errorf("获取区块哈希失败: %w", utils.ClassifyError(err))
+	}
+	tx, err := solana.NewTransaction(instructions, latest.Value.Blockhash, solana.TransactionPayer(payer.PublicKey()))
+	if err != nil {
+		return fmt.Errorf("构造交易失败: %v", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if payer.PublicKey().Equals(key) {
+			return &payer
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("签名交易失败: %v", err)
+	}
+	if _, err := utils.SendTransaction(ctx, rpcClient, wsClient, tx, latest.Value.LastValidBlockHeight, utils.SendOptions{
+		Signers:    []solana.PrivateKey{payer},
+		MaxResigns: 2,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package lookuptable
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// testInstructions 两条指令共引用表中20个地址（前10个可写），另有一个不在表中的账户。
+// 消息中每个账户只有一组读写/签名标记，同一账户在两条指令中的标记保持一致，便于比较
+func testInstructions(payer solana.PublicKey, table solana.PublicKeySlice, program, extra solana.PublicKey) []solana.Instruction {
+	first := solana.AccountMetaSlice{solana.Meta(payer).WRITE().SIGNER(), solana.Meta(extra).WRITE()}
+	for i, addr := range table {
+		meta := solana.Meta(addr)
+		if i < 10 {
+			meta.WRITE()
+		}
+		first = append(first, meta)
+	}
+	second := solana.AccountMetaSlice{solana.Meta(table[3]).WRITE(), solana.Meta(table[15]), solana.Meta(payer).WRITE().SIGNER()}
+	return []solana.Instruction{
+		solana.NewInstruction(program, first, []byte{1, 2, 3}),
+		solana.NewInstruction(program, second, []byte{4}),
+	}
+}
+
+// loadedAddresses 按节点的方式给出loadedAddresses：各表的可写地址在前，只读地址在后
+func loadedAddresses(message *solana.Message, tables Tables) rpc.LoadedAddresses {
+	var loaded rpc.LoadedAddresses
+	for _, lookup := range message.GetAddressTableLookups() {
+		for _, idx := range lookup.WritableIndexes {
+			loaded.Writable = append(loaded.Writable, tables[lookup.AccountKey][idx])
+		}
+	}
+	for _, lookup := range message.GetAddressTableLookups() {
+		for _, idx := range lookup.ReadonlyIndexes {
+			loaded.ReadOnly = append(loaded.ReadOnly, tables[lookup.AccountKey][idx])
+		}
+	}
+	return loaded
+}
+
+func TestV0MessageRoundTrip(t *testing.T) {
+	payer := solana.NewWallet().PrivateKey
+	program, extra, tableAddress := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	addresses := make(solana.PublicKeySlice, 20)
+	for i := range addresses {
+		addresses[i] = solana.NewWallet().PublicKey()
+	}
+	tables := Tables{tableAddress: addresses}
+	instructions := testInstructions(payer.PublicKey(), addresses, program, extra)
+	blockhash := solana.HashFromBytes([]byte("lookuptable-test-blockhash-00000"))
+
+	tx, err := NewV0Transaction(instructions, blockhash, payer.PublicKey(), tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tx.Message.IsVersioned() || tx.Message.NumLookups() != len(addresses) || tx.Message.NumWritableLookups() != 10 {
+		t.Fatalf("v0消息: versioned=%v 引用 %d 个（可写 %d 个）", tx.Message.IsVersioned(), tx.Message.NumLookups(), tx.Message.NumWritableLookups())
+	}
+	// 静态账户只剩付款账户、表外账户和程序
+	if n := len(tx.Message.AccountKeys); n != 3 {
+		t.Fatalf("静态账户 %d 个: %v", n, tx.Message.AccountKeys)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(payer.PublicKey()) {
+			return &payer
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := NewV0Transaction(instructions, blockhash, payer.PublicKey(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if legacy.Message.IsVersioned() {
+		t.Fatal("没有查找表时应构造legacy交易")
+	}
+	legacy.Signatures = tx.Signatures
+	legacyRaw, err := legacy.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 每个表内地址从32字节变为1字节下标，扣掉表地址等开销后平均每个至少省28字节
+	if saved := len(legacyRaw) - len(raw); saved < len(addresses)*28 {
+		t.Fatalf("v0交易 %d 字节，legacy交易 %d 字节，查找表没有节省空间", len(raw), len(legacyRaw))
+	}
+
+	decoded, err := solana.TransactionFromBytes(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.VerifySignatures(); err != nil {
+		t.Fatalf("反序列化后签名校验失败: %v", err)
+	}
+	if !decoded.Message.IsVersioned() || decoded.Message.RecentBlockhash != blockhash {
+		t.Fatalf("反序列化后的消息不一致: versioned=%v blockhash=%s", decoded.Message.IsVersioned(), decoded.Message.RecentBlockhash)
+	}
+
+	if err := ResolveLoadedAddresses(&decoded.Message, loadedAddresses(&decoded.Message, tables)); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := decoded.Message.GetAllKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3+len(addresses) {
+		t.Fatalf("展开后 %d 个账户，期望 %d 个", len(keys), 3+len(addresses))
+	}
+	if len(decoded.Message.Instructions) != len(instructions) {
+		t.Fatalf("指令 %d 条，期望 %d 条", len(decoded.Message.Instructions), len(instructions))
+	}
+	for i, ci := range decoded.Message.Instructions {
+		programID, err := decoded.Message.ResolveProgramIDIndex(ci.ProgramIDIndex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !programID.Equals(program) {
+			t.Fatalf("指令%d 程序 = %s", i, programID)
+		}
+		metas, err := ci.ResolveInstructionAccounts(&decoded.Message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := instructions[i].Accounts()
+		if len(metas) != len(want) {
+			t.Fatalf("指令%d 账户 %d 个，期望 %d 个", i, len(metas), len(want))
+		}
+		for j, meta := range metas {
+			if !meta.PublicKey.Equals(want[j].PublicKey) || meta.IsWritable != want[j].IsWritable || meta.IsSigner != want[j].IsSigner {
+				t.Fatalf("指令%d 第%d个账户 = %s w=%v s=%v，期望 %s w=%v s=%v", i, j,
+					meta.PublicKey, meta.IsWritable, meta.IsSigner, want[j].PublicKey, want[j].IsWritable, want[j].IsSigner)
+			}
+		}
+	}
+}
+
+func TestResolveLoadedAddressesMismatch(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	addresses := make(solana.PublicKeySlice, 20)
+	for i := range addresses {
+		addresses[i] = solana.NewWallet().PublicKey()
+	}
+	tables := Tables{solana.NewWallet().PublicKey(): addresses}
+	tx, err := NewV0Transaction(testInstructions(payer, addresses, solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()), solana.Hash{}, payer, tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := loadedAddresses(&tx.Message, tables)
+	loaded.ReadOnly = loaded.ReadOnly[1:]
+	if err := ResolveLoadedAddresses(&tx.Message, loaded); err == nil {
+		t.Fatal("loadedAddresses数量不一致时应报错")
+	}
+}
@@ -0,0 +1,200 @@
+package nonce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"solana-go/utils"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// Durable nonce账户：普通交易的区块哈希只有60~90秒有效期，离线签名来不及广播。
+// nonce账户保存一个nonce值，交易用它代替区块哈希，并以AdvanceNonceAccount作为第一条指令；
+// 交易上链时nonce被推进成新值，同一个nonce只能用一次，在此之前签好的交易一直有效。
+
+// AccountSize nonce账户的数据长度
+const AccountSize = 80
+
+const stateInitialized = 1
+
+var (
+	// ErrNotNonceAccount 账户不存在、不属于System程序或未初始化
+	ErrNotNonceAccount = errors.New("不是已初始化的nonce账户")
+	// ErrNonceAdvanced 交易使用的nonce已被推进：交易已经上链，或者同一个nonce被别的交易用掉了
+	ErrNonceAdvanced = errors.New("nonce已被推进")
+)
+
+// Account nonce账户的状态
+type Account struct {
+	Address              solana.PublicKey
+	Authority            solana.PublicKey // 有权推进、提取和转移授权的账户，离线签名时需要它的私钥
+	Nonce                solana.Hash      // 当前nonce值，交易的RecentBlockhash填它
+	LamportsPerSignature uint64
+	Lamports             uint64
+}
+
+func (a *Account) String() string {
+	return fmt.Sprintf("nonce账户 %s: nonce %s，授权账户 %s，余额 %s SOL",
+		a.Address, a.Nonce, a.Authority, utils.FormatTokenAmount(a.Lamports, 9))
+}
+
+// DecodeAccount 解析nonce账户数据
+func DecodeAccount(address solana.PublicKey, data []byte) (*Account, error) {
+	if len(data) != AccountSize {
+		return nil, fmt.Errorf("%w: %s 数据长度为 %d", ErrNotNonceAccount, address, len(data))
+	}
+	var state system.NonceAccount
+	if err := bin.NewBinDecoder(data).Decode(&state); err != nil {
+		return nil, fmt.Errorf("解析nonce账户 %s 失败: %v", address, err)
+	}
+	if state.State != stateInitialized {
+		return nil, fmt.Errorf("%w: %s 未初始化", ErrNotNonceAccount, address)
+	}
+	return &Account{
+		Address:              address,
+		Authority:            state.AuthorizedPubkey,
+		Nonce:                solana.Hash(state.Nonce),
+		LamportsPerSignature: state.FeeCalculator.LamportsPerSignature,
+	}, nil
+}
+
+// Fetch 读取nonce账户的当前状态
+func Fetch(ctx context.Context, rpcClient *rpc.Client, address solana.PublicKey) (*Account, error) {
+	res, err := rpcClient.GetMultipleAccountsWithOpts(ctx, []solana.PublicKey{address}, &rpc.GetMultipleAccountsOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取nonce账户失败: %w", utils.ClassifyError(err))
+	}
+	if len(res.Value) == 0 || res.Value[0] == nil {
+		return nil, fmt.Errorf("%w: %s 不存在", ErrNotNonceAccount, address)
+	}
+	acc := res.Value[0]
+	if !acc.Owner.Equals(solana.SystemProgramID) {
+		return nil, fmt.Errorf("%w: %s 的owner为 %s", ErrNotNonceAccount, address, acc.Owner)
+	}
+	account, err := DecodeAccount(address, acc.Data.GetBinary())
+	if err != nil {
+		return nil, err
+	}
+	account.Lamports = acc.Lamports
+	return account, nil
+}
+
+// Create 由payer出资创建nonce账户并初始化，授权账户为authority。
+// nonce账户的密钥只在创建时签名用一次，之后由authority管理，返回账户的当前状态
+func Create(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	payer solana.PrivateKey,
+	authority solana.PublicKey,
+) (*Account, error) {
+	rent, err := rpcClient.GetMinimumBalanceForRentExemption(ctx, AccountSize, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("查询免租金余额失败: %w", utils.ClassifyError(err))
+	}
+	key, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("生成nonce账户密钥失败: %v", err)
+	}
+	address := key.PublicKey()
+	instructions := []solana.Instruction{
+		system.NewCreateAccountInstruction(rent, AccountSize, solana.SystemProgramID, payer.PublicKey(), address).Build(),
+		system.NewInitializeNonceAccountInstruction(authority, address, solana.SysVarRecentBlockHashesPubkey, solana.SysVarRentPubkey).Build(),
+	}
+	if err := send(ctx, rpcClient, wsClient, instructions, payer, key); err != nil {
+		return nil, fmt.Errorf("创建nonce账户失败: %w", err)
+	}
+	return Fetch(ctx, rpcClient, address)
+}
+
+// Advance 推进nonce，之前用旧nonce签好但还没广播的交易全部作废
+func Advance(ctx context.Context, rpcClient *rpc.Client, wsClient *ws.Client, payer, authority solana.PrivateKey, address solana.PublicKey) (*Account, error) {
+	inst := AdvanceInstruction(address, authority.PublicKey())
+	if err := send(ctx, rpcClient, wsClient, []solana.Instruction{inst}, payer, authority); err != nil {
+		return nil, fmt.Errorf("推进nonce失败: %w", err)
+	}
+	return Fetch(ctx, rpcClient, address)
+}
+
+// Withdraw 从nonce账户提取lamports到to。剩余余额必须不低于免租金余额，全部提取时账户关闭
+func Withdraw(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	payer, authority solana.PrivateKey,
+	address, to solana.PublicKey,
+	lamports uint64,
+) error {
+	inst := system.NewWithdrawNonceAccountInstruction(lamports, address, to,
+		solana.SysVarRecentBlockHashesPubkey, solana.SysVarRentPubkey, authority.PublicKey()).Build()
+	if err := send(ctx, rpcClient, wsClient, []solana.Instruction{inst}, payer, authority); err != nil {
+		return fmt.Errorf("从nonce账户提取失败: %w", err)
+	}
+	return nil
+}
+
+// Authorize 把nonce账户的授权转移给newAuthority，例如交给离线机器上的密钥
+func Authorize(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	payer, authority solana.PrivateKey,
+	address, newAuthority solana.PublicKey,
+) (*Account, error) {
+	inst := system.NewAuthorizeNonceAccountInstruction(newAuthority, address, authority.PublicKey()).Build()
+	if err := send(ctx, rpcClient, wsClient, []solana.Instruction{inst}, payer, authority); err != nil {
+		return nil, fmt.Errorf("转移nonce授权失败: %w", err)
+	}
+	return Fetch(ctx, rpcClient, address)
+}
+
+// AdvanceInstruction 推进nonce的指令，使用nonce的交易必须以它作为第一条指令
+func AdvanceInstruction(address, authority solana.PublicKey) solana.Instruction {
+	return system.NewAdvanceNonceAccountInstruction(address, solana.SysVarRecentBlockHashesPubkey, authority).Build()
+}
+
+// send 用最新区块哈希签名发送管理nonce账户的交易并等待确认
+func send(ctx context.Context, rpcClient *rpc.Client, wsClient *ws.Client, instructions []solana.Instruction, payer solana.PrivateKey, signers ...solana.PrivateKey) error {
+	latest, err := rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		return fmt.Errorf("获取区块哈希失败: %w", utils.ClassifyError(err))
+	}
+	tx, err := solana.NewTransaction(instructions, latest.Value.Blockhash, solana.TransactionPayer(payer.PublicKey()))
+	if err != nil {
+		return fmt.Errorf("构造交易失败: %v", err)
+	}
+	signers = append([]solana.PrivateKey{payer}, signers...)
+	if err := sign(tx, signers); err != nil {
+		return err
+	}
+	_, err = utils.SendTransaction(ctx, rpcClient, wsClient, tx, latest.Value.LastValidBlockHeight, utils.SendOptions{
+		Signers:    signers,
+		MaxResigns: 2,
+	})
+	return err
+}
+
+// sign 用signers中与交易签名者匹配的私钥签名，缺少任何一个签名者时报错
+func sign(tx *solana.Transaction, signers []solana.PrivateKey) error {
+	_, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		for i := range signers {
+			if signers[i].PublicKey().Equals(key) {
+				return &signers[i]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("签名交易失败: %v", err)
+	}
+	return nil
+}
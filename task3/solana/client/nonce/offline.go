@@ -0,0 +1,134 @@
+package nonce
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// 离线签名、稍后广播：在线机器读取nonce账户（Fetch）并把nonce值带到离线机器，
+// 离线机器用NewTransaction构造交易、签名后用utils.EncodeTransaction转成base64；任意时间把base64带回在线机器，
+// 用utils.DecodeTransaction解析后调用Broadcast发送。离线机器上不需要访问网络
+
+const (
+	// broadcastWindow 每轮广播持续的块数，与区块哈希的有效期相同；nonce交易本身不会过期
+	broadcastWindow = 150
+	defaultRounds   = 3
+	confirmTimeout  = 60 * time.Second
+)
+
+// NewTransaction 构造使用nonce的交易：第一条指令为AdvanceNonceAccount，区块哈希为当前nonce值。
+// account只用到Address、Authority和Nonce，离线时可以直接填写
+func NewTransaction(instructions []solana.Instruction, account *Account, payer solana.PublicKey) (*solana.Transaction, error) {
+	if account.Nonce.IsZero() {
+		return nil, fmt.Errorf("nonce值为空")
+	}
+	instructions = append([]solana.Instruction{AdvanceInstruction(account.Address, account.Authority)}, instructions...)
+	tx, err := solana.NewTransaction(instructions, account.Nonce, solana.TransactionPayer(payer))
+	if err != nil {
+		return nil, fmt.Errorf("构造交易失败: %v", err)
+	}
+	return tx, nil
+}
+
+// Sign 用signers签名，交易需要的每个签名者都必须提供私钥
+func Sign(tx *solana.Transaction, signers ...solana.PrivateKey) error {
+	return sign(tx, signers)
+}
+
+// Inspect 检查tx是否为nonce交易，返回nonce账户和授权账户地址
+func Inspect(tx *solana.Transaction) (address, authority solana.PublicKey, err error) {
+	msg := tx.Message
+	if len(msg.Instructions) == 0 {
+		return address, authority, fmt.Errorf("交易没有指令")
+	}
+	inst := msg.Instructions[0]
+	program, err := msg.Program(inst.ProgramIDIndex)
+	if err != nil {
+		return address, authority, fmt.Errorf("解析第一条指令失败: %v", err)
+	}
+	if !program.Equals(solana.SystemProgramID) || len(inst.Data) < 4 ||
+		binary.LittleEndian.Uint32(inst.Data) != system.Instruction_AdvanceNonceAccount || len(inst.Accounts) < 3 {
+		return address, authority, fmt.Errorf("第一条指令不是AdvanceNonceAccount，交易不使用nonce")
+	}
+	accounts, err := inst.ResolveInstructionAccounts(&msg)
+	if err != nil {
+		return address, authority, fmt.Errorf("解析第一条指令的账户失败: %v", err)
+	}
+	return accounts[0].PublicKey, accounts[2].PublicKey, nil
+}
+
+// BroadcastOptions 广播参数
+type BroadcastOptions struct {
+	Commitment rpc.CommitmentType // 等待的承诺级别，默认confirmed
+	// Rounds 最多广播几轮，每轮150个块；nonce交易不会过期，一轮没上链且nonce未变时再来一轮。默认3
+	Rounds int
+	// OnAttempt 每次广播后回调
+	OnAttempt func(utils.SendAttempt)
+}
+
+// Broadcast 广播离线签好的nonce交易并等待确认。广播前检查签名和nonce：
+// nonce已被推进且交易没有上链时返回ErrNonceAdvanced，这笔交易已经不可能上链，需要用新nonce重新签名
+func Broadcast(ctx context.Context, rpcClient *rpc.Client, wsClient *ws.Client, tx *solana.Transaction, opts BroadcastOptions) (*utils.ConfirmationResult, error) {
+	if opts.Commitment == "" {
+		opts.Commitment = rpc.CommitmentConfirmed
+	}
+	if opts.Rounds <= 0 {
+		opts.Rounds = defaultRounds
+	}
+	if err := utils.CheckFullySigned(tx); err != nil {
+		return nil, err
+	}
+	address, _, err := Inspect(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for round := 0; ; round++ {
+		// nonce已变时交易可能就是自己（上一轮或之前已广播过），先查签名状态
+		if err := checkNonce(ctx, rpcClient, address, tx.Message.RecentBlockhash); err != nil {
+			if landed(ctx, rpcClient, tx.Signatures[0]) {
+				return utils.WaitForConfirmation(ctx, rpcClient, wsClient, tx.Signatures[0], opts.Commitment, confirmTimeout)
+			}
+			return nil, err
+		}
+		height, err := rpcClient.GetBlockHeight(ctx, opts.Commitment)
+		if err != nil {
+			return nil, fmt.Errorf("获取区块高度失败: %w", utils.ClassifyError(err))
+		}
+		result, err := utils.SendTransaction(ctx, rpcClient, wsClient, tx, height+broadcastWindow, utils.SendOptions{
+			Commitment: opts.Commitment,
+			OnAttempt:  opts.OnAttempt,
+		})
+		if !errors.Is(err, utils.ErrBlockhashExpired) || round+1 >= opts.Rounds {
+			return result, err
+		}
+	}
+}
+
+// checkNonce nonce账户当前的nonce是否仍为expected
+func checkNonce(ctx context.Context, rpcClient *rpc.Client, address solana.PublicKey, expected solana.Hash) error {
+	account, err := Fetch(ctx, rpcClient, address)
+	if err != nil {
+		return err
+	}
+	if !account.Nonce.Equals(expected) {
+		return fmt.Errorf("%w: 交易使用 %s，账户 %s 当前为 %s", ErrNonceAdvanced, expected, address, account.Nonce)
+	}
+	return nil
+}
+
+// landed 交易是否已上链（包括执行失败）
+func landed(ctx context.Context, rpcClient *rpc.Client, signature solana.Signature) bool {
+	res, err := rpcClient.GetSignatureStatuses(ctx, true, signature)
+	return err == nil && len(res.Value) > 0 && res.Value[0] != nil
+}
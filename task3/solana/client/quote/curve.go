@@ -0,0 +1,141 @@
+package quote
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// TradeDirection 兑换方向，部分曲线（固定价格、偏移）对A、B两侧不对称
+type TradeDirection uint8
+
+const (
+	AToB TradeDirection = iota
+	BToA
+)
+
+// Curve 不含手续费的兑换计算，与spl-token-swap的CurveCalculator::swap_without_fees一致
+type Curve interface {
+	// SwapWithoutFees 返回池子实际收下的源代币数量和转出的目标代币数量
+	SwapWithoutFees(sourceAmount, reserveIn, reserveOut *big.Int, dir TradeDirection) (sourceSwapped, destinationSwapped *big.Int, err error)
+	// SpotPrice 当前储备下的边际价格（每单位源代币可换的目标代币）
+	SpotPrice(reserveIn, reserveOut *big.Int, dir TradeDirection) (float64, error)
+	Name() string
+}
+
+// ConstantProduct x*y=k曲线
+type ConstantProduct struct{}
+
+func (ConstantProduct) Name() string { return "ConstantProduct" }
+
+func (ConstantProduct) SwapWithoutFees(sourceAmount, reserveIn, reserveOut *big.Int, _ TradeDirection) (*big.Int, *big.Int, error) {
+	return constantProductSwap(sourceAmount, reserveIn, reserveOut)
+}
+
+func (ConstantProduct) SpotPrice(reserveIn, reserveOut *big.Int, _ TradeDirection) (float64, error) {
+	return ratio(reserveOut, reserveIn)
+}
+
+// ConstantPrice 固定价格曲线：1个B代币价值TokenBPrice个A代币
+type ConstantPrice struct {
+	TokenBPrice uint64
+}
+
+func (ConstantPrice) Name() string { return "ConstantPrice" }
+
+func (c ConstantPrice) SwapWithoutFees(sourceAmount, _, _ *big.Int, dir TradeDirection) (*big.Int, *big.Int, error) {
+	if c.TokenBPrice == 0 {
+		return nil, nil, fmt.Errorf("固定价格曲线的价格为0")
+	}
+	price := new(big.Int).SetUint64(c.TokenBPrice)
+	var swapped, out *big.Int
+	if dir == BToA {
+		swapped, out = new(big.Int).Set(sourceAmount), new(big.Int).Mul(sourceAmount, price)
+	} else {
+		// 买B时余数部分不扣，避免多收A代币
+		var rem *big.Int
+		out, rem = new(big.Int).QuoRem(sourceAmount, price, new(big.Int))
+		swapped = new(big.Int).Sub(sourceAmount, rem)
+	}
+	if swapped.Sign() == 0 || out.Sign() == 0 {
+		return nil, nil, fmt.Errorf("兑换数量太小，得到的目标代币为0")
+	}
+	return swapped, out, nil
+}
+
+func (c ConstantPrice) SpotPrice(_, _ *big.Int, dir TradeDirection) (float64, error) {
+	if c.TokenBPrice == 0 {
+		return 0, fmt.Errorf("固定价格曲线的价格为0")
+	}
+	if dir == BToA {
+		return float64(c.TokenBPrice), nil
+	}
+	return 1 / float64(c.TokenBPrice), nil
+}
+
+// Offset 在B侧储备上加虚拟偏移量的x*y=k曲线，用于单边启动的池子
+type Offset struct {
+	TokenBOffset uint64
+}
+
+func (Offset) Name() string { return "Offset" }
+
+func (o Offset) adjust(reserveIn, reserveOut *big.Int, dir TradeDirection) (*big.Int, *big.Int) {
+	offset := new(big.Int).SetUint64(o.TokenBOffset)
+	if dir == AToB {
+		return reserveIn, new(big.Int).Add(reserveOut, offset)
+	}
+	return new(big.Int).Add(reserveIn, offset), reserveOut
+}
+
+func (o Offset) SwapWithoutFees(sourceAmount, reserveIn, reserveOut *big.Int, dir TradeDirection) (*big.Int, *big.Int, error) {
+	in, out := o.adjust(reserveIn, reserveOut, dir)
+	return constantProductSwap(sourceAmount, in, out)
+}
+
+func (o Offset) SpotPrice(reserveIn, reserveOut *big.Int, dir TradeDirection) (float64, error) {
+	in, out := o.adjust(reserveIn, reserveOut, dir)
+	return ratio(out, in)
+}
+
+// constantProductSwap 对应spl-token-swap的constant_product::swap，目标余额向上取整，对池子有利
+func constantProductSwap(sourceAmount, reserveIn, reserveOut *big.Int) (*big.Int, *big.Int, error) {
+	if reserveIn.Sign() == 0 || reserveOut.Sign() == 0 {
+		return nil, nil, fmt.Errorf("池子余额为0，无法兑换")
+	}
+	invariant := new(big.Int).Mul(reserveIn, reserveOut)
+	newIn := new(big.Int).Add(reserveIn, sourceAmount)
+	newOut, newIn, ok := ceilDiv(invariant, newIn)
+	if !ok {
+		return nil, nil, fmt.Errorf("池子余额不足")
+	}
+	out := new(big.Int).Sub(reserveOut, newOut)
+	if out.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("兑换数量太小，得到的目标代币为0")
+	}
+	return new(big.Int).Sub(newIn, reserveIn), out, nil
+}
+
+// ceilDiv 对应spl-math的checked_ceil_div：返回向上取整的商，以及用该商反推的向上取整除数
+func ceilDiv(dividend, divisor *big.Int) (quotient, newDivisor *big.Int, ok bool) {
+	quotient, rem := new(big.Int).QuoRem(dividend, divisor, new(big.Int))
+	if quotient.Sign() == 0 {
+		return nil, nil, false
+	}
+	newDivisor = new(big.Int).Set(divisor)
+	if rem.Sign() > 0 {
+		quotient.Add(quotient, big.NewInt(1))
+		newDivisor, rem = new(big.Int).QuoRem(dividend, quotient, new(big.Int))
+		if rem.Sign() > 0 {
+			newDivisor.Add(newDivisor, big.NewInt(1))
+		}
+	}
+	return quotient, newDivisor, true
+}
+
+func ratio(num, den *big.Int) (float64, error) {
+	if den.Sign() == 0 {
+		return 0, fmt.Errorf("池子余额为0，无法计算价格")
+	}
+	f, _ := new(big.Rat).SetFrac(num, den).Float64()
+	return f, nil
+}
@@ -0,0 +1,156 @@
+package quote
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// 纯Go的兑换报价计算，不依赖网络：输入池子储备、手续费参数和曲线，
+// 得到预期输出、价格影响、滑点保护下的最少到账数量以及各项手续费。
+// 计算方式与spl-token-swap程序一致，报价结果就是链上实际会执行的结果。
+
+// MaxBps 基点上限，10000 = 100%
+const MaxBps = 10000
+
+// Fees 池子的手续费参数，均为分子/分母形式
+type Fees struct {
+	TradeFeeNumerator           uint64
+	TradeFeeDenominator         uint64
+	OwnerTradeFeeNumerator      uint64
+	OwnerTradeFeeDenominator    uint64
+	OwnerWithdrawFeeNumerator   uint64
+	OwnerWithdrawFeeDenominator uint64
+	HostFeeNumerator            uint64
+	HostFeeDenominator          uint64
+}
+
+// TradingFee 给LP的交易手续费
+func (f Fees) TradingFee(amount uint64) uint64 {
+	return calculateFee(amount, f.TradeFeeNumerator, f.TradeFeeDenominator)
+}
+
+// OwnerTradingFee 给池子所有者的交易手续费
+func (f Fees) OwnerTradingFee(amount uint64) uint64 {
+	return calculateFee(amount, f.OwnerTradeFeeNumerator, f.OwnerTradeFeeDenominator)
+}
+
+// Params 报价输入
+type Params struct {
+	AmountIn    uint64
+	ReserveIn   uint64
+	ReserveOut  uint64
+	Fees        Fees
+	Curve       Curve
+	Direction   TradeDirection
+	SlippageBps uint64 // 滑点容忍度，单位为基点（100 = 1%）
+}
+
+// Result 报价结果
+type Result struct {
+	Curve            string
+	AmountIn         uint64 // 实际从用户扣除的源代币数量（含手续费）
+	AmountOut        uint64 // 预期收到的目标代币数量
+	MinimumAmountOut uint64 // 滑点保护下最少应收到的数量
+	TradeFee         uint64 // 留在池子里给LP的手续费
+	OwnerFee         uint64 // 给池子所有者的手续费（以LP代币形式铸造）
+	ReserveIn        uint64
+	ReserveOut       uint64
+	SlippageBps      uint64
+	SpotPrice        float64 // 兑换前的边际价格（每单位源代币可换的目标代币）
+	ExecutionPrice   float64 // 实际成交均价（含手续费）
+	PriceImpact      float64 // 曲线造成的价格偏离比例，不含手续费，0.01 = 1%
+}
+
+// TotalFee 总手续费
+func (r *Result) TotalFee() uint64 {
+	return r.TradeFee + r.OwnerFee
+}
+
+func (r *Result) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "曲线: %s\n", r.Curve)
+	fmt.Fprintf(&b, "池子储备: %d -> %d\n", r.ReserveIn, r.ReserveOut)
+	fmt.Fprintf(&b, "输入: %d, 预期输出: %d\n", r.AmountIn, r.AmountOut)
+	fmt.Fprintf(&b, "手续费: %d (LP %d, 所有者 %d)\n", r.TotalFee(), r.TradeFee, r.OwnerFee)
+	fmt.Fprintf(&b, "价格: 当前 %.9g, 成交 %.9g, 价格影响 %.4f%%\n", r.SpotPrice, r.ExecutionPrice, r.PriceImpact*100)
+	fmt.Fprintf(&b, "最少到账: %d (滑点 %.2f%%)", r.MinimumAmountOut, float64(r.SlippageBps)/100)
+	return b.String()
+}
+
+// Quote 计算一次兑换的报价。先扣除手续费，再按曲线计算池子转出的目标代币，
+// 与spl-token-swap的SwapCurve::swap一致
+func Quote(p Params) (*Result, error) {
+	if p.Curve == nil {
+		return nil, fmt.Errorf("未指定兑换曲线")
+	}
+	if p.SlippageBps > MaxBps {
+		return nil, fmt.Errorf("滑点容忍度不能超过%d基点: %d", MaxBps, p.SlippageBps)
+	}
+	if p.AmountIn == 0 {
+		return nil, fmt.Errorf("兑换数量不能为0")
+	}
+	tradeFee := p.Fees.TradingFee(p.AmountIn)
+	ownerFee := p.Fees.OwnerTradingFee(p.AmountIn)
+	if tradeFee+ownerFee >= p.AmountIn {
+		return nil, fmt.Errorf("兑换数量 %d 不足以支付手续费", p.AmountIn)
+	}
+	sourceLessFees := p.AmountIn - tradeFee - ownerFee
+
+	reserveIn, reserveOut := new(big.Int).SetUint64(p.ReserveIn), new(big.Int).SetUint64(p.ReserveOut)
+	swapped, out, err := p.Curve.SwapWithoutFees(new(big.Int).SetUint64(sourceLessFees), reserveIn, reserveOut, p.Direction)
+	if err != nil {
+		return nil, fmt.Errorf("%s曲线计算失败: %v", p.Curve.Name(), err)
+	}
+	if out.Cmp(reserveOut) > 0 {
+		return nil, fmt.Errorf("池子余额不足: 需要转出 %s，池子仅有 %d", out, p.ReserveOut)
+	}
+	spot, err := p.Curve.SpotPrice(reserveIn, reserveOut, p.Direction)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Result{
+		Curve: p.Curve.Name(),
+		// 向上取整后池子实际收下的源代币可能略少于输入，程序只会扣这部分加手续费
+		AmountIn:    swapped.Uint64() + tradeFee + ownerFee,
+		AmountOut:   out.Uint64(),
+		TradeFee:    tradeFee,
+		OwnerFee:    ownerFee,
+		ReserveIn:   p.ReserveIn,
+		ReserveOut:  p.ReserveOut,
+		SlippageBps: p.SlippageBps,
+		SpotPrice:   spot,
+	}
+	r.MinimumAmountOut = MinimumAmountOut(r.AmountOut, p.SlippageBps)
+	r.ExecutionPrice = float64(r.AmountOut) / float64(r.AmountIn)
+	if spot > 0 && swapped.Sign() > 0 {
+		noFeePrice := float64(r.AmountOut) / float64(swapped.Uint64())
+		if impact := 1 - noFeePrice/spot; impact > 0 {
+			r.PriceImpact = impact
+		}
+	}
+	return r, nil
+}
+
+// MinimumAmountOut 按滑点容忍度（基点，100 = 1%）计算最少应收到的数量
+func MinimumAmountOut(amountOut, slippageBps uint64) uint64 {
+	if slippageBps >= MaxBps {
+		return 0
+	}
+	v := new(big.Int).Mul(new(big.Int).SetUint64(amountOut), new(big.Int).SetUint64(MaxBps-slippageBps))
+	return v.Quo(v, big.NewInt(MaxBps)).Uint64()
+}
+
+// calculateFee 与spl-token-swap一致：amount*num/den向下取整，费率不为0时至少收1
+func calculateFee(amount, num, den uint64) uint64 {
+	if num == 0 || amount == 0 || den == 0 {
+		return 0
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(amount), new(big.Int).SetUint64(num))
+	fee.Quo(fee, new(big.Int).SetUint64(den))
+	if fee.Sign() == 0 {
+		return 1
+	}
+	return fee.Uint64()
+}
@@ -0,0 +1,160 @@
+package quote
+
+import (
+	"strings"
+	"testing"
+)
+
+// 常见的池子费率：交易手续费0.25%，所有者手续费0.05%
+var testFees = Fees{
+	TradeFeeNumerator:        25,
+	TradeFeeDenominator:      10000,
+	OwnerTradeFeeNumerator:   5,
+	OwnerTradeFeeDenominator: 10000,
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       Params
+		wantIn       uint64
+		wantOut      uint64
+		wantTradeFee uint64
+		wantOwnerFee uint64
+		wantErr      string
+	}{
+		// 取整用例与spl-token-swap constant_product.rs的constant_product_swap_rounding一致
+		{name: "恒定乘积/大池子", params: Params{AmountIn: 10, ReserveIn: 4_000_000, ReserveOut: 70_000_000_000, Curve: ConstantProduct{}}, wantIn: 10, wantOut: 174_999},
+		{name: "恒定乘积/只扣实际用到的源代币", params: Params{AmountIn: 20, ReserveIn: 30_000 - 20, ReserveOut: 10_000, Curve: ConstantProduct{}}, wantIn: 18, wantOut: 6},
+		{name: "恒定乘积/输入19", params: Params{AmountIn: 19, ReserveIn: 30_000 - 20, ReserveOut: 10_000, Curve: ConstantProduct{}}, wantIn: 18, wantOut: 6},
+		{name: "恒定乘积/输入18", params: Params{AmountIn: 18, ReserveIn: 30_000 - 20, ReserveOut: 10_000, Curve: ConstantProduct{}}, wantIn: 18, wantOut: 6},
+		{name: "恒定乘积/输出向下取整", params: Params{AmountIn: 10, ReserveIn: 20_000, ReserveOut: 30_000, Curve: ConstantProduct{}}, wantIn: 10, wantOut: 14},
+		{name: "恒定乘积/接近整除", params: Params{AmountIn: 10, ReserveIn: 20_000 - 9, ReserveOut: 30_000, Curve: ConstantProduct{}}, wantIn: 10, wantOut: 14},
+		{name: "恒定乘积/整除", params: Params{AmountIn: 10, ReserveIn: 20_000 - 10, ReserveOut: 30_000, Curve: ConstantProduct{}}, wantIn: 10, wantOut: 15},
+		{name: "恒定乘积/输入100", params: Params{AmountIn: 100, ReserveIn: 60_000, ReserveOut: 30_000, Curve: ConstantProduct{}}, wantIn: 99, wantOut: 49},
+		{name: "恒定乘积/输入98", params: Params{AmountIn: 98, ReserveIn: 60_000, ReserveOut: 30_000, Curve: ConstantProduct{}}, wantIn: 97, wantOut: 48},
+		{name: "恒定乘积/手续费", params: Params{AmountIn: 10_000, ReserveIn: 1_000_000, ReserveOut: 2_000_000, Fees: testFees, Curve: ConstantProduct{}}, wantIn: 10_000, wantOut: 19_743, wantTradeFee: 25, wantOwnerFee: 5},
+		{name: "恒定乘积/手续费不足1时收1", params: Params{AmountIn: 1000, ReserveIn: 1_000_000, ReserveOut: 2_000_000, Fees: testFees, Curve: ConstantProduct{}}, wantIn: 1000, wantOut: 1992, wantTradeFee: 2, wantOwnerFee: 1},
+		{name: "恒定乘积/输入不够付手续费", params: Params{AmountIn: 2, ReserveIn: 1_000_000, ReserveOut: 2_000_000, Fees: testFees, Curve: ConstantProduct{}}, wantErr: "不足以支付手续费"},
+		{name: "恒定乘积/输出为0", params: Params{AmountIn: 1, ReserveIn: 1_000_000, ReserveOut: 10, Curve: ConstantProduct{}}, wantErr: "得到的目标代币为0"},
+		{name: "恒定乘积/源储备为0", params: Params{AmountIn: 1000, ReserveIn: 0, ReserveOut: 1_000_000, Curve: ConstantProduct{}}, wantErr: "池子余额为0"},
+		{name: "恒定乘积/目标储备为0", params: Params{AmountIn: 1000, ReserveIn: 1_000_000, ReserveOut: 0, Curve: ConstantProduct{}}, wantErr: "池子余额为0"},
+
+		{name: "稳定/平衡池子", params: Params{AmountIn: 1000, ReserveIn: 1_000_000, ReserveOut: 1_000_000, Curve: Stable{Amp: 100}}, wantIn: 1000, wantOut: 999},
+		{name: "稳定/大额", params: Params{AmountIn: 100_000, ReserveIn: 1_000_000, ReserveOut: 1_000_000, Curve: Stable{Amp: 100}}, wantIn: 100_000, wantOut: 99_900},
+		{name: "稳定/amp为1", params: Params{AmountIn: 100_000, ReserveIn: 1_000_000, ReserveOut: 1_000_000, Curve: Stable{Amp: 1}}, wantIn: 100_000, wantOut: 95_227},
+		{name: "稳定/不平衡池子", params: Params{AmountIn: 10_000, ReserveIn: 1_000_000, ReserveOut: 2_000_000, Curve: Stable{Amp: 100}}, wantIn: 10_000, wantOut: 10_081},
+		{name: "稳定/手续费", params: Params{AmountIn: 1000, ReserveIn: 1_000_000, ReserveOut: 1_000_000, Fees: testFees, Curve: Stable{Amp: 100}}, wantIn: 1000, wantOut: 996, wantTradeFee: 2, wantOwnerFee: 1},
+		{name: "稳定/amp为0", params: Params{AmountIn: 1000, ReserveIn: 1_000_000, ReserveOut: 1_000_000, Curve: Stable{}}, wantErr: "amp为0"},
+		{name: "稳定/储备为0", params: Params{AmountIn: 1000, ReserveIn: 0, ReserveOut: 0, Curve: Stable{Amp: 100}}, wantErr: "池子余额为0"},
+
+		{name: "固定价格/买B扣掉余数", params: Params{AmountIn: 25, ReserveIn: 1000, ReserveOut: 1000, Curve: ConstantPrice{TokenBPrice: 10}}, wantIn: 20, wantOut: 2},
+		{name: "固定价格/卖B", params: Params{AmountIn: 3, ReserveIn: 1000, ReserveOut: 1000, Curve: ConstantPrice{TokenBPrice: 10}, Direction: BToA}, wantIn: 3, wantOut: 30},
+		{name: "固定价格/不够买1个B", params: Params{AmountIn: 9, ReserveIn: 1000, ReserveOut: 1000, Curve: ConstantPrice{TokenBPrice: 10}}, wantErr: "得到的目标代币为0"},
+		{name: "固定价格/池子A不够", params: Params{AmountIn: 200, ReserveIn: 1000, ReserveOut: 1000, Curve: ConstantPrice{TokenBPrice: 10}, Direction: BToA}, wantErr: "池子余额不足"},
+		{name: "固定价格/价格为0", params: Params{AmountIn: 10, ReserveIn: 1000, ReserveOut: 1000, Curve: ConstantPrice{}}, wantErr: "价格为0"},
+
+		// 单边启动的池子：B储备为0，靠偏移量定价
+		{name: "偏移/卖B", params: Params{AmountIn: 1000, ReserveIn: 0, ReserveOut: 1_000_000, Curve: Offset{TokenBOffset: 1_000_000}, Direction: BToA}, wantIn: 1000, wantOut: 999},
+		{name: "偏移/B储备为0时不能买B", params: Params{AmountIn: 1000, ReserveIn: 1_000_000, ReserveOut: 0, Curve: Offset{TokenBOffset: 1_000_000}}, wantErr: "池子余额不足"},
+		{name: "偏移/偏移量为0", params: Params{AmountIn: 1000, ReserveIn: 0, ReserveOut: 1_000_000, Curve: Offset{}, Direction: BToA}, wantErr: "池子余额为0"},
+
+		{name: "未指定曲线", params: Params{AmountIn: 1000, ReserveIn: 1000, ReserveOut: 1000}, wantErr: "未指定兑换曲线"},
+		{name: "输入为0", params: Params{ReserveIn: 1000, ReserveOut: 1000, Curve: ConstantProduct{}}, wantErr: "兑换数量不能为0"},
+		{name: "滑点超过100%", params: Params{AmountIn: 1000, ReserveIn: 1000, ReserveOut: 1000, Curve: ConstantProduct{}, SlippageBps: MaxBps + 1}, wantErr: "滑点容忍度"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Quote(tt.params)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v，期望包含 %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.AmountIn != tt.wantIn || r.AmountOut != tt.wantOut {
+				t.Fatalf("输入/输出 = %d/%d，期望 %d/%d", r.AmountIn, r.AmountOut, tt.wantIn, tt.wantOut)
+			}
+			if r.TradeFee != tt.wantTradeFee || r.OwnerFee != tt.wantOwnerFee {
+				t.Fatalf("手续费 = %d/%d，期望 %d/%d", r.TradeFee, r.OwnerFee, tt.wantTradeFee, tt.wantOwnerFee)
+			}
+			if r.AmountIn > tt.params.AmountIn {
+				t.Fatalf("扣除 %d 超过输入 %d", r.AmountIn, tt.params.AmountIn)
+			}
+			if r.PriceImpact < 0 {
+				t.Fatalf("价格影响为负: %v", r.PriceImpact)
+			}
+		})
+	}
+}
+
+func TestPriceImpact(t *testing.T) {
+	small, err := Quote(Params{AmountIn: 100, ReserveIn: 1_000_000, ReserveOut: 1_000_000, Curve: ConstantProduct{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := Quote(Params{AmountIn: 100_000, ReserveIn: 1_000_000, ReserveOut: 1_000_000, Curve: ConstantProduct{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small.SpotPrice != 1 {
+		t.Fatalf("平衡池子的价格 = %v", small.SpotPrice)
+	}
+	// 100000换90909，价格影响约9.09%
+	if large.PriceImpact < 0.09 || large.PriceImpact > 0.0910 {
+		t.Fatalf("价格影响 = %v", large.PriceImpact)
+	}
+	if small.PriceImpact >= large.PriceImpact {
+		t.Fatalf("小额价格影响 %v 不应大于大额 %v", small.PriceImpact, large.PriceImpact)
+	}
+
+	// 同样的储备，稳定曲线的价格影响远小于恒定乘积
+	stable, err := Quote(Params{AmountIn: 100_000, ReserveIn: 1_000_000, ReserveOut: 1_000_000, Curve: Stable{Amp: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stable.PriceImpact >= large.PriceImpact/10 {
+		t.Fatalf("稳定曲线价格影响 = %v，恒定乘积 = %v", stable.PriceImpact, large.PriceImpact)
+	}
+}
+
+func TestMinimumAmountOut(t *testing.T) {
+	tests := []struct {
+		amountOut, slippageBps, want uint64
+	}{
+		{19_743, 0, 19_743},
+		{19_743, 50, 19_644}, // 19644.285向下取整
+		{19_743, 100, 19_545},
+		{1, 1, 0},
+		{1000, MaxBps, 0},
+		{1000, MaxBps + 1, 0},
+		{^uint64(0), 1, 18_444_899_399_302_180_659}, // 中间结果超过u64也不能溢出
+	}
+	for _, tt := range tests {
+		if got := MinimumAmountOut(tt.amountOut, tt.slippageBps); got != tt.want {
+			t.Errorf("MinimumAmountOut(%d, %d) = %d，期望 %d", tt.amountOut, tt.slippageBps, got, tt.want)
+		}
+	}
+}
+
+func TestFees(t *testing.T) {
+	tests := []struct {
+		amount, num, den, want uint64
+	}{
+		{10_000, 25, 10_000, 25},
+		{1000, 25, 10_000, 2}, // 2.5向下取整
+		{100, 25, 10_000, 1},  // 不足1时收1
+		{1000, 0, 10_000, 0},
+		{1000, 25, 0, 0},
+		{0, 25, 10_000, 0},
+		{^uint64(0), 1, 2, ^uint64(0) / 2}, // 乘法不能溢出
+	}
+	for _, tt := range tests {
+		if got := calculateFee(tt.amount, tt.num, tt.den); got != tt.want {
+			t.Errorf("calculateFee(%d, %d, %d) = %d，期望 %d", tt.amount, tt.num, tt.den, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package quote
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// stableIterations 牛顿迭代次数上限，与spl-token-swap一致
+const stableIterations = 32
+
+// Stable StableSwap曲线（两种代币），Amp为放大系数
+type Stable struct {
+	Amp uint64
+}
+
+func (Stable) Name() string { return "Stable" }
+
+// leverage 对应compute_a：A * n
+func (s Stable) leverage() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(s.Amp), big.NewInt(2))
+}
+
+func (s Stable) SwapWithoutFees(sourceAmount, reserveIn, reserveOut *big.Int, _ TradeDirection) (*big.Int, *big.Int, error) {
+	if s.Amp == 0 {
+		return nil, nil, fmt.Errorf("稳定曲线的amp为0")
+	}
+	if sourceAmount.Sign() == 0 {
+		return new(big.Int), new(big.Int), nil
+	}
+	if reserveIn.Sign() == 0 || reserveOut.Sign() == 0 {
+		return nil, nil, fmt.Errorf("池子余额为0，无法兑换")
+	}
+	leverage := s.leverage()
+	d := computeD(leverage, reserveIn, reserveOut)
+	newIn := new(big.Int).Add(reserveIn, sourceAmount)
+	newOut, err := computeNewDestination(leverage, newIn, d)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := new(big.Int).Sub(reserveOut, newOut)
+	if out.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("兑换数量太小，得到的目标代币为0")
+	}
+	return new(big.Int).Set(sourceAmount), out, nil
+}
+
+// SpotPrice 对不变量 Ann(x+y) + D = Ann*D + D³/(4xy) 求隐函数导数 -dy/dx
+func (s Stable) SpotPrice(reserveIn, reserveOut *big.Int, _ TradeDirection) (float64, error) {
+	if reserveIn.Sign() == 0 || reserveOut.Sign() == 0 {
+		return 0, fmt.Errorf("池子余额为0，无法计算价格")
+	}
+	leverage := s.leverage()
+	d := new(big.Float).SetInt(computeD(leverage, reserveIn, reserveOut))
+	x, y := new(big.Float).SetInt(reserveIn), new(big.Float).SetInt(reserveOut)
+	ann := new(big.Float).SetInt(leverage)
+	d3 := new(big.Float).Mul(d, new(big.Float).Mul(d, d))
+	xy4 := new(big.Float).Mul(big.NewFloat(4), new(big.Float).Mul(x, y))
+	// Fx = Ann + D³/(4x²y), Fy = Ann + D³/(4xy²)
+	fx := new(big.Float).Add(ann, new(big.Float).Quo(d3, new(big.Float).Mul(xy4, x)))
+	fy := new(big.Float).Add(ann, new(big.Float).Quo(d3, new(big.Float).Mul(xy4, y)))
+	price, _ := new(big.Float).Quo(fx, fy).Float64()
+	return price, nil
+}
+
+// computeD 牛顿法求不变量D，对应spl-token-swap stable.rs的compute_d
+func computeD(leverage, amountA, amountB *big.Int) *big.Int {
+	sumX := new(big.Int).Add(amountA, amountB)
+	if sumX.Sign() == 0 {
+		return new(big.Int)
+	}
+	aTimesCoins := new(big.Int).Add(new(big.Int).Mul(amountA, big.NewInt(2)), big.NewInt(1))
+	bTimesCoins := new(big.Int).Add(new(big.Int).Mul(amountB, big.NewInt(2)), big.NewInt(1))
+	d := new(big.Int).Set(sumX)
+	for i := 0; i < stableIterations; i++ {
+		dProduct := new(big.Int).Mul(d, d)
+		dProduct.Quo(dProduct, aTimesCoins)
+		dProduct.Mul(dProduct, d)
+		dProduct.Quo(dProduct, bTimesCoins)
+		prev := d
+		d = calculateStep(d, leverage, sumX, dProduct)
+		if d.Cmp(prev) == 0 {
+			break
+		}
+	}
+	return d
+}
+
+// calculateStep d = (leverage*S + D_P*n) * d / ((leverage-1)*d + (n+1)*D_P)
+func calculateStep(d, leverage, sumX, dProduct *big.Int) *big.Int {
+	lVal := new(big.Int).Mul(leverage, sumX)
+	lVal.Add(lVal, new(big.Int).Mul(dProduct, big.NewInt(2)))
+	lVal.Mul(lVal, d)
+	rVal := new(big.Int).Mul(d, new(big.Int).Sub(leverage, big.NewInt(1)))
+	rVal.Add(rVal, new(big.Int).Mul(dProduct, big.NewInt(3)))
+	return lVal.Quo(lVal, rVal)
+}
+
+// computeNewDestination 求解 y² + b*y = c，对应compute_new_destination_amount
+func computeNewDestination(leverage, newSource, d *big.Int) (*big.Int, error) {
+	// c = D³ / (4 * x * leverage)
+	c := new(big.Int).Mul(d, d)
+	c.Mul(c, d)
+	c.Quo(c, new(big.Int).Mul(new(big.Int).Mul(newSource, big.NewInt(4)), leverage))
+	// b = x + D / leverage
+	b := new(big.Int).Add(newSource, new(big.Int).Quo(d, leverage))
+
+	y := new(big.Int).Set(d)
+	for i := 0; i < stableIterations; i++ {
+		num := new(big.Int).Add(new(big.Int).Mul(y, y), c)
+		den := new(big.Int).Add(new(big.Int).Mul(y, big.NewInt(2)), b)
+		den.Sub(den, d)
+		if den.Sign() <= 0 {
+			return nil, fmt.Errorf("稳定曲线计算失败")
+		}
+		yNew, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+		if rem.Sign() > 0 {
+			yNew.Add(yNew, big.NewInt(1))
+		}
+		if yNew.Cmp(y) == 0 {
+			break
+		}
+		y = yNew
+	}
+	return y, nil
+}
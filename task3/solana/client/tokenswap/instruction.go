@@ -0,0 +1,61 @@
+package tokenswap
+
+import (
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Token Swap程序的指令序号
+const (
+	InstructionInitialize uint8 = iota
+	InstructionSwap
+)
+
+// SwapAccounts Swap指令需要的账户，顺序与spl-token-swap程序一致
+type SwapAccounts struct {
+	Pool                    solana.PublicKey
+	Authority               solana.PublicKey
+	UserTransferAuthority   solana.PublicKey // 用户源代币账户的所有者或授权人，需要签名
+	Source                  solana.PublicKey // 用户的源代币账户
+	SwapSource              solana.PublicKey // 池子接收源代币的账户
+	SwapDestination         solana.PublicKey // 池子转出目标代币的账户
+	Destination             solana.PublicKey // 用户的目标代币账户
+	PoolMint                solana.PublicKey
+	PoolFeeAccount          solana.PublicKey
+	SourceMint              solana.PublicKey
+	DestinationMint         solana.PublicKey
+	SourceTokenProgram      solana.PublicKey
+	DestinationTokenProgram solana.PublicKey
+	PoolTokenProgram        solana.PublicKey  // LP代币（PoolMint）所属的Token程序
+	HostFeeAccount          *solana.PublicKey // 可选，位于PoolTokenProgram之后
+}
+
+// NewSwapInstruction 构造Swap指令，数据为 [1, amount_in(u64), minimum_amount_out(u64)]
+func NewSwapInstruction(programID solana.PublicKey, accounts SwapAccounts, amountIn, minimumAmountOut uint64) solana.Instruction {
+	data := make([]byte, 17)
+	data[0] = InstructionSwap
+	binary.LittleEndian.PutUint64(data[1:9], amountIn)
+	binary.LittleEndian.PutUint64(data[9:17], minimumAmountOut)
+
+	metas := solana.AccountMetaSlice{
+		solana.Meta(accounts.Pool),
+		solana.Meta(accounts.Authority),
+		solana.Meta(accounts.UserTransferAuthority).SIGNER(),
+		solana.Meta(accounts.Source).WRITE(),
+		solana.Meta(accounts.SwapSource).WRITE(),
+		solana.Meta(accounts.SwapDestination).WRITE(),
+		solana.Meta(accounts.Destination).WRITE(),
+		solana.Meta(accounts.PoolMint).WRITE(),
+		solana.Meta(accounts.PoolFeeAccount).WRITE(),
+		solana.Meta(accounts.SourceMint),
+		solana.Meta(accounts.DestinationMint),
+		solana.Meta(accounts.SourceTokenProgram),
+		solana.Meta(accounts.DestinationTokenProgram),
+		solana.Meta(accounts.PoolTokenProgram),
+	}
+	if accounts.HostFeeAccount != nil {
+		metas = append(metas, solana.Meta(*accounts.HostFeeAccount).WRITE())
+	}
+	return solana.NewInstruction(programID, metas, data)
+}
@@ -0,0 +1,136 @@
+package tokenswap
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"solana-go/client/quote"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// PoolStateLen SPL Token Swap池子账户（SwapVersion::SwapV1）的数据长度
+const PoolStateLen = 324
+
+// CurveType 池子使用的兑换曲线
+type CurveType uint8
+
+const (
+	CurveConstantProduct CurveType = iota // x*y=k
+	CurveConstantPrice                    // 固定价格
+	CurveStable                           // StableSwap
+	CurveOffset                           // 带虚拟偏移的x*y=k
+)
+
+func (c CurveType) String() string {
+	switch c {
+	case CurveConstantProduct:
+		return "ConstantProduct"
+	case CurveConstantPrice:
+		return "ConstantPrice"
+	case CurveStable:
+		return "Stable"
+	case CurveOffset:
+		return "Offset"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(c))
+	}
+}
+
+// Pool 解码后的池子状态
+type Pool struct {
+	Address        solana.PublicKey // 池子状态账户
+	ProgramID      solana.PublicKey // 池子所属的Token Swap程序
+	Version        uint8
+	IsInitialized  bool
+	BumpSeed       uint8
+	TokenProgramID solana.PublicKey
+	TokenA         solana.PublicKey // 池子持有A代币的账户
+	TokenB         solana.PublicKey // 池子持有B代币的账户
+	PoolMint       solana.PublicKey // LP代币
+	TokenAMint     solana.PublicKey
+	TokenBMint     solana.PublicKey
+	PoolFeeAccount solana.PublicKey
+	Fees           quote.Fees
+	CurveType      CurveType
+	CurveParams    [32]byte // 曲线参数，例如Stable曲线的amp、ConstantPrice的价格
+}
+
+// DecodePool 解码池子账户数据
+func DecodePool(address, programID solana.PublicKey, data []byte) (*Pool, error) {
+	if len(data) < PoolStateLen {
+		return nil, fmt.Errorf("池子账户数据长度 %d 小于 %d", len(data), PoolStateLen)
+	}
+	p := &Pool{
+		Address:       address,
+		ProgramID:     programID,
+		Version:       data[0],
+		IsInitialized: data[1] == 1,
+		BumpSeed:      data[2],
+	}
+	if p.Version != 1 {
+		return nil, fmt.Errorf("不支持的池子版本: %d", p.Version)
+	}
+	if !p.IsInitialized {
+		return nil, fmt.Errorf("池子 %s 尚未初始化", address)
+	}
+	off := 3
+	for _, key := range []*solana.PublicKey{
+		&p.TokenProgramID, &p.TokenA, &p.TokenB, &p.PoolMint,
+		&p.TokenAMint, &p.TokenBMint, &p.PoolFeeAccount,
+	} {
+		*key = solana.PublicKeyFromBytes(data[off : off+32])
+		off += 32
+	}
+	for _, fee := range []*uint64{
+		&p.Fees.TradeFeeNumerator, &p.Fees.TradeFeeDenominator,
+		&p.Fees.OwnerTradeFeeNumerator, &p.Fees.OwnerTradeFeeDenominator,
+		&p.Fees.OwnerWithdrawFeeNumerator, &p.Fees.OwnerWithdrawFeeDenominator,
+		&p.Fees.HostFeeNumerator, &p.Fees.HostFeeDenominator,
+	} {
+		*fee = binary.LittleEndian.Uint64(data[off : off+8])
+		off += 8
+	}
+	p.CurveType = CurveType(data[off])
+	copy(p.CurveParams[:], data[off+1:off+33])
+	return p, nil
+}
+
+// Curve 按曲线类型解析曲线参数，参数为小端序u64放在CurveParams开头
+func (p *Pool) Curve() (quote.Curve, error) {
+	param := binary.LittleEndian.Uint64(p.CurveParams[:8])
+	switch p.CurveType {
+	case CurveConstantProduct:
+		return quote.ConstantProduct{}, nil
+	case CurveConstantPrice:
+		return quote.ConstantPrice{TokenBPrice: param}, nil
+	case CurveStable:
+		return quote.Stable{Amp: param}, nil
+	case CurveOffset:
+		return quote.Offset{TokenBOffset: param}, nil
+	default:
+		return nil, fmt.Errorf("不支持的曲线类型: %s", p.CurveType)
+	}
+}
+
+// Authority 池子的权限PDA，由池子地址和bump种子推导，持有池子的代币账户
+func (p *Pool) Authority() (solana.PublicKey, error) {
+	authority, err := solana.CreateProgramAddress([][]byte{p.Address[:], {p.BumpSeed}}, p.ProgramID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("推导池子权限地址失败: %v", err)
+	}
+	return authority, nil
+}
+
+// Direction 根据输入输出代币确定池子内的源账户和目标账户
+func (p *Pool) Direction(inputMint, outputMint solana.PublicKey) (swapSource, swapDestination solana.PublicKey, aToB bool, err error) {
+	switch {
+	case inputMint.Equals(p.TokenAMint) && outputMint.Equals(p.TokenBMint):
+		return p.TokenA, p.TokenB, true, nil
+	case inputMint.Equals(p.TokenBMint) && outputMint.Equals(p.TokenAMint):
+		return p.TokenB, p.TokenA, false, nil
+	default:
+		return solana.PublicKey{}, solana.PublicKey{}, false,
+			fmt.Errorf("池子 %s 不支持 %s -> %s 的兑换（池子代币为 %s / %s）", p.Address, inputMint, outputMint, p.TokenAMint, p.TokenBMint)
+	}
+}
@@ -3,76 +3,249 @@ package tokenswap
 import (
 	"context"
 	"fmt"
+	"strconv"
 
+	"solana-go/client/lookuptable"
+	"solana-go/client/quote"
+	"solana-go/utils"
+
+	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
 )
 
-// 此部分功能未实现代币交换功能，包括创建账户、计算交换率、构建交换指令等
-//​​在 Ubuntu 中开发合约​​：编写和测试 Rust 智能合约
-//
-//​​构建和部署​​：在 Ubuntu 中构建和部署合约
-//
-//​​生成绑定代码​​：生成 Go 客户端代码
-//
-//​​在主机中开发客户端​​：在您的主机系统中开发 Go 客户端应用
-//
-//​​测试集成​​：测试客户端与合约的集成
+// SPL Token Swap程序的客户端：读取池子状态，按池子曲线和手续费计算预期输出，
+// 按滑点容忍度设置最少到账数量，构造Swap指令并由payer签名发送。
+// 程序ID取自池子账户的owner，不同网络部署的Token Swap程序都可以使用。
+
+// DefaultSlippageBps 默认滑点容忍度 0.5%
+const DefaultSlippageBps = 50
 
 type TokenSwapClient struct {
-	rpcClient *rpc.Client
-	payer     solana.PrivateKey
-	programID solana.PublicKey
+	rpcClient   *rpc.Client
+	payer       solana.PrivateKey
+	pool        solana.PublicKey
+	slippageBps uint64
+	tables      lookuptable.Tables // 设置后兑换交易构造为引用这些查找表的v0交易
 }
 
-func NewTokenSwapClient(rpcClient *rpc.Client, payer solana.PrivateKey) (*TokenSwapClient, error) {
+func NewTokenSwapClient(rpcClient *rpc.Client, payer solana.PrivateKey, pool solana.PublicKey) (*TokenSwapClient, error) {
+	if pool.IsZero() {
+		return nil, fmt.Errorf("池子地址不能为空")
+	}
 	return &TokenSwapClient{
-		rpcClient: rpcClient,
-		payer:     payer,
-		programID: solana.MustPublicKeyFromBase58("33NzBbD6AyJ3aEgcvYjGuqkfp2FRfSCFQJdpoRNUrFzV"), // 替换为实际程序ID
+		rpcClient:   rpcClient,
+		payer:       payer,
+		pool:        pool,
+		slippageBps: DefaultSlippageBps,
 	}, nil
 }
 
+// SetSlippageBps 设置滑点容忍度，单位为基点（100 = 1%）
+func (c *TokenSwapClient) SetSlippageBps(bps uint64) error {
+	if bps > quote.MaxBps {
+		return fmt.Errorf("滑点容忍度不能超过%d基点: %d", quote.MaxBps, bps)
+	}
+	c.slippageBps = bps
+	return nil
+}
+
+// SetLookupTables 设置兑换交易引用的地址查找表（可用lookuptable.Fetch读取），传nil恢复legacy交易
+func (c *TokenSwapClient) SetLookupTables(tables lookuptable.Tables) {
+	c.tables = tables
+}
+
+// LoadPool 读取并解码池子账户
+func (c *TokenSwapClient) LoadPool(ctx context.Context) (*Pool, error) {
+	info, err := c.rpcClient.GetAccountInfoWithOpts(ctx, c.pool, &rpc.GetAccountInfoOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取池子账户失败: %v", err)
+	}
+	return DecodePool(c.pool, info.Value.Owner, info.Value.Data.GetBinary())
+}
+
+// Reserves 读取池子两个代币账户的余额
+func (c *TokenSwapClient) Reserves(ctx context.Context, pool *Pool) (reserveA, reserveB uint64, err error) {
+	res, err := c.rpcClient.GetMultipleAccountsWithOpts(ctx, []solana.PublicKey{pool.TokenA, pool.TokenB}, &rpc.GetMultipleAccountsOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("读取池子代币账户失败: %v", err)
+	}
+	amounts := make([]uint64, 2)
+	for i, acc := range res.Value {
+		if acc == nil {
+			return 0, 0, fmt.Errorf("池子代币账户不存在")
+		}
+		var tokenAccount token.Account
+		if err := tokenAccount.UnmarshalWithDecoder(bin.NewBinDecoder(acc.Data.GetBinary())); err != nil {
+			return 0, 0, fmt.Errorf("解析池子代币账户失败: %v", err)
+		}
+		amounts[i] = tokenAccount.Amount
+	}
+	return amounts[0], amounts[1], nil
+}
+
+// Quote 读取池子状态和储备，计算用amount个inputTokenMint能换到多少outputTokenMint
+func (c *TokenSwapClient) Quote(ctx context.Context, inputTokenMint, outputTokenMint solana.PublicKey, amount uint64) (*Pool, *quote.Result, error) {
+	pool, err := c.LoadPool(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, _, aToB, err := pool.Direction(inputTokenMint, outputTokenMint)
+	if err != nil {
+		return nil, nil, err
+	}
+	curve, err := pool.Curve()
+	if err != nil {
+		return nil, nil, err
+	}
+	reserveA, reserveB, err := c.Reserves(ctx, pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	params := quote.Params{
+		AmountIn:    amount,
+		ReserveIn:   reserveA,
+		ReserveOut:  reserveB,
+		Fees:        pool.Fees,
+		Curve:       curve,
+		Direction:   quote.AToB,
+		SlippageBps: c.slippageBps,
+	}
+	if !aToB {
+		params.ReserveIn, params.ReserveOut, params.Direction = reserveB, reserveA, quote.BToA
+	}
+	result, err := quote.Quote(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pool, result, nil
+}
+
+// SwapTokens 用amount个inputTokenMint兑换outputTokenMint。
+// 源代币从payer的关联代币账户扣除，目标关联代币账户不存在时在同一笔交易中创建。
+// 两个mint可以分属Token和Token-2022程序，程序ID取自各自mint账户的owner。
+// 交易先模拟，失败时不发送；发送后等待确认，区块哈希过期时由payer重新签名
 func (c *TokenSwapClient) SwapTokens(
 	ctx context.Context,
+	wsClient *ws.Client,
 	inputTokenMint solana.PublicKey,
 	outputTokenMint solana.PublicKey,
 	amount uint64,
 	recentBlockhash solana.Hash,
+	lastValidBlockHeight uint64,
 ) (solana.Signature, error) {
-	// 这里实现具体的代币交换逻辑
-	// 包括创建账户、计算交换率、构建交换指令等
+	pool, q, err := c.Quote(ctx, inputTokenMint, outputTokenMint, amount)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	swapSource, swapDestination, _, err := pool.Direction(inputTokenMint, outputTokenMint)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	authority, err := pool.Authority()
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	inputMint, err := utils.GetMintInfo(ctx, c.rpcClient, inputTokenMint)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	outputMint, err := utils.GetMintInfo(ctx, c.rpcClient, outputTokenMint)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+
+	owner := c.payer.PublicKey()
+	source, err := utils.FindAssociatedTokenAddress(owner, inputTokenMint, inputMint.ProgramID)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	destination, err := utils.FindAssociatedTokenAddress(owner, outputTokenMint, outputMint.ProgramID)
+	if err != nil {
+		return solana.Signature{}, err
+	}
 
-	// 示例: 创建关联代币账户
-	//associatedTokenAccount, _, err := token.FindAssociatedTokenAddress(
-	//	c.payer.PublicKey(),
-	//	inputTokenMint,
-	//)
-	//if err != nil {
-	//	return solana.Signature{}, fmt.Errorf("查找关联账户失败: %v", err)
-	//}
+	balance, err := c.rpcClient.GetTokenAccountBalance(ctx, source, rpc.CommitmentConfirmed)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("查询源代币账户 %s 余额失败: %v", source, err)
+	}
+	have, err := parseAmount(balance.Value.Amount)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	if have < q.AmountIn {
+		return solana.Signature{}, fmt.Errorf("源代币余额不足: 余额 %d，需要 %d", have, q.AmountIn)
+	}
 
-	// 构建交换指令
-	instructions := []solana.Instruction{
-		// 这里添加实际的交换指令
-		// 可能包括: 批准转账、执行交换、更新余额等
+	// 目标关联代币账户不存在时创建，已存在时该指令不做任何事
+	createDestination, err := utils.NewCreateIdempotentInstruction(owner, owner, outputTokenMint, outputMint.ProgramID)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	instructions := []solana.Instruction{createDestination, NewSwapInstruction(pool.ProgramID, SwapAccounts{
+		Pool:                    pool.Address,
+		Authority:               authority,
+		UserTransferAuthority:   owner,
+		Source:                  source,
+		SwapSource:              swapSource,
+		SwapDestination:         swapDestination,
+		Destination:             destination,
+		PoolMint:                pool.PoolMint,
+		PoolFeeAccount:          pool.PoolFeeAccount,
+		SourceMint:              inputTokenMint,
+		DestinationMint:         outputTokenMint,
+		SourceTokenProgram:      inputMint.ProgramID,
+		DestinationTokenProgram: outputMint.ProgramID,
+		PoolTokenProgram:        pool.TokenProgramID,
+	}, amount, q.MinimumAmountOut)}
+
+	// 兑换涉及的账户多、计算量大，按模拟结果设置计算单元上限和优先费
+	instructions, _, err = utils.WithComputeBudget(ctx, c.rpcClient, instructions, owner, utils.DefaultFeeOptions())
+	if err != nil {
+		return solana.Signature{}, err
 	}
 
 	// 构造交易
-	tx, err := solana.NewTransaction(
-		instructions,
-		recentBlockhash,
-		solana.TransactionPayer(c.payer.PublicKey()),
-	)
+	tx, err := lookuptable.NewV0Transaction(instructions, recentBlockhash, owner, c.tables)
 	if err != nil {
-		return solana.Signature{}, fmt.Errorf("构造交易失败: %v", err)
+		return solana.Signature{}, err
 	}
 
-	// 签名并发送交易
-	signature, err := c.rpcClient.SendTransaction(ctx, tx)
+	// 签名交易
+	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if owner.Equals(key) {
+			return &c.payer
+		}
+		return nil
+	})
 	if err != nil {
-		return solana.Signature{}, fmt.Errorf("发送交易失败: %v", err)
+		return solana.Signature{}, fmt.Errorf("签名交易失败: %v", err)
 	}
 
-	return signature, nil
+	// 发送交易并等待确认
+	result, err := utils.SendTransaction(ctx, c.rpcClient, wsClient, tx, lastValidBlockHeight, utils.SendOptions{
+		Signers:    []solana.PrivateKey{c.payer},
+		MaxResigns: 2,
+	})
+	if err != nil {
+		return tx.Signatures[0], err
+	}
+	return result.Signature, nil
+}
+
+// parseAmount 解析RPC返回的字符串形式的代币数量
+func parseAmount(s string) (uint64, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无法解析代币数量 %q: %w", s, err)
+	}
+	return v, nil
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"solana-go/client/anchor/gen"
+)
+
+// 由Anchor IDL（anchor build生成的target/idl/<程序名>.json，新旧格式均可）生成Go客户端。
+// -check不写文件，只比较生成结果与-out是否一致，用于检查生成的代码和golden文件是否过期。
+//
+//	go run ./cmd/anchorgen -idl target/idl/counter.json -out client/counter/counter.go
+//	go run ./cmd/anchorgen -idl client/anchor/gen/testdata/counter.json -out client/anchor/gen/testdata/counter.golden -check
+//	go run ./cmd/anchorgen -idl client/anchor/gen/testdata/counter_legacy.json -out client/anchor/gen/testdata/counter.golden -check
+func main() {
+	idlPath := flag.String("idl", "", "Anchor IDL JSON文件")
+	pkg := flag.String("pkg", "", "生成代码的包名，默认由程序名转换")
+	runtime := flag.String("runtime", gen.DefaultRuntime, "运行时包的导入路径")
+	out := flag.String("out", "", "输出文件，默认标准输出")
+	check := flag.Bool("check", false, "只比较生成结果与-out是否一致，不一致时以非零状态退出")
+	flag.Parse()
+
+	if *idlPath == "" {
+		log.Fatalf("需要用-idl指定IDL文件")
+	}
+	data, err := os.ReadFile(*idlPath)
+	if err != nil {
+		log.Fatalf("读取IDL失败: %v", err)
+	}
+	idl, err := gen.Parse(data)
+	if err != nil {
+		log.Fatalf("%s: %v", *idlPath, err)
+	}
+	src, err := gen.Generate(idl, gen.Options{Package: *pkg, Runtime: *runtime})
+	if err != nil {
+		log.Fatalf("生成代码失败: %v", err)
+	}
+
+	switch {
+	case *check:
+		if *out == "" {
+			log.Fatalf("-check需要用-out指定比较的文件")
+		}
+		want, err := os.ReadFile(*out)
+		if err != nil {
+			log.Fatalf("读取 %s 失败: %v", *out, err)
+		}
+		if !bytes.Equal(src, want) {
+			log.Fatalf("%s 已过期，与 %s 的生成结果不一致（第%d行起），去掉-check重新生成", *out, *idlPath, firstDiffLine(src, want))
+		}
+		fmt.Fprintf(os.Stderr, "%s 与 %s 的生成结果一致\n", *out, *idlPath)
+	case *out == "":
+		os.Stdout.Write(src)
+	default:
+		if err := os.WriteFile(*out, src, 0o644); err != nil {
+			log.Fatalf("写入 %s 失败: %v", *out, err)
+		}
+		fmt.Fprintf(os.Stderr, "已生成 %s: %d 条指令，%d 种账户，%d 种事件\n",
+			*out, len(idl.Instructions), len(idl.Accounts), len(idl.Events))
+	}
+}
+
+// firstDiffLine 两份内容第一处不同所在的行号
+func firstDiffLine(a, b []byte) int {
+	line := 1
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return line
+		}
+		if a[i] == '\n' {
+			line++
+		}
+	}
+	return line
+}
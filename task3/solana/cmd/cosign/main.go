@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"solana-go/client/nonce"
+	"solana-go/config"
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// 多方签名的转账：配置中的付款账户出手续费，转出账户可以是另一方的。
+// 一方构造并签好自己的部分，把base64交给另一方补签（或各自签名后合并），签名齐全后广播。
+// 区块哈希只有60~90秒有效期，来回传递需要更久时用-nonce-account改用durable nonce（见cmd/nonce）
+//
+//	go run ./cmd/cosign transfer -from <转出地址> -to <地址> -amount 0.1 -out tx.b64
+//	go run ./cmd/cosign inspect -in tx.b64
+//	go run ./cmd/cosign sign -in tx.b64 -keypair other.json -out signed.b64
+//	go run ./cmd/cosign merge -in a.b64,b.b64 -out merged.b64
+//	go run ./cmd/cosign broadcast -in signed.b64
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	configPath := fs.String("config", "etc/config.yaml", "配置文件路径")
+	network := fs.String("network", "", "覆盖配置中的网络: devnet/testnet/mainnet/localnet")
+	in := fs.String("in", "", "base64交易文件，-表示标准输入；merge时逗号分隔多个文件")
+	out := fs.String("out", "", "输出的base64交易文件，默认标准输出")
+	keypairs := fs.String("keypair", "", "用来签名的密钥文件，逗号分隔；加密文件的口令取自SOLANA_KEYPAIR_PASSPHRASE")
+
+	var (
+		from, to, amount, nonceAccount *string
+		confirmMainnet                 *bool
+	)
+	switch cmd {
+	case "transfer":
+		from = fs.String("from", "", "转出地址，默认付款账户")
+		to = fs.String("to", "", "收款地址")
+		amount = fs.String("amount", "", "转账的SOL数量")
+		nonceAccount = fs.String("nonce-account", "", "使用durable nonce账户代替区块哈希，授权账户必须是付款账户")
+	case "sign", "inspect", "merge":
+	case "broadcast":
+		confirmMainnet = fs.Bool("confirm-mainnet", false, "确认在主网上发送交易（会使用真实资金）")
+	default:
+		usage()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath, *network)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	switch cmd {
+	case "transfer":
+		tx := buildTransfer(ctx, cfg, *from, *to, *amount, *nonceAccount, loadSigners(cfg, *keypairs))
+		writeTransaction(tx, *out)
+	case "sign":
+		tx := readTransaction(*in)
+		signers := loadSigners(cfg, *keypairs)
+		if len(signers) == 0 {
+			log.Fatalf("需要用-keypair指定签名用的密钥")
+		}
+		if err := utils.PartialSign(tx, signers...); err != nil {
+			log.Fatalf("%v", err)
+		}
+		writeTransaction(tx, *out)
+	case "inspect":
+		inspect(readTransaction(*in))
+	case "merge":
+		paths := strings.Split(*in, ",")
+		if len(paths) < 2 {
+			log.Fatalf("merge需要至少两个交易文件，用逗号分隔")
+		}
+		tx := readTransaction(paths[0])
+		others := make([]*solana.Transaction, 0, len(paths)-1)
+		for _, p := range paths[1:] {
+			others = append(others, readTransaction(p))
+		}
+		if err := utils.MergeSignatures(tx, others...); err != nil {
+			log.Fatalf("%v", err)
+		}
+		writeTransaction(tx, *out)
+	case "broadcast":
+		cfg.ConfirmMainnet = cfg.ConfirmMainnet || *confirmMainnet
+		if err := cfg.CheckMainnet(ctx); err != nil {
+			log.Fatalf("%v", err)
+		}
+		broadcast(ctx, cfg, readTransaction(*in))
+	}
+}
+
+// buildTransfer 构造from到to的转账，用付款账户和signers中已有的签名者签名
+func buildTransfer(ctx context.Context, cfg *config.Config, from, to, amount, nonceAccount string, signers []utils.Signer) *solana.Transaction {
+	payer, err := cfg.Payer()
+	if err != nil {
+		log.Fatalf("读取钱包失败: %v", err)
+	}
+	source := payer.PublicKey()
+	if from != "" {
+		source = mustAddress("-from", from)
+	}
+	lamports, err := utils.ParseTokenAmount(amount, 9)
+	if err != nil || lamports == 0 {
+		log.Fatalf("无效的数量 %q: %v", amount, err)
+	}
+	instructions := []solana.Instruction{system.NewTransferInstruction(lamports, source, mustAddress("-to", to)).Build()}
+
+	rpcClient := rpc.New(cfg.RPCEndpoint)
+	var blockhash solana.Hash
+	if nonceAccount != "" {
+		acc, err := nonce.Fetch(ctx, rpcClient, mustAddress("-nonce-account", nonceAccount))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		instructions = append([]solana.Instruction{nonce.AdvanceInstruction(acc.Address, acc.Authority)}, instructions...)
+		blockhash = acc.Nonce
+	} else {
+		latest, err := rpcClient.GetLatestBlockhash(ctx, cfg.CommitmentType())
+		if err != nil {
+			log.Fatalf("获取区块哈希失败: %v", err)
+		}
+		blockhash = latest.Value.Blockhash
+		fmt.Fprintf(os.Stderr, "区块哈希在高度 %d 后过期，请尽快完成签名和广播\n", latest.Value.LastValidBlockHeight)
+	}
+
+	tx, missing, err := utils.NewTxBuilder(payer.PublicKey(), blockhash).
+		Add(instructions...).
+		Sign(append([]utils.Signer{utils.NewKeySigner(payer)}, signers...)...).
+		Build()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fmt.Fprintf(os.Stderr, "转账 %s SOL: %s -> %s，付款账户 %s\n", amount, source, to, payer.PublicKey())
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "还需要以下账户签名: %s\n", keys(missing))
+	}
+	return tx
+}
+
+func inspect(tx *solana.Transaction) {
+	states, err := utils.SignatureStates(tx)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fmt.Printf("消息: %d 条指令，区块哈希/nonce %s\n", len(tx.Message.Instructions), tx.Message.RecentBlockhash)
+	if addr, authority, err := nonce.Inspect(tx); err == nil {
+		fmt.Printf("使用durable nonce: 账户 %s，授权账户 %s\n", addr, authority)
+	}
+	missing := 0
+	for i, s := range states {
+		status := "已签名"
+		switch {
+		case !s.Signed:
+			status = "缺少签名"
+			missing++
+		case !s.Valid:
+			status = "签名无效"
+		}
+		role := ""
+		if i == 0 {
+			role = "（付款账户）"
+		}
+		fmt.Printf("  %d. %s%s: %s\n", i+1, s.Signer, role, status)
+	}
+	if missing == 0 {
+		fmt.Println("签名已齐全")
+	}
+}
+
+func broadcast(ctx context.Context, cfg *config.Config, tx *solana.Transaction) {
+	if err := utils.CheckFullySigned(tx); err != nil {
+		log.Fatalf("%v", err)
+	}
+	rpcClient := rpc.New(cfg.RPCEndpoint)
+	wsClient, err := ws.Connect(ctx, cfg.WSEndpoint)
+	if err != nil {
+		log.Fatalf("WebSocket连接失败: %v", err)
+	}
+	defer wsClient.Close()
+
+	var result *utils.ConfirmationResult
+	onAttempt := func(a utils.SendAttempt) { log.Println(a) }
+	if _, _, err := nonce.Inspect(tx); err == nil {
+		result, err = nonce.Broadcast(ctx, rpcClient, wsClient, tx, nonce.BroadcastOptions{Commitment: cfg.CommitmentType(), OnAttempt: onAttempt})
+		if err != nil {
+			log.Fatalf("广播失败: %v", err)
+		}
+	} else {
+		// 不知道签名时的lastValidBlockHeight，按当前高度再广播一个有效期；过期后无法重新签名
+		height, err := rpcClient.GetBlockHeight(ctx, cfg.CommitmentType())
+		if err != nil {
+			log.Fatalf("获取区块高度失败: %v", err)
+		}
+		result, err = utils.SendTransaction(ctx, rpcClient, wsClient, tx, height+150, utils.SendOptions{
+			Commitment: cfg.CommitmentType(),
+			OnSimulation: func(r *utils.SimulationReport) {
+				log.Println(r)
+			},
+			OnAttempt: onAttempt,
+		})
+		if err != nil {
+			log.Fatalf("广播失败: %v", err)
+		}
+	}
+	fmt.Printf("交易已确认: %s (slot %d, %s)\n", result.Signature, result.Slot, result.Status)
+}
+
+func loadSigners(cfg *config.Config, paths string) []utils.Signer {
+	var signers []utils.Signer
+	for _, p := range strings.Split(paths, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		s, err := utils.NewFileSigner(p, cfg.Passphrase)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		signers = append(signers, s)
+	}
+	return signers
+}
+
+func readTransaction(path string) *solana.Transaction {
+	var (
+		data []byte
+		err  error
+	)
+	switch path {
+	case "":
+		log.Fatalf("需要用-in指定交易文件")
+	case "-":
+		data, err = io.ReadAll(os.Stdin)
+	default:
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		log.Fatalf("读取交易失败: %v", err)
+	}
+	tx, err := utils.DecodeTransaction(string(data))
+	if err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+	return tx
+}
+
+// writeTransaction 输出base64交易，并提示还缺哪些签名
+func writeTransaction(tx *solana.Transaction, path string) {
+	encoded, err := utils.EncodeTransaction(tx)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if missing, err := utils.MissingSigners(tx); err != nil {
+		log.Fatalf("%v", err)
+	} else if len(missing) == 0 {
+		fmt.Fprintln(os.Stderr, "签名已齐全，可以广播")
+	}
+	if path == "" {
+		fmt.Println(encoded)
+		return
+	}
+	if err := os.WriteFile(path, []byte(encoded+"\n"), 0o600); err != nil {
+		log.Fatalf("写入交易文件失败: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "已写入 %s\n", path)
+}
+
+func mustAddress(flagName, s string) solana.PublicKey {
+	if s == "" {
+		log.Fatalf("需要指定%s", flagName)
+	}
+	addr, err := solana.PublicKeyFromBase58(s)
+	if err != nil {
+		log.Fatalf("%s 不是有效的地址 %s: %v", flagName, s, err)
+	}
+	return addr
+}
+
+func keys(list []solana.PublicKey) string {
+	s := make([]string, len(list))
+	for i, k := range list {
+		s[i] = k.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: cosign <transfer|sign|inspect|merge|broadcast> [参数]，各子命令的参数见 -h")
+	os.Exit(2)
+}
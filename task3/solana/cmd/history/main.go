@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+
+	"solana-go/client/history"
+	"solana-go/config"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// 查看账户的交易历史：分页读取签名，解码系统、SPL Token、计算预算、Token Swap等指令，
+// 显示每笔交易的SOL和代币余额变化，可导出为JSON或CSV。默认查看配置中付款账户的最近20笔交易。
+//
+//	go run ./cmd/history -limit 50
+//	go run ./cmd/history -address <地址> -format csv -out history.csv
+//	go run ./cmd/history -before <签名> -limit 100 -format json
+func main() {
+	configPath := flag.String("config", "etc/config.yaml", "配置文件路径")
+	network := flag.String("network", "", "覆盖配置中的网络: devnet/testnet/mainnet/localnet")
+	address := flag.String("address", "", "查询的账户地址，默认配置中的付款账户")
+	limit := flag.Int("limit", 20, "最多读取的交易数")
+	pageSize := flag.Int("page-size", 0, "每页签名数，默认与limit相同，最大1000")
+	before := flag.String("before", "", "从该签名之前开始读取（用于继续翻页）")
+	until := flag.String("until", "", "读到该签名为止")
+	swapPrograms := flag.String("swap-programs", "", "额外识别的Token Swap程序地址，逗号分隔")
+	format := flag.String("format", "text", "输出格式：text、json或csv")
+	out := flag.String("out", "", "输出文件，默认标准输出")
+	flag.Parse()
+
+	if !slices.Contains(history.Formats, *format) {
+		log.Fatalf("未知的输出格式: %s（可选 %s）", *format, strings.Join(history.Formats, "/"))
+	}
+	cfg, err := config.Load(*configPath, *network)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	var account solana.PublicKey
+	if *address != "" {
+		if account, err = solana.PublicKeyFromBase58(*address); err != nil {
+			log.Fatalf("无效的地址 %s: %v", *address, err)
+		}
+	} else {
+		payer, err := cfg.Payer()
+		if err != nil {
+			log.Fatalf("读取钱包失败: %v", err)
+		}
+		account = payer.PublicKey()
+	}
+
+	opts := history.Options{Limit: *limit, PageSize: *pageSize}
+	if opts.Before, err = parseSignature(*before); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if opts.Until, err = parseSignature(*until); err != nil {
+		log.Fatalf("%v", err)
+	}
+	var programs []solana.PublicKey
+	for _, s := range strings.Split(*swapPrograms, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		p, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			log.Fatalf("无效的程序地址 %s: %v", s, err)
+		}
+		programs = append(programs, p)
+	}
+	opts.Decoder = history.NewDecoder(programs...)
+
+	w := os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("创建输出文件失败: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	// 文本格式边读边打印，其他格式读完后统一写出
+	if *format == "text" {
+		opts.OnEntry = func(e *history.Entry) {
+			if err := history.WriteText(w, e); err != nil {
+				log.Fatalf("%v", err)
+			}
+		}
+	} else if *out != "" {
+		count := 0
+		opts.OnEntry = func(*history.Entry) {
+			count++
+			fmt.Fprintf(os.Stderr, "\r已读取 %d 笔", count)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "网络: %s, 账户: %s\n", cfg.Network, account)
+	entries, err := history.Fetch(ctx, rpc.New(cfg.RPCEndpoint), account, opts)
+	if err != nil {
+		// 已读取的部分照常输出，最后一笔的签名可用作-before继续
+		log.Printf("读取交易历史失败: %v", err)
+	}
+	if *format != "text" {
+		if *out != "" && len(entries) > 0 {
+			fmt.Fprintln(os.Stderr)
+		}
+		if err := history.Write(w, *format, entries); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+	if len(entries) > 0 {
+		fmt.Fprintf(os.Stderr, "共 %d 笔交易，继续翻页: -before %s\n", len(entries), entries[len(entries)-1].Signature)
+	} else {
+		fmt.Fprintln(os.Stderr, "没有交易")
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func parseSignature(s string) (solana.Signature, error) {
+	if s == "" {
+		return solana.Signature{}, nil
+	}
+	sig, err := solana.SignatureFromBase58(s)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("无效的签名 %s: %v", s, err)
+	}
+	return sig, nil
+}
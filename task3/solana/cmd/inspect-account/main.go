@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"solana-go/client/account"
+	"solana-go/config"
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// 查看账户并解码数据：SPL Token账户和mint、Token Swap池子、nonce账户、地址查找表。
+// 可以直接给地址，也可以用-program和-seed推导PDA后查看；-bump指定已知的bump时不搜索规范bump。
+//
+//	go run ./cmd/inspect-account <地址> [<地址>...]
+//	go run ./cmd/inspect-account -program <程序ID> -seed string:vault -seed pubkey:<地址> -seed u64:1
+//	go run ./cmd/inspect-account -format json <地址>
+func main() {
+	configPath := flag.String("config", "etc/config.yaml", "配置文件路径")
+	network := flag.String("network", "", "覆盖配置中的网络: devnet/testnet/mainnet/localnet")
+	format := flag.String("format", "text", "输出格式：text或json")
+	program := flag.String("program", "", "推导PDA的程序ID")
+	bump := flag.Int("bump", -1, "已知的bump，默认搜索规范bump")
+	var seeds seedFlag
+	flag.Var(&seeds, "seed", "PDA种子，格式为类型:值（string/pubkey/hex/u8/u16/u32/u64），可重复，按顺序使用")
+	flag.Parse()
+
+	if *format != "text" && *format != "json" {
+		log.Fatalf("未知的输出格式: %s（可选 text/json）", *format)
+	}
+	var addresses []solana.PublicKey
+	if *program != "" {
+		addresses = append(addresses, derive(*program, *bump, seeds))
+	} else if len(seeds) > 0 {
+		log.Fatalf("-seed需要和-program一起使用")
+	}
+	for _, s := range flag.Args() {
+		addr, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			log.Fatalf("无效的地址 %s: %v", s, err)
+		}
+		addresses = append(addresses, addr)
+	}
+	if len(addresses) == 0 {
+		log.Fatalf("需要给出账户地址，或用-program和-seed推导PDA")
+	}
+
+	cfg, err := config.Load(*configPath, *network)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	rpcClient := rpc.New(cfg.RPCEndpoint)
+	accounts, err := account.DefaultRegistry().Fetch(ctx, rpcClient, addresses...)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fillDecimals(ctx, rpcClient, accounts)
+	if *format == "json" {
+		printJSON(accounts)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "网络: %s, RPC: %s\n", cfg.Network, cfg.RPCEndpoint)
+	for i, acc := range accounts {
+		if i > 0 {
+			fmt.Println()
+		}
+		printText(acc)
+	}
+}
+
+// derive 推导PDA并在标准错误输出推导结果
+func derive(program string, bump int, seeds seedFlag) solana.PublicKey {
+	programID, err := solana.PublicKeyFromBase58(program)
+	if err != nil {
+		log.Fatalf("无效的程序ID %s: %v", program, err)
+	}
+	if bump >= 0 {
+		if bump > 255 {
+			log.Fatalf("bump必须在0~255之间: %d", bump)
+		}
+		addr, err := account.CreatePDA(programID, uint8(bump), seeds...)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Fprintf(os.Stderr, "PDA: %s (bump %d)\n", addr, bump)
+		return addr
+	}
+	pda, err := account.FindPDA(programID, seeds...)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fmt.Fprintf(os.Stderr, "PDA: %s\n", pda)
+	return pda.Address
+}
+
+// fillDecimals 代币账户的精度记录在mint中，补查mint后才能显示带小数的余额
+func fillDecimals(ctx context.Context, rpcClient *rpc.Client, accounts []*account.Account) {
+	for _, acc := range accounts {
+		ta, ok := acc.Value.(*utils.TokenAccount)
+		if !ok {
+			continue
+		}
+		mint, err := utils.GetMintInfo(ctx, rpcClient, ta.Mint)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "读取 %s 的mint失败，余额按最小单位显示: %v\n", acc.Address, err)
+			continue
+		}
+		ta.Decimals = mint.Decimals
+		fmt.Fprintf(os.Stderr, "%s 余额: %s\n", acc.Address, ta.UIAmount())
+	}
+}
+
+func printText(acc *account.Account) {
+	fmt.Println(acc.Address)
+	if !acc.Exists {
+		fmt.Println("  账户不存在")
+		return
+	}
+	fmt.Printf("  owner: %s\n", acc.Owner)
+	fmt.Printf("  余额: %s SOL\n", utils.FormatTokenAmount(acc.Lamports, 9))
+	fmt.Printf("  数据: %d 字节\n", len(acc.Data))
+	if acc.Executable {
+		fmt.Println("  可执行（程序账户）")
+	}
+	switch {
+	case len(acc.Data) == 0:
+	case acc.DecodeErr != nil:
+		fmt.Printf("  %v\n", acc.DecodeErr)
+		fmt.Printf("  开头 %d 字节: %s\n", min(len(acc.Data), 8), hex.EncodeToString(acc.Data[:min(len(acc.Data), 8)]))
+	default:
+		fmt.Printf("  格式: %s\n", acc.Layout)
+		out, err := json.MarshalIndent(acc.Value, "  ", "  ")
+		if err != nil {
+			log.Fatalf("格式化 %s 失败: %v", acc.Address, err)
+		}
+		fmt.Printf("  %s\n", out)
+	}
+}
+
+// accountJSON JSON输出的一个账户
+type accountJSON struct {
+	Address    solana.PublicKey `json:"address"`
+	Exists     bool             `json:"exists"`
+	Owner      string           `json:"owner,omitempty"`
+	Lamports   uint64           `json:"lamports"`
+	Executable bool             `json:"executable,omitempty"`
+	DataLen    int              `json:"dataLen"`
+	Layout     string           `json:"layout,omitempty"`
+	Value      any              `json:"value,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+func printJSON(accounts []*account.Account) {
+	list := make([]accountJSON, len(accounts))
+	for i, acc := range accounts {
+		list[i] = accountJSON{
+			Address:    acc.Address,
+			Exists:     acc.Exists,
+			Lamports:   acc.Lamports,
+			Executable: acc.Executable,
+			DataLen:    len(acc.Data),
+			Layout:     acc.Layout,
+			Value:      acc.Value,
+		}
+		if acc.Exists {
+			list[i].Owner = acc.Owner.String()
+		}
+		if acc.DecodeErr != nil {
+			list[i].Error = acc.DecodeErr.Error()
+		}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(list); err != nil {
+		log.Fatalf("输出JSON失败: %v", err)
+	}
+}
+
+// seedFlag 可重复的-seed参数
+type seedFlag []account.Seed
+
+func (s *seedFlag) String() string {
+	parts := make([]string, len(*s))
+	for i, seed := range *s {
+		parts[i] = hex.EncodeToString(seed)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (s *seedFlag) Set(v string) error {
+	seed, err := account.ParseSeed(v)
+	if err != nil {
+		return err
+	}
+	*s = append(*s, seed)
+	return nil
+}
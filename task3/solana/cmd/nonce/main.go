@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+
+	"solana-go/client/nonce"
+	"solana-go/config"
+	"solana-go/utils"
+	"solana-go/wallet"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// Durable nonce账户管理和离线签名。付款账户取自配置，nonce授权账户默认与付款账户相同。
+//
+//	go run ./cmd/nonce create                                     # 创建nonce账户
+//	go run ./cmd/nonce show -account <nonce账户>                   # 查看当前nonce值
+//	go run ./cmd/nonce advance -account <nonce账户>                # 推进nonce，作废已签名未广播的交易
+//	go run ./cmd/nonce withdraw -account <nonce账户> -to <地址> -amount 0.001
+//	go run ./cmd/nonce authorize -account <nonce账户> -new-authority <地址>
+//
+// 离线签名、稍后广播：
+//
+//	go run ./cmd/nonce show -account <nonce账户>                                      # 在线：记下nonce值和授权账户
+//	go run ./cmd/nonce sign-transfer -account <nonce账户> -nonce <nonce值> \
+//	    -authority <授权账户> -to <地址> -amount 0.1 -out tx.b64                        # 离线：不访问网络
+//	go run ./cmd/nonce broadcast -in tx.b64                                            # 在线：任意时间广播
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	configPath := fs.String("config", "etc/config.yaml", "配置文件路径")
+	network := fs.String("network", "", "覆盖配置中的网络: devnet/testnet/mainnet/localnet")
+	confirmMainnet := fs.Bool("confirm-mainnet", false, "确认在主网上发送交易（会使用真实资金）")
+	authorityKeypair := fs.String("authority-keypair", "", "nonce授权账户的密钥文件，默认使用付款账户")
+	account := fs.String("account", "", "nonce账户地址")
+
+	var (
+		to, amount, newAuthority, nonceValue, authority, in, out *string
+	)
+	switch cmd {
+	case "create":
+		authority = fs.String("authority", "", "nonce授权账户地址，默认为-authority-keypair或付款账户")
+	case "show", "advance":
+	case "withdraw":
+		to = fs.String("to", "", "接收地址，默认付款账户")
+		amount = fs.String("amount", "", "提取的SOL数量，all表示全部提取并关闭账户")
+	case "authorize":
+		newAuthority = fs.String("new-authority", "", "新的授权账户地址")
+	case "sign-transfer":
+		to = fs.String("to", "", "收款地址")
+		amount = fs.String("amount", "", "转账的SOL数量")
+		nonceValue = fs.String("nonce", "", "nonce值（在线时用show查询）；为空时在线读取")
+		authority = fs.String("authority", "", "nonce授权账户地址，默认为签名用的授权账户")
+		out = fs.String("out", "", "签名后的交易（base64）写入的文件，默认标准输出")
+	case "broadcast":
+		in = fs.String("in", "", "base64交易文件，-表示标准输入")
+	default:
+		usage()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath, *network)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	cfg.ConfirmMainnet = cfg.ConfirmMainnet || *confirmMainnet
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// 离线签名不连接节点
+	if cmd == "sign-transfer" {
+		signTransfer(ctx, cfg, *account, *nonceValue, *authority, *authorityKeypair, *to, *amount, *out)
+		return
+	}
+
+	rpcClient := rpc.New(cfg.RPCEndpoint)
+	fmt.Fprintf(os.Stderr, "网络: %s, RPC: %s\n", cfg.Network, cfg.RPCEndpoint)
+	if cmd == "show" {
+		acc, err := nonce.Fetch(ctx, rpcClient, mustAddress("-account", *account))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Println(acc)
+		return
+	}
+
+	if err := cfg.CheckMainnet(ctx); err != nil {
+		log.Fatalf("%v", err)
+	}
+	wsClient, err := ws.Connect(ctx, cfg.WSEndpoint)
+	if err != nil {
+		log.Fatalf("WebSocket连接失败: %v", err)
+	}
+	defer wsClient.Close()
+
+	if cmd == "broadcast" {
+		tx, err := readTransaction(*in)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		result, err := nonce.Broadcast(ctx, rpcClient, wsClient, tx, nonce.BroadcastOptions{
+			Commitment: cfg.CommitmentType(),
+			OnAttempt: func(a utils.SendAttempt) {
+				log.Println(a)
+			},
+		})
+		if err != nil {
+			log.Fatalf("广播失败: %v", err)
+		}
+		fmt.Printf("交易已确认: %s (slot %d, %s)\n", result.Signature, result.Slot, result.Status)
+		return
+	}
+
+	payer, err := cfg.Payer()
+	if err != nil {
+		log.Fatalf("读取钱包失败: %v", err)
+	}
+	authorityKey := loadAuthority(cfg, payer, *authorityKeypair)
+	switch cmd {
+	case "create":
+		owner := authorityKey.PublicKey()
+		if *authority != "" {
+			owner = mustAddress("-authority", *authority)
+		}
+		acc, err := nonce.Create(ctx, rpcClient, wsClient, payer, owner)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Println(acc)
+	case "advance":
+		acc, err := nonce.Advance(ctx, rpcClient, wsClient, payer, authorityKey, mustAddress("-account", *account))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Println(acc)
+	case "withdraw":
+		address := mustAddress("-account", *account)
+		recipient := payer.PublicKey()
+		if *to != "" {
+			recipient = mustAddress("-to", *to)
+		}
+		var lamports uint64
+		if *amount == "all" {
+			acc, err := nonce.Fetch(ctx, rpcClient, address)
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+			lamports = acc.Lamports
+		} else {
+			lamports = mustSOL(*amount)
+		}
+		if err := nonce.Withdraw(ctx, rpcClient, wsClient, payer, authorityKey, address, recipient, lamports); err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("已提取 %s SOL 到 %s\n", utils.FormatTokenAmount(lamports, 9), recipient)
+	case "authorize":
+		acc, err := nonce.Authorize(ctx, rpcClient, wsClient, payer, authorityKey,
+			mustAddress("-account", *account), mustAddress("-new-authority", *newAuthority))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Println(acc)
+	}
+}
+
+// signTransfer 用nonce构造并签名一笔SOL转账，输出base64。给出-nonce时不访问网络
+func signTransfer(ctx context.Context, cfg *config.Config, account, nonceValue, authority, authorityKeypair, to, amount, out string) {
+	payer, err := cfg.Payer()
+	if err != nil {
+		log.Fatalf("读取钱包失败: %v", err)
+	}
+	authorityKey := loadAuthority(cfg, payer, authorityKeypair)
+
+	acc := &nonce.Account{Address: mustAddress("-account", account), Authority: authorityKey.PublicKey()}
+	if authority != "" {
+		acc.Authority = mustAddress("-authority", authority)
+	}
+	if nonceValue != "" {
+		if acc.Nonce, err = solana.HashFromBase58(nonceValue); err != nil {
+			log.Fatalf("无效的nonce值 %s: %v", nonceValue, err)
+		}
+	} else {
+		fetched, err := nonce.Fetch(ctx, rpc.New(cfg.RPCEndpoint), acc.Address)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		acc = fetched
+	}
+	if !acc.Authority.Equals(authorityKey.PublicKey()) {
+		log.Fatalf("nonce授权账户为 %s，但签名密钥是 %s，用-authority-keypair指定授权账户的密钥", acc.Authority, authorityKey.PublicKey())
+	}
+
+	lamports := mustSOL(amount)
+	transfer := system.NewTransferInstruction(lamports, payer.PublicKey(), mustAddress("-to", to)).Build()
+	tx, err := nonce.NewTransaction([]solana.Instruction{transfer}, acc, payer.PublicKey())
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := nonce.Sign(tx, payer, authorityKey); err != nil {
+		log.Fatalf("%v", err)
+	}
+	encoded, err := utils.EncodeTransaction(tx)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fmt.Fprintf(os.Stderr, "已签名: 转账 %s SOL 到 %s，签名 %s，nonce %s\n", amount, to, tx.Signatures[0], acc.Nonce)
+	if out == "" {
+		fmt.Println(encoded)
+		return
+	}
+	if err := os.WriteFile(out, []byte(encoded+"\n"), 0o600); err != nil {
+		log.Fatalf("写入交易文件失败: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "已写入 %s，用 broadcast -in %s 广播\n", out, out)
+}
+
+// loadAuthority 读取nonce授权账户的密钥，未指定时使用付款账户
+func loadAuthority(cfg *config.Config, payer solana.PrivateKey, path string) solana.PrivateKey {
+	if path == "" {
+		return payer
+	}
+	key, err := wallet.Load(path, cfg.Passphrase)
+	if err != nil {
+		log.Fatalf("读取授权账户密钥失败: %v", err)
+	}
+	return key
+}
+
+func readTransaction(path string) (*solana.Transaction, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch path {
+	case "":
+		return nil, fmt.Errorf("需要用-in指定交易文件")
+	case "-":
+		data, err = io.ReadAll(os.Stdin)
+	default:
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取交易失败: %v", err)
+	}
+	return utils.DecodeTransaction(string(data))
+}
+
+func mustAddress(flagName, s string) solana.PublicKey {
+	if s == "" {
+		log.Fatalf("需要指定%s", flagName)
+	}
+	addr, err := solana.PublicKeyFromBase58(s)
+	if err != nil {
+		log.Fatalf("%s 不是有效的地址 %s: %v", flagName, s, err)
+	}
+	return addr
+}
+
+func mustSOL(s string) uint64 {
+	if s == "" {
+		log.Fatalf("需要用-amount指定数量")
+	}
+	lamports, err := utils.ParseTokenAmount(s, 9)
+	if err != nil {
+		log.Fatalf("无效的数量 %s: %v", s, err)
+	}
+	return lamports
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: nonce <create|show|advance|withdraw|authorize|sign-transfer|broadcast> [参数]，各子命令的参数见 -h")
+	os.Exit(2)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"solana-go/config"
+	"solana-go/watcher"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// 订阅监听：对配置（watch段）或命令行指定的地址建立account/program/logs订阅，
+// 把解码后的事件输出到stdout、文件或webhook，断线后自动重连并补齐。Ctrl+C退出时打印事件统计。
+//
+//	go run ./cmd/watch
+//	go run ./cmd/watch -account <地址> -logs <地址> -sink stdout,file:events.jsonl
+//	go run ./cmd/watch -program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA -sink https://example.com/hook
+func main() {
+	configPath := flag.String("config", "etc/config.yaml", "配置文件路径")
+	network := flag.String("network", "", "覆盖配置中的网络: devnet/testnet/mainnet/localnet")
+	accounts := flag.String("account", "", "监听的账户地址，逗号分隔，覆盖配置中的watch.accounts")
+	programs := flag.String("program", "", "监听的程序地址，逗号分隔，覆盖配置中的watch.programs")
+	logs := flag.String("logs", "", "监听交易日志的地址，逗号分隔，覆盖配置中的watch.logs")
+	sinks := flag.String("sink", "", "事件输出，逗号分隔：stdout、file:<路径>、http(s)://地址，覆盖配置中的watch.sinks")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath, *network)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	override(&cfg.Watch.Accounts, *accounts)
+	override(&cfg.Watch.Programs, *programs)
+	override(&cfg.Watch.Logs, *logs)
+	override(&cfg.Watch.Sinks, *sinks)
+	if len(cfg.Watch.Accounts)+len(cfg.Watch.Programs)+len(cfg.Watch.Logs) == 0 {
+		payer, err := cfg.Payer()
+		if err != nil {
+			log.Fatalf("没有配置监听目标，读取付款账户失败: %v", err)
+		}
+		cfg.Watch.Accounts = []string{payer.PublicKey().String()}
+	}
+	if len(cfg.Watch.Sinks) == 0 {
+		cfg.Watch.Sinks = []string{"stdout"}
+	}
+
+	var targets []watcher.Target
+	for kind, addresses := range map[watcher.Kind][]string{
+		watcher.KindAccount: cfg.Watch.Accounts,
+		watcher.KindProgram: cfg.Watch.Programs,
+		watcher.KindLogs:    cfg.Watch.Logs,
+	} {
+		t, err := watcher.ParseTargets(kind, addresses)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		targets = append(targets, t...)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].String() < targets[j].String() })
+	opts := watcher.Options{Commitment: cfg.CommitmentType()}
+	for _, spec := range cfg.Watch.Sinks {
+		sink, err := watcher.ParseSink(spec)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		opts.Sinks = append(opts.Sinks, sink)
+	}
+	w, err := watcher.New(rpc.New(cfg.RPCEndpoint), cfg.WSEndpoint, targets, opts)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "网络: %s, WS: %s\n", cfg.Network, cfg.WSEndpoint)
+	for _, t := range targets {
+		fmt.Fprintf(os.Stderr, "订阅 %s\n", t)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Run返回后关闭channel，统计协程读完剩余事件后退出
+	events := make(chan watcher.Event, 64)
+	counts := make(map[watcher.EventType]int)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range events {
+			counts[ev.Type]++
+		}
+	}()
+	err = w.Run(ctx, events)
+	close(events)
+	<-done
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("监听失败: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "\n已退出，事件统计: 账户 %d，日志 %d，重连 %d\n",
+		counts[watcher.EventAccount], counts[watcher.EventLogs], counts[watcher.EventReconnect])
+}
+
+// override 命令行参数不为空时覆盖配置中的列表
+func override(list *[]string, flagValue string) {
+	if flagValue == "" {
+		return
+	}
+	*list = nil
+	for _, s := range strings.Split(flagValue, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*list = append(*list, s)
+		}
+	}
+}
@@ -1,21 +1,71 @@
 package config
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"solana-go/utils"
+	"solana-go/wallet"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"gopkg.in/yaml.v3"
+)
+
 const (
-	DevNetRPC  = "https://api.devnet.solana.com"
-	DevNetWS   = "wss://api.devnet.solana.com"
-	MainNetRPC = "https://api.mainnet-beta.solana.com"
-	MainNetWS  = "wss://api.mainnet-beta.solana.com"
-	TestNetRPC = "https://api.testnet.solana.com"
-	TestNetWS  = "wss://api.testnet.solana.com"
+	DevNetRPC   = "https://api.devnet.solana.com"
+	DevNetWS    = "wss://api.devnet.solana.com"
+	MainNetRPC  = "https://api.mainnet-beta.solana.com"
+	MainNetWS   = "wss://api.mainnet-beta.solana.com"
+	TestNetRPC  = "https://api.testnet.solana.com"
+	TestNetWS   = "wss://api.testnet.solana.com"
+	LocalNetRPC = "http://127.0.0.1:8899" // solana-test-validator默认端口
+	LocalNetWS  = "ws://127.0.0.1:8900"
 )
 
+// 环境变量，优先级高于配置文件
+const (
+	EnvNetwork        = "SOLANA_NETWORK"
+	EnvRPCEndpoint    = "SOLANA_RPC_URL"
+	EnvWSEndpoint     = "SOLANA_WS_URL"
+	EnvCommitment     = "SOLANA_COMMITMENT"
+	EnvKeypair        = "SOLANA_KEYPAIR"
+	EnvPrivateKey     = "SOLANA_PRIVATE_KEY"
+	EnvPassphrase     = "SOLANA_KEYPAIR_PASSPHRASE"
+	EnvConfirmMainnet = "SOLANA_CONFIRM_MAINNET"
+)
+
+// ErrMainnetNotConfirmed 在主网上操作但没有显式确认
+var ErrMainnetNotConfirmed = errors.New("主网上涉及真实资金的操作需要显式确认（confirm_mainnet: true、SOLANA_CONFIRM_MAINNET=true 或 -confirm-mainnet）")
+
 type Config struct {
-	Network     string
-	RPCEndpoint string
-	WSEndpoint  string
-	PrivateKey  string
+	Network          string `yaml:"network"` // devnet/testnet/mainnet/localnet
+	RPCEndpoint      string `yaml:"rpc_endpoint"`
+	WSEndpoint       string `yaml:"ws_endpoint"`
+	Commitment       string `yaml:"commitment"`        // processed/confirmed/finalized
+	Keypair          string `yaml:"keypair"`           // 付款账户的solana-keygen密钥文件
+	RecipientKeypair string `yaml:"recipient_keypair"` // 示例转账的收款账户密钥文件
+	PrivateKey       string `yaml:"private_key"`       // base58私钥，设置后优先于keypair文件
+	ConfirmMainnet   bool   `yaml:"confirm_mainnet"`   // 允许在主网上发送交易
+	Passphrase       string `yaml:"-"`                 // 加密密钥文件的口令，只从环境变量读取
+
+	Localnet struct {
+		RPCPort int `yaml:"rpc_port"` // solana-test-validator的--rpc-port，WS端口为其+1
+	} `yaml:"localnet"`
+
+	// Watch 订阅监听（cmd/watch）的目标和输出
+	Watch struct {
+		Accounts []string `yaml:"accounts"` // accountSubscribe的账户地址
+		Programs []string `yaml:"programs"` // programSubscribe的程序地址
+		Logs     []string `yaml:"logs"`     // logsSubscribe提到的地址
+		Sinks    []string `yaml:"sinks"`    // stdout、file:<路径>、http(s)://webhook，默认stdout
+	} `yaml:"watch"`
 }
 
+// GetConfig 返回网络的默认节点地址
 func GetConfig(network string) Config {
 	switch network {
 	case "mainnet":
@@ -30,6 +80,12 @@ func GetConfig(network string) Config {
 			RPCEndpoint: TestNetRPC,
 			WSEndpoint:  TestNetWS,
 		}
+	case "localnet":
+		return Config{
+			Network:     "localnet",
+			RPCEndpoint: LocalNetRPC,
+			WSEndpoint:  LocalNetWS,
+		}
 	default: // devnet
 		return Config{
 			Network:     "devnet",
@@ -38,3 +94,167 @@ func GetConfig(network string) Config {
 		}
 	}
 }
+
+// Load 读取YAML配置，再依次用环境变量和network参数（命令行）覆盖，未填写的节点地址按网络取默认值。
+// path为空或文件不存在时只使用环境变量和默认值
+func Load(path, network string) (*Config, error) {
+	c := &Config{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("加载配置文件失败: %w", err)
+		default:
+			if err := yaml.Unmarshal(data, c); err != nil {
+				return nil, fmt.Errorf("解析配置文件失败: %w", err)
+			}
+		}
+	}
+	fileNetwork := c.Network
+	if err := c.applyEnv(); err != nil {
+		return nil, err
+	}
+	if networkName(c.Network) != networkName(fileNetwork) {
+		// SOLANA_NETWORK切换了网络，配置文件中为原网络填写的节点地址不再适用，环境变量给出的地址保留
+		if !envSet(EnvRPCEndpoint) {
+			c.RPCEndpoint = ""
+		}
+		if !envSet(EnvWSEndpoint) {
+			c.WSEndpoint = ""
+		}
+	}
+	if network != "" && networkName(network) != networkName(c.Network) {
+		// 切换网络时不能沿用为原网络配置的节点地址
+		c.Network, c.RPCEndpoint, c.WSEndpoint = network, "", ""
+	}
+	if err := c.applyDefaults(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// networkName 统一网络名称，未填写时为devnet
+func networkName(network string) string {
+	switch network {
+	case "":
+		return "devnet"
+	case "mainnet-beta":
+		return "mainnet"
+	}
+	return network
+}
+
+func envSet(env string) bool {
+	v, ok := os.LookupEnv(env)
+	return ok && v != ""
+}
+
+func (c *Config) applyEnv() error {
+	for env, field := range map[string]*string{
+		EnvNetwork:     &c.Network,
+		EnvRPCEndpoint: &c.RPCEndpoint,
+		EnvWSEndpoint:  &c.WSEndpoint,
+		EnvCommitment:  &c.Commitment,
+		EnvKeypair:     &c.Keypair,
+		EnvPrivateKey:  &c.PrivateKey,
+		EnvPassphrase:  &c.Passphrase,
+	} {
+		if envSet(env) {
+			*field = os.Getenv(env)
+		}
+	}
+	if v, ok := os.LookupEnv(EnvConfirmMainnet); ok && v != "" {
+		confirm, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("环境变量 %s 的值无效: %q", EnvConfirmMainnet, v)
+		}
+		c.ConfirmMainnet = confirm
+	}
+	return nil
+}
+
+func (c *Config) applyDefaults() error {
+	switch c.Network {
+	case "":
+		c.Network = "devnet"
+	case "devnet", "testnet", "mainnet", "localnet":
+	case "mainnet-beta":
+		c.Network = "mainnet"
+	default:
+		return fmt.Errorf("未知的网络: %s（可选 devnet/testnet/mainnet/localnet）", c.Network)
+	}
+	preset := GetConfig(c.Network)
+	if c.Network == "localnet" && c.Localnet.RPCPort > 0 {
+		preset.RPCEndpoint = fmt.Sprintf("http://127.0.0.1:%d", c.Localnet.RPCPort)
+		preset.WSEndpoint = fmt.Sprintf("ws://127.0.0.1:%d", c.Localnet.RPCPort+1)
+	}
+	if c.RPCEndpoint == "" {
+		c.RPCEndpoint = preset.RPCEndpoint
+	}
+	if c.WSEndpoint == "" {
+		c.WSEndpoint = preset.WSEndpoint
+	}
+
+	switch rpc.CommitmentType(c.Commitment) {
+	case "":
+		c.Commitment = string(rpc.CommitmentConfirmed)
+	case rpc.CommitmentProcessed, rpc.CommitmentConfirmed, rpc.CommitmentFinalized:
+	default:
+		return fmt.Errorf("未知的承诺级别: %s（可选 processed/confirmed/finalized）", c.Commitment)
+	}
+	if c.Keypair == "" && c.PrivateKey == "" {
+		c.Keypair = "wallet-keypair.json"
+	}
+	if c.RecipientKeypair == "" {
+		c.RecipientKeypair = "wallet-keypair2.json"
+	}
+	return nil
+}
+
+// IsMainnet 是否为主网
+func (c *Config) IsMainnet() bool {
+	return c.Network == "mainnet"
+}
+
+// CheckMainnet 发送交易前调用，主网上没有显式确认时返回ErrMainnetNotConfirmed。
+// 除了配置的网络，还会比较rpc_endpoint的创世哈希，自定义的主网节点配在其他网络下同样需要确认；
+// 读不到创世哈希时无法确认节点所属网络，也需要确认
+func (c *Config) CheckMainnet(ctx context.Context) error {
+	if c.ConfirmMainnet {
+		return nil
+	}
+	if c.IsMainnet() {
+		return ErrMainnetNotConfirmed
+	}
+	genesis, err := rpc.New(c.RPCEndpoint).GetGenesisHash(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: 无法读取节点 %s 的创世哈希: %v", ErrMainnetNotConfirmed, c.RPCEndpoint, utils.ClassifyError(err))
+	}
+	if genesis.Equals(utils.MainnetGenesisHash) {
+		return fmt.Errorf("%w: 节点 %s 的创世哈希属于主网（配置的网络为 %s）", ErrMainnetNotConfirmed, c.RPCEndpoint, c.Network)
+	}
+	return nil
+}
+
+// CommitmentType 配置的承诺级别
+func (c *Config) CommitmentType() rpc.CommitmentType {
+	return rpc.CommitmentType(c.Commitment)
+}
+
+// Payer 读取付款账户私钥：优先使用private_key（base58、字节数组JSON或助记词），
+// 否则读取keypair文件，加密的密钥文件用SOLANA_KEYPAIR_PASSPHRASE解密
+func (c *Config) Payer() (solana.PrivateKey, error) {
+	if c.PrivateKey != "" {
+		key, err := wallet.Import(c.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("解析private_key失败: %w", err)
+		}
+		return key, nil
+	}
+	key, err := wallet.Load(c.Keypair, c.Passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件 %s 失败: %w", c.Keypair, err)
+	}
+	return key, nil
+}
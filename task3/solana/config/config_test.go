@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"solana-go/mockrpc"
+	"solana-go/utils"
+)
+
+const customMainnetRPC = "https://mainnet.example.com"
+
+// writeConfig 写入临时配置文件并清空会影响结果的环境变量
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	for _, env := range []string{EnvNetwork, EnvRPCEndpoint, EnvWSEndpoint, EnvConfirmMainnet} {
+		t.Setenv(env, "")
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadNetworkSwitch(t *testing.T) {
+	mainnetFile := "network: mainnet\nrpc_endpoint: " + customMainnetRPC + "\nws_endpoint: wss://mainnet.example.com\n"
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		network string
+		wantNet string
+		wantRPC string
+		wantWS  string
+	}{
+		{name: "沿用配置文件", file: mainnetFile, wantNet: "mainnet", wantRPC: customMainnetRPC, wantWS: "wss://mainnet.example.com"},
+		{name: "命令行切换网络", file: mainnetFile, network: "devnet", wantNet: "devnet", wantRPC: DevNetRPC, wantWS: DevNetWS},
+		{name: "环境变量切换网络", file: mainnetFile, env: map[string]string{EnvNetwork: "devnet"}, wantNet: "devnet", wantRPC: DevNetRPC, wantWS: DevNetWS},
+		{name: "环境变量切换网络并给出节点", file: mainnetFile, env: map[string]string{EnvNetwork: "testnet", EnvRPCEndpoint: "https://testnet.example.com"}, wantNet: "testnet", wantRPC: "https://testnet.example.com", wantWS: TestNetWS},
+		{name: "环境变量与配置文件相同", file: mainnetFile, env: map[string]string{EnvNetwork: "mainnet-beta"}, wantNet: "mainnet", wantRPC: customMainnetRPC, wantWS: "wss://mainnet.example.com"},
+		{name: "未填写网络按devnet", file: "rpc_endpoint: https://devnet.example.com\n", env: map[string]string{EnvNetwork: "devnet"}, wantNet: "devnet", wantRPC: "https://devnet.example.com", wantWS: DevNetWS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.file)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			c, err := Load(path, tt.network)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Network != tt.wantNet || c.RPCEndpoint != tt.wantRPC || c.WSEndpoint != tt.wantWS {
+				t.Fatalf("得到 %s %s %s，期望 %s %s %s", c.Network, c.RPCEndpoint, c.WSEndpoint, tt.wantNet, tt.wantRPC, tt.wantWS)
+			}
+		})
+	}
+}
+
+func TestCheckMainnet(t *testing.T) {
+	ctx := context.Background()
+	devnetNode := mockrpc.NewServer(mockrpc.Options{})
+	defer devnetNode.Close()
+	mainnetNode := mockrpc.NewServer(mockrpc.Options{GenesisHash: utils.MainnetGenesisHash})
+	defer mainnetNode.Close()
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{name: "devnet节点", config: Config{Network: "devnet", RPCEndpoint: devnetNode.URL}},
+		{name: "配置为主网", config: Config{Network: "mainnet", RPCEndpoint: devnetNode.URL}, wantErr: true},
+		{name: "配置为主网且已确认", config: Config{Network: "mainnet", RPCEndpoint: mainnetNode.URL, ConfirmMainnet: true}},
+		{name: "devnet下配置了主网节点", config: Config{Network: "devnet", RPCEndpoint: mainnetNode.URL}, wantErr: true},
+		{name: "无法读取创世哈希", config: Config{Network: "localnet", RPCEndpoint: "http://127.0.0.1:1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.CheckMainnet(ctx)
+			if tt.wantErr != errors.Is(err, ErrMainnetNotConfirmed) {
+				t.Fatalf("err = %v", err)
+			}
+		})
+	}
+}
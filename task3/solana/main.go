@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"solana-go/config"
 	"solana-go/utils"
 	"time"
 
@@ -13,26 +15,35 @@ import (
 )
 
 func main() {
+	configPath := flag.String("config", "etc/config.yaml", "配置文件路径")
+	network := flag.String("network", "", "覆盖配置中的网络: devnet/testnet/mainnet/localnet")
+	confirmMainnet := flag.Bool("confirm-mainnet", false, "确认在主网上发送交易（会使用真实资金）")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath, *network)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	cfg.ConfirmMainnet = cfg.ConfirmMainnet || *confirmMainnet
+	fmt.Printf("网络: %s, RPC: %s, 承诺级别: %s\n", cfg.Network, cfg.RPCEndpoint, cfg.Commitment)
+
 	// 初始化RPC客户端
-	rpcClient := rpc.New(rpc.DevNet_RPC)
+	rpcClient := rpc.New(cfg.RPCEndpoint)
 	// 4. 监听交易事件
-	wsClient, err := ws.Connect(context.Background(), rpc.DevNet_WS)
+	wsClient, err := ws.Connect(context.Background(), cfg.WSEndpoint)
 	if err != nil {
 		log.Fatalf("WebSocket连接失败: %v", err)
 	}
 	defer wsClient.Close()
-	// 1. 获取最新区块
-	recentBlockhash, lastValidBlockHeight, err := utils.GetRecentBlockhash(rpcClient)
+
+	fromWallet, err := cfg.Payer()
 	if err != nil {
-		log.Fatalf("获取最新区块失败: %v", err)
+		log.Fatalf("读取钱包失败: %v", err)
+	}
+	toWallet2, err := utils.GetAccountFromPrivateKey(cfg.RecipientKeypair)
+	if err != nil {
+		log.Fatalf("读取钱包失败: %v", err)
 	}
-	fmt.Printf("最新区块哈希: %s\n", recentBlockhash)
-	fmt.Printf("最后有效区块高度: %d\n", lastValidBlockHeight)
-	// 3. 构造并发送转账交易
-
-	fromWallet := utils.GetAccountFromPrivateKey("wallet-keypair.json")
-	// 替换为实际私钥
-	toWallet2 := utils.GetAccountFromPrivateKey("wallet-keypair2.json")
 
 	fmt.Printf("Account 2: %s\n", toWallet2.PublicKey().String())
 	// 2. 查询账户余额
@@ -41,89 +52,97 @@ func main() {
 	if err != nil {
 		log.Fatalf("查询余额失败: %v", err)
 	}
-	fmt.Printf("账户余额: %f SOL\n", balance)
+	fmt.Printf("账户余额: %f SOL\n", float64(balance.Value)/float64(solana.LAMPORTS_PER_SOL))
+	// 列出持有的SPL代币
+	tokenAccounts, err := utils.GetTokenAccounts(context.TODO(), rpcClient, walletAddress)
+	if err != nil {
+		log.Fatalf("查询代币账户失败: %v", err)
+	}
+	for _, acc := range tokenAccounts {
+		fmt.Printf("代币 %s: %s (账户 %s)\n", acc.Mint, acc.UIAmount(), acc.Address)
+	}
 
 	// 替换为实际私钥
 	//toWallet := solana.MustPublicKeyFromBase58("Dukdx2R3wMvnAvriU5HnLDFjqgjHDKCqLnux2opC85PT") // 替换为实际地址
 	// 替换为实际地址
 
 	amount := uint64(1000000) // 0.001 SOL
-	// 请求空投（确保账户有足够余额）
-	airdropSig, err := rpcClient.RequestAirdrop(
-		context.TODO(),
-		fromWallet.PublicKey(),
-		solana.LAMPORTS_PER_SOL*1, // 1 SOL
-		rpc.CommitmentConfirmed,   // 使用 confirmed 承诺级别
-	)
-	if err != nil {
-		log.Fatalf("Airdrop failed: %v", err)
+	// 主网上转账使用真实资金，必须显式确认
+	if err := cfg.CheckMainnet(context.TODO()); err != nil {
+		log.Fatalf("%v", err)
+	}
+	// 余额不足1 SOL时请求空投，等空投确认且余额到账后再转账；主网没有水龙头
+	if !cfg.IsMainnet() {
+		airdrop, err := utils.AirdropTo(context.TODO(), rpcClient, wsClient, fromWallet.PublicKey(), solana.LAMPORTS_PER_SOL, utils.AirdropOptions{
+			Network:    cfg.Network,
+			Commitment: cfg.CommitmentType(),
+			OnProgress: func(p utils.AirdropProgress) { fmt.Println(p) },
+		})
+		if err != nil {
+			log.Fatalf("空投失败: %v", err)
+		}
+		fmt.Printf("当前余额: %s SOL（本次空投 %s SOL）\n", utils.FormatTokenAmount(airdrop.Balance, 9), utils.FormatTokenAmount(airdrop.Requested, 9))
 	}
-	fmt.Printf("Airdrop transaction signature: %s\n", airdropSig)
-	// 等待空投确认（可选，但建议等待）
-	time.Sleep(5 * time.Second)
 	//Solana 网络上的每笔交易都必须包含一个最近的区块哈希，它就像一个时间戳，用来确保交易的新鲜度。
 	//Solana 的区块哈希有效期很短，通常只有 60-90 秒。如果你的交易在获取区块哈希后没有及时发送并被打包，区块哈希就会因过期而被移出验证节点的队列，从而导致此错误。
 	//RPC 节点状态不同步：如果你从一个 RPC 节点获取了最新的区块哈希，但将交易发送到另一个 RPC 节点，而该节点尚未同步到最新的区块状态，它就会无法识别你交易中的区块哈希，从而报错
-	// 获取最新区块哈希和有效高度
-	recent, err := rpcClient.GetLatestBlockhash(
-		context.TODO(),
-		rpc.CommitmentConfirmed, // 使用 confirmed 承诺级别
-	)
+	// 获取最新区块哈希和有效高度，发送时超过有效高度会自动换新的区块哈希重新签名
+	blockhash, lastValidHeight, err := utils.GetRecentBlockhash(rpcClient, cfg.CommitmentType())
 	if err != nil {
-		log.Fatalf("Failed to get recent blockhash: %v", err)
-	}
-	blockhash := recent.Value.Blockhash
-	lastlockHeight := recent.Value.LastValidBlockHeight
-
-	// 检查当前区块高度是否超过有效高度
-	currentHeight, err := rpcClient.GetBlockHeight(context.TODO(), rpc.CommitmentConfirmed)
-	if err != nil {
-		log.Fatalf("Failed to get current block height: %v", err)
-	}
-	if currentHeight > lastlockHeight {
-		log.Fatalf("Blockhash expired. Current height: %d, Last valid height: %d", currentHeight, lastlockHeight)
+		log.Fatalf("获取最新区块失败: %v", err)
 	}
-	signature, err := utils.TransferSOL(rpcClient, wsClient, fromWallet, toWallet2.PublicKey(), amount, blockhash)
+	fmt.Printf("最新区块哈希: %s，最后有效区块高度: %d\n", blockhash, lastValidHeight)
+	signature, err := utils.TransferSOL(rpcClient, wsClient, fromWallet, toWallet2.PublicKey(), amount, blockhash, lastValidHeight, utils.SendOptions{
+		Commitment:   cfg.CommitmentType(),
+		OnSimulation: func(r *utils.SimulationReport) { log.Println(r) },
+		OnAttempt:    func(a utils.SendAttempt) { log.Println(a) },
+	})
 	if err != nil {
 		log.Fatalf("转账失败: %v", err)
 	}
 	fmt.Printf("转账成功! 交易签名: %s\n", signature)
 
-	sub, err := wsClient.SignatureSubscribe(
-		signature,
-		"",
-	)
+	// TransferSOL返回时已达到配置的承诺级别，这里继续等待最终确认
+	result, err := utils.WaitForConfirmation(context.TODO(), rpcClient, wsClient, signature, rpc.CommitmentFinalized, 60*time.Second)
 	if err != nil {
-		log.Fatalf("订阅交易事件失败: %v", err)
+		log.Fatalf("等待交易确认失败: %v", err)
 	}
-	defer sub.Unsubscribe()
-
-	go func() {
-		for {
-			select {
-			case <-sub.Response():
-				fmt.Println("交易已确认!")
-				return
-			case <-time.After(30 * time.Second):
-				fmt.Println("交易确认超时")
-				return
-			}
-		}
-	}()
+	fmt.Printf("交易已最终确认! slot: %d, 状态: %s\n", result.Slot, result.Status)
 
 	// 5. 智能合约交互示例
-	//swapClient, err := tokenswap.NewTokenSwapClient(rpcClient, fromWallet)
+	//swapClient, err := tokenswap.NewTokenSwapClient(rpcClient, fromWallet, solana.MustPublicKeyFromBase58("PoolAddress"))
 	//if err != nil {
 	//	log.Fatalf("创建代币交换客户端失败: %v", err)
 	//}
 	//
+	//// 先报价，确认预期输出和价格影响
+	//_, q, err := swapClient.Quote(
+	//	context.Background(),
+	//	solana.MustPublicKeyFromBase58("InputTokenMint"),
+	//	solana.MustPublicKeyFromBase58("OutputTokenMint"),
+	//	uint64(1000000),
+	//)
+	//if err != nil {
+	//	log.Fatalf("报价失败: %v", err)
+	//}
+	//fmt.Println(q)
+	//
+	//// 兑换涉及的账户较多时，引用地址查找表构造v0交易（查找表可用lookuptable.Create创建）
+	//tables, err := lookuptable.Fetch(context.Background(), rpcClient, solana.MustPublicKeyFromBase58("LookupTableAddress"))
+	//if err != nil {
+	//	log.Fatalf("读取查找表失败: %v", err)
+	//}
+	//swapClient.SetLookupTables(tables)
+	//
 	//// 执行代币交换
 	//swapSignature, err := swapClient.SwapTokens(
 	//	context.Background(),
+	//	wsClient,
 	//	solana.MustPublicKeyFromBase58("InputTokenMint"),
 	//	solana.MustPublicKeyFromBase58("OutputTokenMint"),
 	//	uint64(1000000), // 输入金额
-	//	recentBlockhash,
+	//	blockhash,
+	//	lastValidHeight,
 	//)
 	//if err != nil {
 	//	log.Fatalf("代币交换失败: %v", err)
@@ -0,0 +1,198 @@
+package mockrpc
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// System程序指令的执行和durable nonce账户。nonce值直接取当前区块哈希，
+// 真实节点会再做一次哈希，对调用方没有区别
+
+const (
+	// NonceAccountSize nonce账户的数据长度
+	NonceAccountSize = 80
+	// 免租金的最低余额：(数据长度+128字节账户头) * 每字节的租金，与真实节点的默认参数一致
+	rentPerByte        = 6960
+	accountStorageSize = 128
+)
+
+// nonceState 已初始化的nonce账户
+type nonceState struct {
+	authority solana.PublicKey
+	nonce     solana.Hash
+}
+
+// encode 按nonce账户的链上格式编码：版本、状态、授权账户、nonce值、每签名手续费
+func (n *nonceState) encode() []byte {
+	data := make([]byte, NonceAccountSize)
+	binary.LittleEndian.PutUint32(data[0:], 1) // Versions::Current
+	binary.LittleEndian.PutUint32(data[4:], 1) // State::Initialized
+	copy(data[8:], n.authority[:])
+	copy(data[40:], n.nonce[:])
+	binary.LittleEndian.PutUint64(data[72:], LamportsPerSignature)
+	return data
+}
+
+func rentExempt(space uint64) uint64 {
+	return (space + accountStorageSize) * rentPerByte
+}
+
+// SetNonceAccount 直接创建一个已初始化的nonce账户，返回当前的nonce值
+func (s *Server) SetNonceAccount(account, authority solana.PublicKey) solana.Hash {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nonces[account] = &nonceState{authority: authority, nonce: s.latest}
+	if s.balances[account] == 0 {
+		s.balances[account] = rentExempt(NonceAccountSize)
+	}
+	return s.latest
+}
+
+// Nonce nonce账户当前的nonce值和授权账户，账户不存在或未初始化时ok为false
+func (s *Server) Nonce(account solana.PublicKey) (nonce solana.Hash, authority solana.PublicKey, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.nonces[account]
+	if !ok {
+		return solana.Hash{}, solana.PublicKey{}, false
+	}
+	return st.nonce, st.authority, true
+}
+
+// validBlockhash 交易的区块哈希仍在有效期内，或者是第一条指令推进的nonce账户中保存的nonce值
+func (s *Server) validBlockhash(tx *solana.Transaction) bool {
+	msg := tx.Message
+	if lastValid, ok := s.blockhashes[msg.RecentBlockhash]; ok && s.height <= lastValid {
+		return true
+	}
+	if len(msg.Instructions) == 0 {
+		return false
+	}
+	inst := msg.Instructions[0]
+	program, err := msg.Program(inst.ProgramIDIndex)
+	if err != nil || !program.Equals(solana.SystemProgramID) || len(inst.Data) < 4 ||
+		binary.LittleEndian.Uint32(inst.Data) != systemAdvanceNonce || len(inst.Accounts) == 0 {
+		return false
+	}
+	st, ok := s.nonces[msg.AccountKeys[inst.Accounts[0]]]
+	return ok && st.nonce == msg.RecentBlockhash
+}
+
+// executeSystem 执行一条System程序指令，结果写入ex；失败时返回指令错误和附加日志
+func (s *Server) executeSystem(
+	ex *execution,
+	msg solana.Message,
+	inst solana.CompiledInstruction,
+	balance func(solana.PublicKey) uint64,
+) (interface{}, []string) {
+	if len(inst.Data) < 4 {
+		return "InvalidInstructionData", nil
+	}
+	account := func(i int) solana.PublicKey {
+		if i >= len(inst.Accounts) {
+			return solana.PublicKey{}
+		}
+		return msg.AccountKeys[inst.Accounts[i]]
+	}
+	nonce := func(key solana.PublicKey) *nonceState {
+		if st, ok := ex.nonces[key]; ok {
+			return st
+		}
+		return s.nonces[key]
+	}
+	transfer := func(from, to solana.PublicKey, lamports uint64) (interface{}, []string) {
+		if balance(from) < lamports {
+			return map[string]interface{}{"Custom": 1}, []string{
+				fmt.Sprintf("Transfer: insufficient lamports %d, need %d", balance(from), lamports),
+			}
+		}
+		ex.balances[from] = balance(from) - lamports
+		ex.balances[to] = balance(to) + lamports
+		return nil, nil
+	}
+	// authorize nonce账户存在且授权账户签了名
+	authorize := func(key, authority solana.PublicKey) (*nonceState, interface{}) {
+		st := nonce(key)
+		if st == nil {
+			return nil, "InvalidAccountData"
+		}
+		if !st.authority.Equals(authority) || !msg.IsSigner(authority) {
+			return nil, "MissingRequiredSignature"
+		}
+		return st, nil
+	}
+
+	data := inst.Data[4:]
+	switch binary.LittleEndian.Uint32(inst.Data) {
+	case systemCreateAccount:
+		if len(data) < 8 || len(inst.Accounts) < 2 {
+			return "InvalidInstructionData", nil
+		}
+		if balance(account(1)) > 0 {
+			return map[string]interface{}{"Custom": 0}, []string{fmt.Sprintf("Create Account: account Address { address: %s, base: None } already in use", account(1))}
+		}
+		return transfer(account(0), account(1), binary.LittleEndian.Uint64(data))
+	case systemTransfer:
+		if len(data) < 8 || len(inst.Accounts) < 2 {
+			return "InvalidInstructionData", nil
+		}
+		return transfer(account(0), account(1), binary.LittleEndian.Uint64(data))
+	case systemInitNonce:
+		if len(data) < 32 || len(inst.Accounts) < 1 {
+			return "InvalidInstructionData", nil
+		}
+		if nonce(account(0)) != nil {
+			return "InvalidAccountData", nil
+		}
+		if balance(account(0)) < rentExempt(NonceAccountSize) {
+			return "InsufficientFunds", nil
+		}
+		ex.nonces[account(0)] = &nonceState{authority: solana.PublicKeyFromBytes(data[:32]), nonce: s.latest}
+	case systemAdvanceNonce:
+		st, txErr := authorize(account(0), account(2))
+		if txErr != nil {
+			return txErr, nil
+		}
+		ex.nonces[account(0)] = &nonceState{authority: st.authority, nonce: s.latest}
+	case systemWithdrawNonce:
+		if len(data) < 8 {
+			return "InvalidInstructionData", nil
+		}
+		if _, txErr := authorize(account(0), account(4)); txErr != nil {
+			return txErr, nil
+		}
+		lamports := binary.LittleEndian.Uint64(data)
+		remaining := balance(account(0)) - min(lamports, balance(account(0)))
+		if remaining != 0 && remaining < rentExempt(NonceAccountSize) {
+			return "InsufficientFunds", nil
+		}
+		if txErr, logs := transfer(account(0), account(1), lamports); txErr != nil {
+			return txErr, logs
+		}
+		if remaining == 0 {
+			ex.nonces[account(0)] = nil
+		}
+	case systemAuthorizeNonce:
+		if len(data) < 32 {
+			return "InvalidInstructionData", nil
+		}
+		st, txErr := authorize(account(0), account(1))
+		if txErr != nil {
+			return txErr, nil
+		}
+		ex.nonces[account(0)] = &nonceState{authority: solana.PublicKeyFromBytes(data[:32]), nonce: st.nonce}
+	}
+	return nil, nil
+}
+
+// failureMessage 程序失败日志中的错误描述
+func failureMessage(txErr interface{}) string {
+	if m, ok := txErr.(map[string]interface{}); ok {
+		if code, ok := m["Custom"].(int); ok {
+			return fmt.Sprintf("custom program error: 0x%x", code)
+		}
+	}
+	return fmt.Sprint(txErr)
+}
@@ -0,0 +1,444 @@
+package mockrpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/gorilla/websocket"
+)
+
+// 进程内的Solana JSON-RPC/WebSocket模拟节点，离线调试和测试utils时代替devnet。
+// 只实现utils用到的方法：getLatestBlockhash、getBlockHeight、getSlot、getBalance、getGenesisHash、
+// getAccountInfo、getMultipleAccounts、getMinimumBalanceForRentExemption、sendTransaction、simulateTransaction、requestAirdrop、getSignatureStatuses、
+// getSignaturesForAddress、getRecentPrioritizationFees，以及signatureSubscribe和logsSubscribe（mentions过滤）。
+// 链的推进完全由调用方控制（Advance或AutoAdvance），每个块换一个区块哈希；
+// 可以按方法预设失败（FailNext）、让交易静默丢失（DropNext）或断开WebSocket（SetWebSocketDown），
+// 复现区块哈希过期、节点不可用、订阅断线等情况。
+
+const (
+	// DefaultBlockhashValidity 区块哈希的有效块数，与主网一致
+	DefaultBlockhashValidity = 150
+	// DefaultFinalizedDepth 交易所在块之后再出多少个块算作finalized
+	DefaultFinalizedDepth = 32
+	// LamportsPerSignature 每个签名的基础手续费
+	LamportsPerSignature = 5000
+)
+
+// Options 模拟节点参数，零值取默认值
+type Options struct {
+	BlockhashValidity uint64 // 区块哈希有效块数，默认150
+	FinalizedDepth    uint64 // 达到finalized需要的确认块数，默认32；confirmed固定为1
+	// AutoAdvance 每处理一个RPC请求出一个块，发送循环和轮询不需要调用方推进也能走完
+	AutoAdvance bool
+	// GenesisHash getGenesisHash的返回值，默认为DefaultGenesisHash；设为主网的创世哈希可测试主网保护
+	GenesisHash solana.Hash
+}
+
+// DefaultGenesisHash 模拟节点默认的创世哈希，不属于任何公共网络
+var DefaultGenesisHash = solana.HashFromBytes([]byte("mockrpc-genesis-hash-00000000000"))
+
+// Server 模拟节点
+type Server struct {
+	URL   string // JSON-RPC地址，传给rpc.New
+	WSURL string // WebSocket地址，传给ws.Connect
+
+	srv      *httptest.Server
+	upgrader websocket.Upgrader
+	opts     Options
+
+	mu          sync.Mutex
+	height      uint64                           // 当前块高，slot与块高相同
+	blockhashes map[solana.Hash]uint64           // 区块哈希 -> lastValidBlockHeight
+	latest      solana.Hash                      // 当前块的区块哈希
+	balances    map[solana.PublicKey]uint64      // 账户余额
+	nonces      map[solana.PublicKey]*nonceState // 已初始化的nonce账户
+	data        map[solana.PublicKey]accountData // SetAccount设置的账户数据
+	txs         map[solana.Signature]*landedTx   // 已上链的交易
+	order       []solana.Signature               // 交易上链的顺序
+	failures    map[string][]failure             // 按方法预设的失败
+	drop        int                              // 接下来静默丢弃的sendTransaction数量
+	fees        []uint64                         // getRecentPrioritizationFees返回的单价
+	calls       map[string]int                   // 各方法被调用的次数
+	subs        map[uint64]*subscription         // signatureSubscribe和logsSubscribe订阅
+	nextSubID   uint64
+	conns       map[*conn]bool // 当前的WebSocket连接
+	wsDown      bool           // 拒绝WebSocket连接，见SetWebSocketDown
+}
+
+// accountData 程序拥有的账户，只读，交易不会修改
+type accountData struct {
+	owner solana.PublicKey
+	data  []byte
+}
+
+// landedTx 已上链的交易
+type landedTx struct {
+	slot     uint64
+	err      interface{}
+	accounts []solana.PublicKey // 交易提到的账户，用于getSignaturesForAddress和logsSubscribe
+	logs     []string
+}
+
+// failure 预设的失败：RPC错误或HTTP状态码二选一
+type failure struct {
+	rpcErr     *jsonrpc.RPCError
+	httpStatus int
+}
+
+// NewServer 启动模拟节点，用完调用Close
+func NewServer(opts Options) *Server {
+	if opts.BlockhashValidity == 0 {
+		opts.BlockhashValidity = DefaultBlockhashValidity
+	}
+	if opts.FinalizedDepth == 0 {
+		opts.FinalizedDepth = DefaultFinalizedDepth
+	}
+	if opts.GenesisHash.IsZero() {
+		opts.GenesisHash = DefaultGenesisHash
+	}
+	s := &Server{
+		opts:        opts,
+		blockhashes: map[solana.Hash]uint64{},
+		balances:    map[solana.PublicKey]uint64{},
+		nonces:      map[solana.PublicKey]*nonceState{},
+		data:        map[solana.PublicKey]accountData{},
+		txs:         map[solana.Signature]*landedTx{},
+		failures:    map[string][]failure{},
+		calls:       map[string]int{},
+		subs:        map[uint64]*subscription{},
+		nextSubID:   1,
+		conns:       map[*conn]bool{},
+	}
+	s.height = 1
+	s.newBlockhash()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	s.WSURL = "ws" + strings.TrimPrefix(s.srv.URL, "http")
+	return s
+}
+
+// Close 关闭模拟节点和所有WebSocket连接
+func (s *Server) Close() {
+	s.mu.Lock()
+	for c := range s.conns {
+		c.close()
+	}
+	s.mu.Unlock()
+	s.srv.Close()
+}
+
+// Advance 出n个块：块高增加、换新的区块哈希，并推送达到订阅级别的签名通知
+func (s *Server) Advance(n uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance(n)
+}
+
+// BlockHeight 当前块高
+func (s *Server) BlockHeight() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.height
+}
+
+// SetBalance 设置账户余额
+func (s *Server) SetBalance(account solana.PublicKey, lamports uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balances[account] = lamports
+}
+
+// SetAccount 设置账户的owner、余额和数据，用于模拟代币账户、程序状态等只读账户
+func (s *Server) SetAccount(account, owner solana.PublicKey, lamports uint64, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balances[account] = lamports
+	s.data[account] = accountData{owner: owner, data: data}
+}
+
+// Balance 账户余额
+func (s *Server) Balance(account solana.PublicKey) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.balances[account]
+}
+
+// SetPrioritizationFees 设置getRecentPrioritizationFees返回的优先费单价（micro-lamports）
+func (s *Server) SetPrioritizationFees(fees ...uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fees = fees
+}
+
+// FailNext 让method的下一次调用返回err，多次调用按顺序排队
+func (s *Server) FailNext(method string, err *jsonrpc.RPCError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method] = append(s.failures[method], failure{rpcErr: err})
+}
+
+// FailNextHTTP 让method的下一次调用返回HTTP状态码，如429限流
+func (s *Server) FailNextHTTP(method string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method] = append(s.failures[method], failure{httpStatus: status})
+}
+
+// DropNext 接下来n笔sendTransaction正常返回签名但交易不会上链，模拟网络丢包
+func (s *Server) DropNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drop += n
+}
+
+// SetWebSocketDown 模拟WebSocket断线：down为true时关闭所有连接，并拒绝新的连接直到再次传入false。
+// 期间交易照常上链，但不会推送通知
+func (s *Server) SetWebSocketDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wsDown = down
+	if down {
+		for c := range s.conns {
+			c.close()
+		}
+	}
+}
+
+// Calls method被调用的次数
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// Landed 交易是否已上链，以及链上执行错误
+func (s *Server) Landed(signature solana.Signature) (bool, interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, ok := s.txs[signature]
+	if !ok {
+		return false, nil
+	}
+	return true, tx.err
+}
+
+// 预设失败常用的错误，与真实节点返回的错误码和data结构一致
+
+// BlockhashNotFound sendTransaction预检时找不到区块哈希
+func BlockhashNotFound() *jsonrpc.RPCError {
+	return preflightFailure("BlockhashNotFound", "Blockhash not found")
+}
+
+// NodeUnhealthy 节点落后或不健康
+func NodeUnhealthy() *jsonrpc.RPCError {
+	return &jsonrpc.RPCError{Code: -32005, Message: "Node is unhealthy", Data: map[string]interface{}{"numSlotsBehind": 42}}
+}
+
+// SignatureVerificationFailure 签名校验失败
+func SignatureVerificationFailure() *jsonrpc.RPCError {
+	return &jsonrpc.RPCError{Code: -32003, Message: "Transaction signature verification failure"}
+}
+
+// preflightFailure sendTransaction预检失败，txErr为交易错误
+func preflightFailure(txErr interface{}, message string, logs ...string) *jsonrpc.RPCError {
+	if logs == nil {
+		logs = []string{}
+	}
+	return &jsonrpc.RPCError{
+		Code:    -32002,
+		Message: "Transaction simulation failed: " + message,
+		Data: map[string]interface{}{
+			"err":           txErr,
+			"logs":          logs,
+			"accounts":      nil,
+			"unitsConsumed": 0,
+		},
+	}
+}
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Result  interface{}       `json:"result,omitempty"`
+	Error   *jsonrpc.RPCError `json:"error,omitempty"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWS(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSON(w, response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &jsonrpc.RPCError{Code: -32700, Message: "Parse error"}})
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[req.Method]++
+	if s.opts.AutoAdvance {
+		s.advance(1)
+	}
+	var (
+		result interface{}
+		rpcErr *jsonrpc.RPCError
+		status int
+	)
+	if queued := s.failures[req.Method]; len(queued) > 0 {
+		s.failures[req.Method] = queued[1:]
+		rpcErr, status = queued[0].rpcErr, queued[0].httpStatus
+	} else {
+		result, rpcErr = s.handle(req.Method, req.Params)
+	}
+	s.mu.Unlock()
+
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	writeJSON(w, response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+}
+
+// handle 分发RPC方法，调用时已持有锁
+func (s *Server) handle(method string, params []json.RawMessage) (interface{}, *jsonrpc.RPCError) {
+	switch method {
+	case "getLatestBlockhash":
+		return s.withContext(map[string]interface{}{
+			"blockhash":            s.latest.String(),
+			"lastValidBlockHeight": s.blockhashes[s.latest],
+		}), nil
+	case "getBlockHeight", "getSlot":
+		return s.height, nil
+	case "getGenesisHash":
+		return s.opts.GenesisHash.String(), nil
+	case "getBalance":
+		var account solana.PublicKey
+		if err := param(params, 0, &account); err != nil {
+			return nil, err
+		}
+		return s.withContext(s.balances[account]), nil
+	case "getAccountInfo":
+		var account solana.PublicKey
+		if err := param(params, 0, &account); err != nil {
+			return nil, err
+		}
+		return s.withContext(s.accountInfo(account, s.balances[account], nil)), nil
+	case "getMultipleAccounts":
+		var accounts []solana.PublicKey
+		if err := param(params, 0, &accounts); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(accounts))
+		for i, account := range accounts {
+			values[i] = s.accountInfo(account, s.balances[account], nil)
+		}
+		return s.withContext(values), nil
+	case "getMinimumBalanceForRentExemption":
+		var space uint64
+		if err := param(params, 0, &space); err != nil {
+			return nil, err
+		}
+		return rentExempt(space), nil
+	case "requestAirdrop":
+		return s.requestAirdrop(params)
+	case "sendTransaction":
+		return s.sendTransaction(params)
+	case "simulateTransaction":
+		return s.simulateTransaction(params)
+	case "getSignatureStatuses":
+		return s.getSignatureStatuses(params)
+	case "getSignaturesForAddress":
+		return s.getSignaturesForAddress(params)
+	case "getRecentPrioritizationFees":
+		fees := make([]map[string]uint64, len(s.fees))
+		for i, fee := range s.fees {
+			fees[i] = map[string]uint64{"slot": s.height - uint64(min(i, int(s.height))), "prioritizationFee": fee}
+		}
+		return fees, nil
+	}
+	return nil, &jsonrpc.RPCError{Code: -32601, Message: "Method not found: " + method}
+}
+
+// withContext 带context.slot的返回值
+func (s *Server) withContext(value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"context": map[string]interface{}{"slot": s.height},
+		"value":   value,
+	}
+}
+
+// advance 出n个块，调用时已持有锁
+func (s *Server) advance(n uint64) {
+	s.height += n
+	s.newBlockhash()
+	s.notify()
+}
+
+// newBlockhash 为当前块生成区块哈希
+func (s *Server) newBlockhash() {
+	var h solana.Hash
+	copy(h[:], fmt.Sprintf("mockrpc-block-%d", s.height))
+	s.latest = h
+	s.blockhashes[h] = s.height + s.opts.BlockhashValidity
+}
+
+// param 解析第i个参数
+// accountInfo System账户的信息，nonce账户带上状态数据；余额为0时账户不存在，返回nil。
+// pending为交易执行中尚未写入的nonce状态，优先于已保存的
+func (s *Server) accountInfo(account solana.PublicKey, lamports uint64, pending map[solana.PublicKey]*nonceState) interface{} {
+	if lamports == 0 {
+		return nil
+	}
+	st, ok := pending[account]
+	if !ok {
+		st = s.nonces[account]
+	}
+	owner, data := solana.SystemProgramID, []byte(nil)
+	if st != nil {
+		data = st.encode()
+	} else if acc, ok := s.data[account]; ok {
+		owner, data = acc.owner, acc.data
+	}
+	return map[string]interface{}{
+		"lamports":   lamports,
+		"owner":      owner.String(),
+		"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+		"executable": false,
+		"rentEpoch":  0,
+		"space":      len(data),
+	}
+}
+
+func param(params []json.RawMessage, i int, v interface{}) *jsonrpc.RPCError {
+	if i >= len(params) {
+		return &jsonrpc.RPCError{Code: -32602, Message: fmt.Sprintf("Invalid params: missing param %d", i)}
+	}
+	if err := json.Unmarshal(params[i], v); err != nil {
+		return &jsonrpc.RPCError{Code: -32602, Message: fmt.Sprintf("Invalid params: %v", err)}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,371 @@
+package mockrpc
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// 交易执行只模拟手续费和System程序的CreateAccount、Transfer及nonce账户指令，
+// 其他程序的指令视为成功、不改变余额
+
+const (
+	systemCreateAccount  = 0 // System程序各指令的编号
+	systemTransfer       = 2
+	systemAdvanceNonce   = 4
+	systemWithdrawNonce  = 5
+	systemInitNonce      = 6
+	systemAuthorizeNonce = 7
+
+	computeBudgetSetUnitLimit = 2 // ComputeBudget程序SetComputeUnitLimit指令编号
+	computeBudgetSetUnitPrice = 3 // ComputeBudget程序SetComputeUnitPrice指令编号
+
+	builtinUnits = 150   // System和ComputeBudget指令消耗的计算单元
+	programUnits = 1_000 // 其他程序指令消耗的计算单元
+)
+
+// execution 交易的执行结果，apply之前不修改余额
+type execution struct {
+	fee      uint64
+	payer    solana.PublicKey
+	balances map[solana.PublicKey]uint64      // 执行后的余额
+	err      interface{}                      // 交易错误，成功为nil
+	nonces   map[solana.PublicKey]*nonceState // 执行后的nonce账户状态，nil值表示账户已关闭
+	logs     []string
+	units    uint64
+	feeErr   bool // 付款账户付不起手续费，交易不会上链
+}
+
+// execute 在当前余额上模拟执行交易
+func (s *Server) execute(tx *solana.Transaction) *execution {
+	msg := tx.Message
+	ex := &execution{
+		payer:    msg.AccountKeys[0],
+		balances: map[solana.PublicKey]uint64{},
+		nonces:   map[solana.PublicKey]*nonceState{},
+	}
+	balance := func(key solana.PublicKey) uint64 {
+		if v, ok := ex.balances[key]; ok {
+			return v
+		}
+		return s.balances[key]
+	}
+
+	var unitLimit, unitPrice uint64
+	for _, inst := range msg.Instructions {
+		program, err := msg.Program(inst.ProgramIDIndex)
+		if err == nil && program.Equals(solana.ComputeBudget) && len(inst.Data) > 0 {
+			switch {
+			case inst.Data[0] == computeBudgetSetUnitLimit && len(inst.Data) >= 5:
+				unitLimit = uint64(binary.LittleEndian.Uint32(inst.Data[1:]))
+			case inst.Data[0] == computeBudgetSetUnitPrice && len(inst.Data) >= 9:
+				unitPrice = binary.LittleEndian.Uint64(inst.Data[1:])
+			}
+		}
+	}
+	if unitLimit == 0 {
+		unitLimit = 200_000
+	}
+	ex.fee = uint64(msg.Header.NumRequiredSignatures)*LamportsPerSignature + (unitLimit*unitPrice+999_999)/1_000_000
+	if balance(ex.payer) < ex.fee {
+		ex.feeErr = true
+		ex.err = "InsufficientFundsForFee"
+		return ex
+	}
+	ex.balances[ex.payer] = balance(ex.payer) - ex.fee
+
+	for i, inst := range msg.Instructions {
+		program, err := msg.Program(inst.ProgramIDIndex)
+		if err != nil {
+			ex.err = map[string]interface{}{"InstructionError": []interface{}{i, "InvalidAccountData"}}
+			return ex
+		}
+		ex.logs = append(ex.logs, fmt.Sprintf("Program %s invoke [1]", program))
+		switch {
+		case program.Equals(solana.ComputeBudget):
+			ex.units += builtinUnits
+		case program.Equals(solana.SystemProgramID):
+			ex.units += builtinUnits
+			if txErr, logs := s.executeSystem(ex, msg, inst, balance); txErr != nil {
+				ex.logs = append(ex.logs, logs...)
+				ex.logs = append(ex.logs, fmt.Sprintf("Program %s failed: %s", program, failureMessage(txErr)))
+				ex.err = map[string]interface{}{"InstructionError": []interface{}{i, txErr}}
+				// 执行失败时只扣手续费
+				ex.balances = map[solana.PublicKey]uint64{ex.payer: s.balances[ex.payer] - ex.fee}
+				ex.nonces = nil
+				return ex
+			}
+		default:
+			ex.units += programUnits
+		}
+		ex.logs = append(ex.logs, fmt.Sprintf("Program %s success", program))
+	}
+	return ex
+}
+
+// apply 把执行结果写入余额并记录交易
+func (s *Server) apply(tx *solana.Transaction, ex *execution) {
+	for key, v := range ex.balances {
+		s.balances[key] = v
+	}
+	for key, v := range ex.nonces {
+		if v == nil {
+			delete(s.nonces, key)
+		} else {
+			s.nonces[key] = v
+		}
+	}
+	s.land(tx.Signatures[0], &landedTx{slot: s.height, err: ex.err, accounts: tx.Message.AccountKeys, logs: ex.logs})
+}
+
+// land 记录上链的交易，并推送给提到其账户的logs订阅
+func (s *Server) land(signature solana.Signature, tx *landedTx) {
+	s.txs[signature] = tx
+	s.order = append(s.order, signature)
+	s.notifyLogs(signature, tx)
+}
+
+// mentions 交易是否提到account
+func mentions(tx *landedTx, account solana.PublicKey) bool {
+	for _, key := range tx.accounts {
+		if key.Equals(account) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeTransaction 解析sendTransaction/simulateTransaction的第一个参数
+func decodeTransaction(params []json.RawMessage) (*solana.Transaction, *jsonrpc.RPCError) {
+	var encoded string
+	if err := param(params, 0, &encoded); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, &jsonrpc.RPCError{Code: -32602, Message: fmt.Sprintf("invalid base64 encoding: %v", err)}
+	}
+	tx, err := solana.TransactionFromDecoder(bin.NewBinDecoder(data))
+	if err != nil {
+		return nil, &jsonrpc.RPCError{Code: -32602, Message: fmt.Sprintf("failed to deserialize transaction: %v", err)}
+	}
+	if len(tx.Message.AccountKeys) == 0 {
+		return nil, &jsonrpc.RPCError{Code: -32602, Message: "transaction has no account keys"}
+	}
+	return tx, nil
+}
+
+func (s *Server) sendTransaction(params []json.RawMessage) (interface{}, *jsonrpc.RPCError) {
+	tx, rpcErr := decodeTransaction(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var opts struct {
+		SkipPreflight bool `json:"skipPreflight"`
+	}
+	if len(params) > 1 {
+		if err := param(params, 1, &opts); err != nil {
+			return nil, err
+		}
+	}
+	if len(tx.Signatures) == 0 {
+		return nil, &jsonrpc.RPCError{Code: -32602, Message: "transaction is not signed"}
+	}
+	signature := tx.Signatures[0]
+	if err := tx.VerifySignatures(); err != nil {
+		return nil, SignatureVerificationFailure()
+	}
+	if _, ok := s.txs[signature]; ok {
+		if opts.SkipPreflight {
+			return signature.String(), nil
+		}
+		return nil, preflightFailure("AlreadyProcessed", "This transaction has already been processed")
+	}
+	if s.drop > 0 {
+		s.drop--
+		return signature.String(), nil
+	}
+
+	// 跳过预检时，区块哈希过期、付不起手续费的交易只是被节点丢弃
+	if !s.validBlockhash(tx) {
+		if opts.SkipPreflight {
+			return signature.String(), nil
+		}
+		return nil, BlockhashNotFound()
+	}
+	ex := s.execute(tx)
+	if ex.err != nil && !opts.SkipPreflight {
+		return nil, preflightFailure(ex.err, "Error processing Instruction", ex.logs...)
+	}
+	if !ex.feeErr {
+		s.apply(tx, ex)
+	}
+	return signature.String(), nil
+}
+
+func (s *Server) simulateTransaction(params []json.RawMessage) (interface{}, *jsonrpc.RPCError) {
+	tx, rpcErr := decodeTransaction(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var opts struct {
+		SigVerify              bool `json:"sigVerify"`
+		ReplaceRecentBlockhash bool `json:"replaceRecentBlockhash"`
+		Accounts               *struct {
+			Addresses []solana.PublicKey `json:"addresses"`
+		} `json:"accounts"`
+	}
+	if len(params) > 1 {
+		if err := param(params, 1, &opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.SigVerify && opts.ReplaceRecentBlockhash {
+		return nil, &jsonrpc.RPCError{Code: -32602, Message: "sigVerify may not be used with replaceRecentBlockhash"}
+	}
+	if opts.SigVerify {
+		if err := tx.VerifySignatures(); err != nil {
+			return nil, SignatureVerificationFailure()
+		}
+	}
+	value := map[string]interface{}{"accounts": nil, "logs": []string{}, "unitsConsumed": 0}
+	if !opts.ReplaceRecentBlockhash {
+		if !s.validBlockhash(tx) {
+			value["err"] = "BlockhashNotFound"
+			return s.withContext(value), nil
+		}
+	}
+	ex := s.execute(tx)
+	value["err"] = ex.err
+	value["unitsConsumed"] = ex.units
+	if ex.logs != nil {
+		value["logs"] = ex.logs
+	}
+	if opts.Accounts != nil {
+		// 返回模拟执行后的账户状态
+		accounts := make([]interface{}, len(opts.Accounts.Addresses))
+		for i, addr := range opts.Accounts.Addresses {
+			lamports, ok := ex.balances[addr]
+			if !ok {
+				lamports = s.balances[addr]
+			}
+			accounts[i] = s.accountInfo(addr, lamports, ex.nonces)
+		}
+		value["accounts"] = accounts
+	}
+	return s.withContext(value), nil
+}
+
+func (s *Server) requestAirdrop(params []json.RawMessage) (interface{}, *jsonrpc.RPCError) {
+	var account solana.PublicKey
+	if err := param(params, 0, &account); err != nil {
+		return nil, err
+	}
+	var lamports uint64
+	if err := param(params, 1, &lamports); err != nil {
+		return nil, err
+	}
+	// 空投交易的签名随便生成，只要能查到状态即可
+	var signature solana.Signature
+	copy(signature[:], fmt.Sprintf("mockrpc-airdrop-%d-%d-%s", s.height, len(s.txs), account))
+	s.balances[account] += lamports
+	s.land(signature, &landedTx{slot: s.height, accounts: []solana.PublicKey{account}})
+	return signature.String(), nil
+}
+
+func (s *Server) getSignatureStatuses(params []json.RawMessage) (interface{}, *jsonrpc.RPCError) {
+	var signatures []solana.Signature
+	if err := param(params, 0, &signatures); err != nil {
+		return nil, err
+	}
+	statuses := make([]interface{}, len(signatures))
+	for i, sig := range signatures {
+		tx, ok := s.txs[sig]
+		if !ok {
+			continue
+		}
+		status := map[string]interface{}{
+			"slot":               tx.slot,
+			"confirmations":      nil,
+			"err":                tx.err,
+			"confirmationStatus": s.confirmationStatus(tx),
+			"status":             map[string]interface{}{"Ok": nil},
+		}
+		if tx.err != nil {
+			status["status"] = map[string]interface{}{"Err": tx.err}
+		}
+		if depth := s.height - tx.slot; depth < s.opts.FinalizedDepth {
+			status["confirmations"] = depth
+		}
+		statuses[i] = status
+	}
+	return s.withContext(statuses), nil
+}
+
+// getSignaturesForAddress 按从新到旧返回提到地址的交易，支持limit、before和until分页
+func (s *Server) getSignaturesForAddress(params []json.RawMessage) (interface{}, *jsonrpc.RPCError) {
+	var address solana.PublicKey
+	if err := param(params, 0, &address); err != nil {
+		return nil, err
+	}
+	var opts struct {
+		Limit  int              `json:"limit"`
+		Before solana.Signature `json:"before"`
+		Until  solana.Signature `json:"until"`
+	}
+	if len(params) > 1 {
+		if err := param(params, 1, &opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 1000
+	}
+	if opts.Limit > 1000 {
+		return nil, &jsonrpc.RPCError{Code: -32602, Message: "Invalid limit; max 1000"}
+	}
+
+	signatures := []interface{}{}
+	// 指定before时从它之后（更旧）开始
+	started := opts.Before.IsZero()
+	for i := len(s.order) - 1; i >= 0 && len(signatures) < opts.Limit; i-- {
+		sig := s.order[i]
+		if !started {
+			started = sig == opts.Before
+			continue
+		}
+		if sig == opts.Until {
+			break
+		}
+		tx := s.txs[sig]
+		if !mentions(tx, address) {
+			continue
+		}
+		signatures = append(signatures, map[string]interface{}{
+			"signature":          sig.String(),
+			"slot":               tx.slot,
+			"err":                tx.err,
+			"memo":               nil,
+			"blockTime":          nil,
+			"confirmationStatus": s.confirmationStatus(tx),
+		})
+	}
+	return signatures, nil
+}
+
+// confirmationStatus 交易当前的承诺级别
+func (s *Server) confirmationStatus(tx *landedTx) string {
+	switch depth := s.height - tx.slot; {
+	case depth >= s.opts.FinalizedDepth:
+		return "finalized"
+	case depth >= 1:
+		return "confirmed"
+	default:
+		return "processed"
+	}
+}
@@ -0,0 +1,178 @@
+package mockrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket支持signatureSubscribe和logsSubscribe：
+// 签名订阅在交易达到订阅的承诺级别时推送一次通知并自动取消订阅，与真实节点一致；
+// logs订阅只支持mentions过滤，交易上链时立即推送，不区分承诺级别
+
+// conn 一条WebSocket连接，写操作需要串行
+type conn struct {
+	mu sync.Mutex
+	ws *websocket.Conn
+}
+
+func (c *conn) write(v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ws.WriteJSON(v)
+}
+
+func (c *conn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ws.Close()
+}
+
+// subscription 一个签名订阅或logs订阅
+type subscription struct {
+	conn       *conn
+	signature  solana.Signature // 签名订阅
+	mentions   solana.PublicKey // logs订阅，签名订阅为零值
+	commitment string
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  struct {
+		Result       interface{} `json:"result"`
+		Subscription uint64      `json:"subscription"`
+	} `json:"params"`
+}
+
+func (s *Server) serveWS(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	down := s.wsDown
+	s.mu.Unlock()
+	if down {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &conn{ws: ws}
+	s.mu.Lock()
+	s.conns[c] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		for id, sub := range s.subs {
+			if sub.conn == c {
+				delete(s.subs, id)
+			}
+		}
+		delete(s.conns, c)
+		s.mu.Unlock()
+		ws.Close()
+	}()
+
+	for {
+		var req request
+		if err := ws.ReadJSON(&req); err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.calls[req.Method]++
+		result, rpcErr := s.handleWS(c, req.Method, req.Params)
+		// 先回复订阅号再推送已满足条件的通知，客户端要靠订阅号识别通知
+		c.write(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+		s.notify()
+		s.mu.Unlock()
+	}
+}
+
+// handleWS 处理订阅请求，调用时已持有锁
+func (s *Server) handleWS(c *conn, method string, params []json.RawMessage) (interface{}, *jsonrpc.RPCError) {
+	switch method {
+	case "signatureSubscribe":
+		var signature solana.Signature
+		if err := param(params, 0, &signature); err != nil {
+			return nil, err
+		}
+		var conf struct {
+			Commitment string `json:"commitment"`
+		}
+		if len(params) > 1 {
+			if err := param(params, 1, &conf); err != nil {
+				return nil, err
+			}
+		}
+		if conf.Commitment == "" {
+			conf.Commitment = "finalized"
+		}
+		id := s.nextSubID
+		s.nextSubID++
+		s.subs[id] = &subscription{conn: c, signature: signature, commitment: conf.Commitment}
+		return id, nil
+	case "logsSubscribe":
+		var filter struct {
+			Mentions []solana.PublicKey `json:"mentions"`
+		}
+		if err := param(params, 0, &filter); err != nil || len(filter.Mentions) != 1 {
+			return nil, &jsonrpc.RPCError{Code: -32602, Message: "Invalid params: only {\"mentions\": [<address>]} is supported"}
+		}
+		id := s.nextSubID
+		s.nextSubID++
+		s.subs[id] = &subscription{conn: c, mentions: filter.Mentions[0]}
+		return id, nil
+	case "signatureUnsubscribe", "logsUnsubscribe":
+		var id uint64
+		if err := param(params, 0, &id); err != nil {
+			return nil, err
+		}
+		// 通知推送后订阅已自动取消，客户端再退订时同样返回true
+		delete(s.subs, id)
+		return true, nil
+	}
+	return nil, &jsonrpc.RPCError{Code: -32601, Message: "Method not found: " + method}
+}
+
+// notify 推送达到订阅级别的签名通知，调用时已持有锁
+func (s *Server) notify() {
+	rank := map[string]int{"processed": 1, "confirmed": 2, "finalized": 3}
+	for id, sub := range s.subs {
+		if !sub.mentions.IsZero() {
+			continue
+		}
+		tx, ok := s.txs[sub.signature]
+		if !ok || rank[s.confirmationStatus(tx)] < rank[sub.commitment] {
+			continue
+		}
+		msg := notification{JSONRPC: "2.0", Method: "signatureNotification"}
+		msg.Params.Subscription = id
+		msg.Params.Result = s.withContext(map[string]interface{}{"err": tx.err})
+		sub.conn.write(msg)
+		delete(s.subs, id)
+	}
+}
+
+// notifyLogs 向提到交易账户的logs订阅推送日志，调用时已持有锁
+func (s *Server) notifyLogs(signature solana.Signature, tx *landedTx) {
+	for id, sub := range s.subs {
+		if sub.mentions.IsZero() || !mentions(tx, sub.mentions) {
+			continue
+		}
+		logs := tx.logs
+		if logs == nil {
+			logs = []string{}
+		}
+		msg := notification{JSONRPC: "2.0", Method: "logsNotification"}
+		msg.Params.Subscription = id
+		msg.Params.Result = map[string]interface{}{
+			"context": map[string]interface{}{"slot": tx.slot},
+			"value":   map[string]interface{}{"signature": signature.String(), "err": tx.err, "logs": logs},
+		}
+		sub.conn.write(msg)
+	}
+}
@@ -0,0 +1,306 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// 测试网空投：按水龙头单次上限拆分请求，每笔等待签名确认，再轮询余额直到到账；
+// 遇到限流（HTTP 429或水龙头的限额错误）按指数退避重试。主网没有水龙头，
+// 除了检查配置的网络名，还会比对节点的创世哈希，防止把主网节点误配成devnet
+
+// MainnetGenesisHash 主网的创世哈希
+var MainnetGenesisHash = solana.MustHashFromBase58("5eykt4UsFv8P8NJdTREpY1vzqKqZKvdpKuc147dw2N9d")
+
+var (
+	// ErrAirdropOnMainnet 在主网上请求空投
+	ErrAirdropOnMainnet = errors.New("主网没有水龙头，不能请求空投")
+	// ErrRateLimited 请求被节点或水龙头限流，重试次数用完后返回
+	ErrRateLimited = errors.New("请求被限流")
+)
+
+const (
+	defaultAirdropRetries      = 5
+	defaultAirdropBackoff      = 2 * time.Second
+	defaultAirdropMaxBackoff   = 30 * time.Second
+	defaultAirdropTimeout      = 60 * time.Second
+	defaultBalancePollInterval = time.Second
+)
+
+// AirdropLimit 各网络水龙头的单次空投上限（lamports），0表示不限制（solana-test-validator）
+func AirdropLimit(network string) uint64 {
+	switch network {
+	case "devnet":
+		return 2 * solana.LAMPORTS_PER_SOL
+	case "testnet":
+		return solana.LAMPORTS_PER_SOL
+	}
+	return 0
+}
+
+// AirdropOptions 空投参数
+type AirdropOptions struct {
+	Network       string             // devnet/testnet/localnet，为mainnet时拒绝执行
+	MaxPerRequest uint64             // 单次请求上限，默认AirdropLimit(Network)
+	Commitment    rpc.CommitmentType // 等待的承诺级别，默认confirmed
+	Timeout       time.Duration      // 每笔空投确认和余额到账各自的超时，默认60秒
+	MaxRetries    int                // 每笔请求遇到限流或节点不可用时的重试次数，默认5
+	Backoff       time.Duration      // 第一次重试的等待，之后每次翻倍，默认2秒
+	MaxBackoff    time.Duration      // 默认30秒
+	// OnProgress 请求、重试和确认时回调，可用于打印进度
+	OnProgress func(AirdropProgress)
+}
+
+// AirdropProgress 空投进度
+type AirdropProgress struct {
+	Request   int              // 第几笔请求，从1开始
+	Requests  int              // 总请求数
+	Lamports  uint64           // 本笔请求的数量
+	Signature solana.Signature // 确认后才有
+	Retry     int              // 第几次重试，0为首次请求
+	Wait      time.Duration    // 重试前的等待
+	Err       error            // 本次请求失败的原因
+}
+
+func (p AirdropProgress) String() string {
+	s := fmt.Sprintf("空投 %d/%d: %s SOL", p.Request, p.Requests, FormatTokenAmount(p.Lamports, 9))
+	switch {
+	case p.Err != nil:
+		s += fmt.Sprintf(" 失败: %v，%s 后第%d次重试", p.Err, p.Wait, p.Retry)
+	case !p.Signature.IsZero():
+		s += fmt.Sprintf(" 已确认 %s", p.Signature)
+	}
+	return s
+}
+
+// AirdropResult 空投结果
+type AirdropResult struct {
+	Signatures []solana.Signature
+	Requested  uint64 // 已确认的空投总数（lamports）
+	Balance    uint64 // 最终余额
+}
+
+// AirdropTo 空投直到account余额达到target，余额已足够时不发请求
+func AirdropTo(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	account solana.PublicKey,
+	target uint64,
+	opts AirdropOptions,
+) (*AirdropResult, error) {
+	if err := checkAirdropNetwork(ctx, rpcClient, opts.Network); err != nil {
+		return nil, err
+	}
+	balance, err := rpcClient.GetBalance(ctx, account, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", ClassifyError(err))
+	}
+	if balance.Value >= target {
+		return &AirdropResult{Balance: balance.Value}, nil
+	}
+	return airdrop(ctx, rpcClient, wsClient, account, balance.Value, target-balance.Value, opts)
+}
+
+// Airdrop 向account空投lamports，超过单次上限时拆成多笔，全部确认且余额到账后返回
+func Airdrop(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	account solana.PublicKey,
+	lamports uint64,
+	opts AirdropOptions,
+) (*AirdropResult, error) {
+	if err := checkAirdropNetwork(ctx, rpcClient, opts.Network); err != nil {
+		return nil, err
+	}
+	balance, err := rpcClient.GetBalance(ctx, account, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", ClassifyError(err))
+	}
+	return airdrop(ctx, rpcClient, wsClient, account, balance.Value, lamports, opts)
+}
+
+func airdrop(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	account solana.PublicKey,
+	startBalance, lamports uint64,
+	opts AirdropOptions,
+) (*AirdropResult, error) {
+	if lamports == 0 {
+		return nil, fmt.Errorf("空投数量不能为0")
+	}
+	if opts.MaxPerRequest == 0 {
+		opts.MaxPerRequest = AirdropLimit(opts.Network)
+	}
+	if opts.MaxPerRequest == 0 {
+		opts.MaxPerRequest = lamports
+	}
+	if opts.Commitment == "" {
+		opts.Commitment = rpc.CommitmentConfirmed
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultAirdropTimeout
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = defaultAirdropRetries
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultAirdropBackoff
+	}
+	if opts.MaxBackoff < opts.Backoff {
+		opts.MaxBackoff = max(defaultAirdropMaxBackoff, opts.Backoff)
+	}
+
+	chunks := splitAmount(lamports, opts.MaxPerRequest)
+	result := &AirdropResult{Balance: startBalance}
+	for i, amount := range chunks {
+		progress := AirdropProgress{Request: i + 1, Requests: len(chunks), Lamports: amount}
+		sig, err := requestAirdrop(ctx, rpcClient, wsClient, account, amount, opts, progress)
+		if err != nil {
+			return result, fmt.Errorf("第%d/%d笔空投失败: %w", i+1, len(chunks), err)
+		}
+		result.Signatures = append(result.Signatures, sig)
+		result.Requested += amount
+		if opts.OnProgress != nil {
+			progress.Signature = sig
+			opts.OnProgress(progress)
+		}
+	}
+
+	balance, err := waitForBalance(ctx, rpcClient, account, startBalance+result.Requested, opts)
+	result.Balance = balance
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// requestAirdrop 请求一笔空投并等待确认，限流、节点不可用和确认超时时退避重试
+func requestAirdrop(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	account solana.PublicKey,
+	lamports uint64,
+	opts AirdropOptions,
+	progress AirdropProgress,
+) (solana.Signature, error) {
+	wait := opts.Backoff
+	for retry := 0; ; retry++ {
+		sig, err := rpcClient.RequestAirdrop(ctx, account, lamports, opts.Commitment)
+		if err == nil {
+			_, err = WaitForConfirmation(ctx, rpcClient, wsClient, sig, opts.Commitment, opts.Timeout)
+			if err == nil {
+				return sig, nil
+			}
+		} else {
+			err = classifyAirdropError(err)
+		}
+		// 空投交易由水龙头签名，确认超时说明没有上链，可以重新请求
+		retryable := errors.Is(err, ErrRateLimited) || IsRetryable(err) || errors.Is(err, ErrConfirmationTimeout)
+		if !retryable || ctx.Err() != nil {
+			return solana.Signature{}, err
+		}
+		if retry >= opts.MaxRetries {
+			return solana.Signature{}, fmt.Errorf("已重试 %d 次: %w", retry, err)
+		}
+		if opts.OnProgress != nil {
+			progress.Retry, progress.Wait, progress.Err = retry+1, wait, err
+			opts.OnProgress(progress)
+		}
+		select {
+		case <-ctx.Done():
+			return solana.Signature{}, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, opts.MaxBackoff)
+	}
+}
+
+// waitForBalance 轮询余额直到不少于target。确认后余额查询可能还落后几个slot
+func waitForBalance(ctx context.Context, rpcClient *rpc.Client, account solana.PublicKey, target uint64, opts AirdropOptions) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	ticker := time.NewTicker(defaultBalancePollInterval)
+	defer ticker.Stop()
+	var balance uint64
+	for {
+		res, err := rpcClient.GetBalance(ctx, account, opts.Commitment)
+		if err == nil {
+			balance = res.Value
+			if balance >= target {
+				return balance, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return balance, fmt.Errorf("等待余额到账超时: 当前 %s SOL，目标 %s SOL",
+					FormatTokenAmount(balance, 9), FormatTokenAmount(target, 9))
+			}
+			return balance, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAirdropNetwork 配置为主网或节点的创世哈希是主网时拒绝空投；
+// 读不到创世哈希时只按配置判断，配置也为空则拒绝
+func checkAirdropNetwork(ctx context.Context, rpcClient *rpc.Client, network string) error {
+	if network == "mainnet" || network == "mainnet-beta" {
+		return ErrAirdropOnMainnet
+	}
+	genesis, err := rpcClient.GetGenesisHash(ctx)
+	if err != nil {
+		if network == "" {
+			return fmt.Errorf("无法确认节点所属网络，拒绝空投: %w", ClassifyError(err))
+		}
+		return nil
+	}
+	if genesis.Equals(MainnetGenesisHash) {
+		return fmt.Errorf("%w: 节点的创世哈希属于主网（配置的网络为 %q）", ErrAirdropOnMainnet, network)
+	}
+	return nil
+}
+
+// classifyAirdropError 在ClassifyError的基础上识别限流：HTTP 429，
+// 或水龙头返回的"airdrop request limit reached"、"rate limit"等内部错误
+func classifyAirdropError(err error) error {
+	classified := ClassifyError(err)
+	var httpErr *jsonrpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code == httpTooManyRequests {
+		return fmt.Errorf("%w: %w", ErrRateLimited, classified)
+	}
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		msg := strings.ToLower(rpcErr.Message)
+		for _, s := range []string{"rate limit", "limit reached", "too many requests"} {
+			if strings.Contains(msg, s) {
+				return fmt.Errorf("%w: %w", ErrRateLimited, classified)
+			}
+		}
+	}
+	return classified
+}
+
+// splitAmount 把amount拆成不超过limit的若干份
+func splitAmount(amount, limit uint64) []uint64 {
+	var chunks []uint64
+	for amount > 0 {
+		chunk := min(amount, limit)
+		chunks = append(chunks, chunk)
+		amount -= chunk
+	}
+	return chunks
+}
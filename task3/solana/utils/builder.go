@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// TxBuilder 构造需要多个签名者的交易：手头有的签名者先签，缺的签名留空，
+// 序列化后交给其他人用PartialSign补签，或者各自签名后用MergeSignatures合并
+type TxBuilder struct {
+	payer        solana.PublicKey
+	blockhash    solana.Hash
+	instructions []solana.Instruction
+	signers      []Signer
+}
+
+// NewTxBuilder payer为手续费付款账户，它也是交易的第一个签名者
+func NewTxBuilder(payer solana.PublicKey, blockhash solana.Hash) *TxBuilder {
+	return &TxBuilder{payer: payer, blockhash: blockhash}
+}
+
+// Add 追加指令
+func (b *TxBuilder) Add(instructions ...solana.Instruction) *TxBuilder {
+	b.instructions = append(b.instructions, instructions...)
+	return b
+}
+
+// Sign 添加本地可用的签名者，同一个公钥只保留第一次添加的
+func (b *TxBuilder) Sign(signers ...Signer) *TxBuilder {
+	for _, s := range signers {
+		if !b.hasSigner(s.PublicKey()) {
+			b.signers = append(b.signers, s)
+		}
+	}
+	return b
+}
+
+func (b *TxBuilder) hasSigner(key solana.PublicKey) bool {
+	for _, s := range b.signers {
+		if s.PublicKey().Equals(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Build 构造交易并用已添加的签名者签名，返回还缺签名的签名者。
+// 添加的签名者不在交易的签名者中时报错
+func (b *TxBuilder) Build() (*solana.Transaction, []solana.PublicKey, error) {
+	if len(b.instructions) == 0 {
+		return nil, nil, fmt.Errorf("交易没有指令")
+	}
+	tx, err := solana.NewTransaction(b.instructions, b.blockhash, solana.TransactionPayer(b.payer))
+	if err != nil {
+		return nil, nil, fmt.Errorf("构造交易失败: %v", err)
+	}
+	if err := PartialSign(tx, b.signers...); err != nil {
+		return nil, nil, err
+	}
+	missing, err := MissingSigners(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tx, missing, nil
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// statusPollInterval getSignatureStatuses轮询间隔，WebSocket订阅丢消息或断开时靠轮询兜底
+const statusPollInterval = 2 * time.Second
+
+var (
+	// ErrConfirmationTimeout 在超时时间内交易未达到目标承诺级别
+	ErrConfirmationTimeout = errors.New("等待交易确认超时")
+	// ErrTransactionFailed 交易已上链但执行失败
+	ErrTransactionFailed = errors.New("交易执行失败")
+)
+
+// ConfirmationResult 交易确认结果
+type ConfirmationResult struct {
+	Signature  solana.Signature
+	Slot       uint64                     // 交易所在的slot
+	Commitment rpc.CommitmentType         // 等待的目标承诺级别
+	Status     rpc.ConfirmationStatusType // 返回时交易实际达到的状态
+	Err        interface{}                // 链上执行错误，成功时为nil
+}
+
+// WaitForConfirmation 等待交易达到commitment级别（processed/confirmed/finalized）。
+// wsClient不为nil时订阅signatureSubscribe，同时用rpcClient轮询getSignatureStatuses兜底，
+// 两者都使用调用方的网络。交易执行失败时返回结果和ErrTransactionFailed，超时返回ErrConfirmationTimeout
+func WaitForConfirmation(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	signature solana.Signature,
+	commitment rpc.CommitmentType,
+	timeout time.Duration,
+) (*ConfirmationResult, error) {
+	if commitment == "" {
+		commitment = rpc.CommitmentConfirmed
+	}
+	if _, ok := commitmentRank[rpc.ConfirmationStatusType(commitment)]; !ok {
+		return nil, fmt.Errorf("不支持的承诺级别: %s", commitment)
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// 订阅失败不影响轮询，只是确认会慢一些
+	notified := make(chan *ws.SignatureResult, 1)
+	if wsClient != nil {
+		if sub, err := wsClient.SignatureSubscribe(signature, commitment); err == nil {
+			defer sub.Unsubscribe()
+			go func() {
+				if res, err := sub.Recv(ctx); err == nil {
+					notified <- res
+				}
+			}()
+		}
+	}
+
+	result := &ConfirmationResult{Signature: signature, Commitment: commitment}
+	ticker := time.NewTicker(statusPollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := pollSignatureStatus(ctx, rpcClient, result)
+		if done || err != nil {
+			return result, err
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return result, fmt.Errorf("%w: %s 未达到 %s", ErrConfirmationTimeout, signature, commitment)
+			}
+			return result, ctx.Err()
+		case res := <-notified:
+			// 通知只在达到订阅的承诺级别后推送
+			result.Slot = res.Context.Slot
+			result.Status = rpc.ConfirmationStatusType(commitment)
+			result.Err = res.Value.Err
+			return result, result.err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// commitmentRank 承诺级别从低到高
+var commitmentRank = map[rpc.ConfirmationStatusType]int{
+	rpc.ConfirmationStatusProcessed: 1,
+	rpc.ConfirmationStatusConfirmed: 2,
+	rpc.ConfirmationStatusFinalized: 3,
+}
+
+// pollSignatureStatus 查询一次交易状态，达到目标级别或执行失败时done为true
+func pollSignatureStatus(ctx context.Context, rpcClient *rpc.Client, result *ConfirmationResult) (done bool, err error) {
+	res, err := rpcClient.GetSignatureStatuses(ctx, true, result.Signature)
+	if err != nil || len(res.Value) == 0 || res.Value[0] == nil {
+		// 节点暂时查不到或请求失败，下一轮再试
+		return false, nil
+	}
+	status := res.Value[0]
+	result.Slot = status.Slot
+	result.Status = status.ConfirmationStatus
+	result.Err = status.Err
+	if status.Err != nil {
+		return true, result.err()
+	}
+	return commitmentRank[status.ConfirmationStatus] >= commitmentRank[rpc.ConfirmationStatusType(result.Commitment)], nil
+}
+
+func (r *ConfirmationResult) err() error {
+	if r.Err == nil {
+		return nil
+	}
+	if kind := TransactionErrorKind(r.Err, nil); kind != nil {
+		return fmt.Errorf("%w: %w: %s slot %d: %v", ErrTransactionFailed, kind, r.Signature, r.Slot, r.Err)
+	}
+	return fmt.Errorf("%w: %s slot %d: %v", ErrTransactionFailed, r.Signature, r.Slot, r.Err)
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"solana-go/mockrpc"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// sendRaw 跳过预检直接广播，交易在当前块上链，状态为processed
+func (n *testNode) sendRaw(t *testing.T, amount uint64) *ConfirmationResult {
+	t.Helper()
+	tx, _ := n.transfer(t, amount)
+	signature, err := n.rpc.SendTransactionWithOpts(context.Background(), tx, rpc.TransactionOpts{SkipPreflight: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ConfirmationResult{Signature: signature}
+}
+
+// advanceAfter 等待delay后出n个块
+func (n *testNode) advanceAfter(delay time.Duration, blocks uint64) {
+	go func() {
+		time.Sleep(delay)
+		n.srv.Advance(blocks)
+	}()
+}
+
+func TestWaitForConfirmationWebSocket(t *testing.T) {
+	ctx := context.Background()
+	n := newTestNode(t, mockrpc.Options{FinalizedDepth: 3})
+	wsClient, err := ws.Connect(ctx, n.srv.WSURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wsClient.Close()
+	sent := n.sendRaw(t, testAmount)
+
+	// 达到finalized之前不会推送，之后由订阅通知返回，不必等下一次轮询
+	n.advanceAfter(50*time.Millisecond, 1)
+	n.advanceAfter(100*time.Millisecond, 2)
+	start := time.Now()
+	result, err := WaitForConfirmation(ctx, n.rpc, wsClient, sent.Signature, rpc.CommitmentFinalized, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= statusPollInterval {
+		t.Fatalf("WebSocket通知用了 %v，不应等到轮询", elapsed)
+	}
+	if result.Status != rpc.ConfirmationStatusFinalized || result.Err != nil {
+		t.Fatalf("确认结果: %+v", result)
+	}
+	if got := n.srv.Calls("signatureSubscribe"); got != 1 {
+		t.Fatalf("signatureSubscribe调用 %d 次", got)
+	}
+	if got := n.srv.Calls("getSignatureStatuses"); got != 1 {
+		t.Fatalf("getSignatureStatuses调用 %d 次，期望只有开始时的1次", got)
+	}
+}
+
+func TestWaitForConfirmationPolling(t *testing.T) {
+	n := newTestNode(t, mockrpc.Options{})
+	sent := n.sendRaw(t, testAmount)
+
+	// 没有WebSocket时靠轮询，出块后的下一次轮询返回
+	n.advanceAfter(50*time.Millisecond, 1)
+	result, err := WaitForConfirmation(context.Background(), n.rpc, nil, sent.Signature, rpc.CommitmentConfirmed, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != rpc.ConfirmationStatusConfirmed || result.Slot == 0 {
+		t.Fatalf("确认结果: %+v", result)
+	}
+	if got := n.srv.Calls("getSignatureStatuses"); got != 2 {
+		t.Fatalf("getSignatureStatuses调用 %d 次，期望2次", got)
+	}
+}
+
+func TestWaitForConfirmationTimeout(t *testing.T) {
+	n := newTestNode(t, mockrpc.Options{})
+	sent := n.sendRaw(t, testAmount)
+
+	result, err := WaitForConfirmation(context.Background(), n.rpc, nil, sent.Signature, rpc.CommitmentConfirmed, 100*time.Millisecond)
+	if !errors.Is(err, ErrConfirmationTimeout) {
+		t.Fatalf("err = %v，期望超时", err)
+	}
+	if result.Status != rpc.ConfirmationStatusProcessed {
+		t.Fatalf("超时时的状态 %s，期望processed", result.Status)
+	}
+}
+
+func TestWaitForConfirmationFailed(t *testing.T) {
+	ctx := context.Background()
+	n := newTestNode(t, mockrpc.Options{})
+	wsClient, err := ws.Connect(ctx, n.srv.WSURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wsClient.Close()
+	// 转账金额超过余额，跳过预检后交易上链但执行失败
+	sent := n.sendRaw(t, 2*testBalance)
+
+	for _, client := range []*ws.Client{wsClient, nil} {
+		result, err := WaitForConfirmation(ctx, n.rpc, client, sent.Signature, rpc.CommitmentConfirmed, time.Second)
+		if !errors.Is(err, ErrTransactionFailed) || result.Err == nil {
+			t.Fatalf("err = %v，期望交易执行失败", err)
+		}
+	}
+
+	if _, err := WaitForConfirmation(ctx, n.rpc, nil, sent.Signature, "recent", time.Second); err == nil {
+		t.Fatal("不支持的承诺级别应报错")
+	}
+}
@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// Solana JSON-RPC的服务端错误码，见agave rpc-client-api的custom_error.rs
+const (
+	rpcBlockCleanedUp               = -32001
+	rpcSendTransactionPreflightFail = -32002
+	rpcSignatureVerificationFailure = -32003
+	rpcBlockNotAvailable            = -32004
+	rpcNodeUnhealthy                = -32005
+	rpcTransactionPrecompileFailure = -32006
+	rpcSlotSkipped                  = -32007
+	rpcMinContextSlotNotReached     = -32016
+	httpTooManyRequests             = 429
+)
+
+var (
+	// ErrBlockhashExpired 区块哈希已过期或节点找不到，交易没有上链，需要换新的区块哈希重新签名
+	ErrBlockhashExpired = errors.New("区块哈希已过期")
+	// ErrInsufficientFunds 余额不足以支付转账金额、手续费或租金
+	ErrInsufficientFunds = errors.New("余额不足")
+	// ErrSimulationFailed 发送前的预检模拟失败，具体原因和程序日志见*SimulationError
+	ErrSimulationFailed = errors.New("交易模拟失败")
+	// ErrSignatureVerification 签名校验失败，通常是缺少签名者或签名后又修改了交易
+	ErrSignatureVerification = errors.New("交易签名校验失败")
+	// ErrNodeUnavailable 节点不健康、落后或限流，换节点或稍后重试
+	ErrNodeUnavailable = errors.New("RPC节点暂不可用")
+	// ErrAlreadyProcessed 交易已被处理过，重复广播时会遇到
+	ErrAlreadyProcessed = errors.New("交易已被处理")
+	// ErrInvalidKeypair 密钥文件无法读取或格式错误
+	ErrInvalidKeypair = errors.New("无效的密钥")
+)
+
+// SimulationError 预检模拟失败的详情
+type SimulationError struct {
+	Err           interface{} // 交易错误，如"BlockhashNotFound"或{"InstructionError":[0,{"Custom":1}]}
+	Logs          []string    // 程序日志
+	UnitsConsumed uint64
+	kind          error // 归类后的错误，如ErrInsufficientFunds
+}
+
+func (e *SimulationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v: %v", ErrSimulationFailed, e.Err)
+	if len(e.Logs) > 0 {
+		fmt.Fprintf(&b, "\n程序日志:\n  %s", strings.Join(e.Logs, "\n  "))
+	}
+	return b.String()
+}
+
+func (e *SimulationError) Unwrap() []error {
+	if e.kind != nil {
+		return []error{ErrSimulationFailed, e.kind}
+	}
+	return []error{ErrSimulationFailed}
+}
+
+// ClassifyError 把RPC返回的错误按错误码归类为本包的错误类型，原始错误仍可通过errors.As取到
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var httpErr *jsonrpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code == httpTooManyRequests {
+		return fmt.Errorf("%w: 请求过于频繁: %w", ErrNodeUnavailable, err)
+	}
+	var rpcErr *jsonrpc.RPCError
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+	switch rpcErr.Code {
+	case rpcSendTransactionPreflightFail:
+		sim := simulationError(rpcErr.Data)
+		if sim.Err == nil {
+			sim.Err = rpcErr.Message
+		}
+		return sim
+	case rpcSignatureVerificationFailure, rpcTransactionPrecompileFailure:
+		return &RPCError{Kind: ErrSignatureVerification, Err: rpcErr}
+	case rpcNodeUnhealthy, rpcBlockNotAvailable, rpcMinContextSlotNotReached, rpcBlockCleanedUp, rpcSlotSkipped:
+		return &RPCError{Kind: ErrNodeUnavailable, Err: rpcErr}
+	}
+	return &RPCError{Err: rpcErr}
+}
+
+// RPCError 归类后的RPC错误，jsonrpc.RPCError自带的Error()会打印整个结构体，这里只保留错误码和消息
+type RPCError struct {
+	Kind error // 归类结果，无法归类时为nil
+	Err  *jsonrpc.RPCError
+}
+
+func (e *RPCError) Error() string {
+	if e.Kind != nil {
+		return fmt.Sprintf("%v: %s (错误码 %d)", e.Kind, e.Err.Message, e.Err.Code)
+	}
+	return fmt.Sprintf("%s (错误码 %d)", e.Err.Message, e.Err.Code)
+}
+
+func (e *RPCError) Unwrap() []error {
+	if e.Kind != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Err}
+}
+
+// TransactionErrorKind 将交易错误（getSignatureStatuses的err或模拟结果的err）归类，无法归类时返回nil
+func TransactionErrorKind(txErr interface{}, logs []string) error {
+	switch name := transactionErrorName(txErr); name {
+	case "BlockhashNotFound":
+		return ErrBlockhashExpired
+	case "AlreadyProcessed":
+		return ErrAlreadyProcessed
+	case "InsufficientFundsForFee", "InsufficientFundsForRent":
+		return ErrInsufficientFunds
+	case "InstructionError":
+		// 指令自定义错误码因程序而异，只能借助程序日志判断余额不足
+		for _, line := range logs {
+			l := strings.ToLower(line)
+			if strings.Contains(l, "insufficient lamports") || strings.Contains(l, "insufficient funds") {
+				return ErrInsufficientFunds
+			}
+		}
+	}
+	return nil
+}
+
+// IsRetryable 判断错误是否值得重试：区块哈希过期可以重新签名，节点不可用可以稍后再试
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrBlockhashExpired) || errors.Is(err, ErrNodeUnavailable)
+}
+
+// NewSimulationError 由simulateTransaction返回的err和日志构造模拟失败错误
+func NewSimulationError(txErr interface{}, logs []string, unitsConsumed uint64) *SimulationError {
+	return &SimulationError{
+		Err:           txErr,
+		Logs:          logs,
+		UnitsConsumed: unitsConsumed,
+		kind:          TransactionErrorKind(txErr, logs),
+	}
+}
+
+// simulationError 解析sendTransaction预检失败时RPC错误中的data字段
+func simulationError(data interface{}) *SimulationError {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return &SimulationError{}
+	}
+	var logs []string
+	if raw, ok := m["logs"].([]interface{}); ok {
+		for _, l := range raw {
+			if s, ok := l.(string); ok {
+				logs = append(logs, s)
+			}
+		}
+	}
+	var units uint64
+	switch v := m["unitsConsumed"].(type) {
+	case float64:
+		units = uint64(v)
+	case interface{ Int64() (int64, error) }:
+		n, _ := v.Int64()
+		units = uint64(n)
+	}
+	return NewSimulationError(m["err"], logs, units)
+}
+
+// transactionErrorName 取交易错误的枚举名：字符串形式直接返回，对象形式返回唯一的键
+func transactionErrorName(txErr interface{}) string {
+	switch v := txErr.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		for k := range v {
+			return k
+		}
+	}
+	return ""
+}
@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// 优先费与计算预算：按交易写入账户最近的优先费取分位数作为单价，
+// 模拟执行得到实际消耗的计算单元并留出余量作为上限，
+// 再把SetComputeUnitLimit/SetComputeUnitPrice放到指令最前面，网络拥堵时交易更容易被打包。
+
+const (
+	// MaxComputeUnits 单笔交易的计算单元上限
+	MaxComputeUnits = 1_400_000
+	// microLamportsPerLamport 优先费单价以百万分之一lamport为单位
+	microLamportsPerLamport = 1_000_000
+	// computeBudgetInstructionUnits 每条计算预算指令消耗的计算单元
+	computeBudgetInstructionUnits = 150
+)
+
+// FeeOptions 优先费参数
+type FeeOptions struct {
+	Percentile   int     // 取最近优先费的第几百分位，默认75
+	MinUnitPrice uint64  // 单价下限（micro-lamports），网络空闲时也至少付这么多
+	MaxUnitPrice uint64  // 单价上限（micro-lamports），0表示不限制
+	UnitMargin   float64 // 计算单元上限 = 模拟消耗 × UnitMargin，默认1.1
+}
+
+// DefaultFeeOptions 默认优先费参数
+func DefaultFeeOptions() FeeOptions {
+	return FeeOptions{
+		Percentile:   75,
+		MinUnitPrice: 1_000,
+		MaxUnitPrice: 5_000_000,
+		UnitMargin:   1.1,
+	}
+}
+
+// ComputeBudget 为一笔交易设置的计算预算
+type ComputeBudget struct {
+	UnitsConsumed uint64 // 模拟消耗的计算单元
+	UnitLimit     uint32 // SetComputeUnitLimit
+	UnitPrice     uint64 // SetComputeUnitPrice，单位micro-lamports
+}
+
+// PriorityFee 优先费（lamports），按计算单元上限计费
+func (b *ComputeBudget) PriorityFee() uint64 {
+	fee := uint64(b.UnitLimit) * b.UnitPrice
+	return (fee + microLamportsPerLamport - 1) / microLamportsPerLamport
+}
+
+func (b *ComputeBudget) String() string {
+	return fmt.Sprintf("计算单元: 消耗 %d, 上限 %d, 单价 %d micro-lamports, 优先费 %d lamports",
+		b.UnitsConsumed, b.UnitLimit, b.UnitPrice, b.PriorityFee())
+}
+
+// EstimatePriorityFee 查询写入账户最近150个slot的优先费，返回percentile分位的单价（micro-lamports）
+func EstimatePriorityFee(ctx context.Context, rpcClient *rpc.Client, writable []solana.PublicKey, percentile int) (uint64, error) {
+	fees, err := rpcClient.GetRecentPrioritizationFees(ctx, writable)
+	if err != nil {
+		return 0, fmt.Errorf("查询最近优先费失败: %w", ClassifyError(err))
+	}
+	if len(fees) == 0 {
+		return 0, nil
+	}
+	prices := make([]uint64, len(fees))
+	for i, f := range fees {
+		prices[i] = f.PrioritizationFee
+	}
+	slices.Sort(prices)
+	percentile = min(max(percentile, 0), 100)
+	idx := int(math.Ceil(float64(percentile)/100*float64(len(prices)))) - 1
+	return prices[max(idx, 0)], nil
+}
+
+// EstimateComputeUnits 以最大计算单元上限模拟执行，返回实际消耗的计算单元。
+// 模拟不校验签名并替换区块哈希，交易不需要签名；执行失败时返回*SimulationError
+func EstimateComputeUnits(ctx context.Context, rpcClient *rpc.Client, instructions []solana.Instruction, payer solana.PublicKey) (uint64, error) {
+	withLimit := append([]solana.Instruction{
+		computebudget.NewSetComputeUnitLimitInstruction(MaxComputeUnits).Build(),
+	}, instructions...)
+	tx, err := solana.NewTransaction(withLimit, solana.Hash{}, solana.TransactionPayer(payer))
+	if err != nil {
+		return 0, fmt.Errorf("构造交易失败: %v", err)
+	}
+	// 节点要求签名数量与消息头一致，关闭签名校验时填零值即可
+	tx.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+
+	res, err := rpcClient.SimulateTransactionWithOpts(ctx, tx, &rpc.SimulateTransactionOpts{
+		Commitment:             rpc.CommitmentConfirmed,
+		ReplaceRecentBlockhash: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("模拟交易失败: %w", ClassifyError(err))
+	}
+	var units uint64
+	if res.Value.UnitsConsumed != nil {
+		units = *res.Value.UnitsConsumed
+	}
+	if res.Value.Err != nil {
+		return units, NewSimulationError(res.Value.Err, res.Value.Logs, units)
+	}
+	return units, nil
+}
+
+// WithComputeBudget 估算计算单元和优先费单价，在instructions前面加上计算预算指令。
+// instructions中已经包含计算预算指令时原样返回
+func WithComputeBudget(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	instructions []solana.Instruction,
+	payer solana.PublicKey,
+	opts FeeOptions,
+) ([]solana.Instruction, *ComputeBudget, error) {
+	for _, inst := range instructions {
+		if inst.ProgramID().Equals(solana.ComputeBudget) {
+			return instructions, nil, nil
+		}
+	}
+	if opts.UnitMargin < 1 {
+		opts.UnitMargin = 1
+	}
+
+	units, err := EstimateComputeUnits(ctx, rpcClient, instructions, payer)
+	if err != nil {
+		return nil, nil, err
+	}
+	price, err := EstimatePriorityFee(ctx, rpcClient, WritableAccounts(instructions), opts.Percentile)
+	if err != nil {
+		return nil, nil, err
+	}
+	price = max(price, opts.MinUnitPrice)
+	if opts.MaxUnitPrice > 0 {
+		price = min(price, opts.MaxUnitPrice)
+	}
+
+	budget := &ComputeBudget{
+		UnitsConsumed: units,
+		// 模拟时已带一条SetComputeUnitLimit，实际交易还多一条SetComputeUnitPrice
+		UnitLimit: uint32(min(math.Ceil(float64(units+computeBudgetInstructionUnits)*opts.UnitMargin), MaxComputeUnits)),
+		UnitPrice: price,
+	}
+	return append([]solana.Instruction{
+		computebudget.NewSetComputeUnitLimitInstruction(budget.UnitLimit).Build(),
+		computebudget.NewSetComputeUnitPriceInstruction(budget.UnitPrice).Build(),
+	}, instructions...), budget, nil
+}
+
+// WritableAccounts 指令中所有可写账户（去重），优先费按这些账户的竞争程度估算
+func WritableAccounts(instructions []solana.Instruction) []solana.PublicKey {
+	var accounts []solana.PublicKey
+	seen := map[solana.PublicKey]bool{}
+	for _, inst := range instructions {
+		for _, meta := range inst.Accounts() {
+			if meta.IsWritable && !seen[meta.PublicKey] {
+				seen[meta.PublicKey] = true
+				accounts = append(accounts, meta.PublicKey)
+			}
+		}
+	}
+	// getRecentPrioritizationFees最多接受128个账户
+	if len(accounts) > 128 {
+		accounts = accounts[:128]
+	}
+	return accounts
+}
@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// 感知区块哈希过期的发送循环：已签名的交易在确认前定期重新广播，
+// 直到达到目标承诺级别或当前区块高度超过RPC返回的lastValidBlockHeight。
+// 过期后交易不可能再上链，此时可以用新的区块哈希重新签名再来一轮。
+
+// DefaultRebroadcastInterval 默认重新广播间隔
+const DefaultRebroadcastInterval = 2 * time.Second
+
+// SendAttempt 一次广播的记录
+type SendAttempt struct {
+	Attempt              int // 从1开始的广播次数
+	Round                int // 第几轮签名，0为调用方签好的交易，之后每次重新签名加1
+	Signature            solana.Signature
+	Blockhash            solana.Hash
+	LastValidBlockHeight uint64
+	BlockHeight          uint64 // 广播时的区块高度
+	Resigned             bool   // 本次是重新签名后的第一次广播
+	Err                  error  // 广播失败的原因，成功为nil
+}
+
+func (a SendAttempt) String() string {
+	s := fmt.Sprintf("第%d次广播 (第%d轮) %s 高度 %d/%d", a.Attempt, a.Round, a.Signature, a.BlockHeight, a.LastValidBlockHeight)
+	if a.Resigned {
+		s += " [已用新区块哈希重新签名]"
+	}
+	if a.Err != nil {
+		s += fmt.Sprintf(" 失败: %v", a.Err)
+	}
+	return s
+}
+
+// SendOptions 发送参数
+type SendOptions struct {
+	Commitment          rpc.CommitmentType // 等待的承诺级别，默认confirmed
+	RebroadcastInterval time.Duration      // 重新广播间隔，默认2秒
+	SkipPreflight       bool               // 第一次广播是否跳过预检，之后的重新广播总是跳过
+	// SkipSimulation 发送前不做模拟；默认先模拟，失败时不发送并返回*SimulationError
+	SkipSimulation bool
+	// IgnoreSimulationFailure 模拟失败仍然发送，例如想让失败的交易上链以便在浏览器中排查
+	IgnoreSimulationFailure bool
+	// OnSimulation 模拟完成后回调，可用于打印模拟报告
+	OnSimulation func(*SimulationReport)
+	// Signers 不为空时，区块哈希过期后用新的区块哈希重新签名，最多MaxResigns次
+	Signers    []solana.PrivateKey
+	MaxResigns int
+	// OnAttempt 每次广播后回调，可用于打印进度
+	OnAttempt func(SendAttempt)
+}
+
+// SendTransaction 发送已签名的交易并等待确认。lastValidBlockHeight应取自签名时
+// getLatestBlockhash的返回值。wsClient可以为nil，此时只靠轮询getSignatureStatuses确认
+func SendTransaction(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	tx *solana.Transaction,
+	lastValidBlockHeight uint64,
+	opts SendOptions,
+) (*ConfirmationResult, error) {
+	if opts.Commitment == "" {
+		opts.Commitment = rpc.CommitmentConfirmed
+	}
+	if opts.RebroadcastInterval <= 0 {
+		opts.RebroadcastInterval = DefaultRebroadcastInterval
+	}
+	if err := CheckFullySigned(tx); err != nil {
+		return nil, err
+	}
+	if !opts.SkipSimulation {
+		if err := simulateBeforeSend(ctx, rpcClient, tx, opts); err != nil {
+			return &ConfirmationResult{Signature: tx.Signatures[0], Commitment: opts.Commitment}, err
+		}
+	}
+
+	attempt := 0
+	for round := 0; ; round++ {
+		result, err := sendUntilExpired(ctx, rpcClient, wsClient, tx, lastValidBlockHeight, round, &attempt, opts)
+		if !errors.Is(err, ErrBlockhashExpired) {
+			return result, err
+		}
+		if round >= opts.MaxResigns || len(opts.Signers) == 0 {
+			return result, err
+		}
+
+		latest, err := rpcClient.GetLatestBlockhash(ctx, opts.Commitment)
+		if err != nil {
+			return result, fmt.Errorf("获取区块哈希失败: %w", err)
+		}
+		tx.Message.RecentBlockhash = latest.Value.Blockhash
+		lastValidBlockHeight = latest.Value.LastValidBlockHeight
+		if err := resign(tx, opts.Signers); err != nil {
+			return result, err
+		}
+	}
+}
+
+// sendUntilExpired 在当前区块哈希的有效期内反复广播同一笔交易
+func sendUntilExpired(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	tx *solana.Transaction,
+	lastValidBlockHeight uint64,
+	round int,
+	attempt *int,
+	opts SendOptions,
+) (*ConfirmationResult, error) {
+	signature := tx.Signatures[0]
+	result := &ConfirmationResult{Signature: signature, Commitment: opts.Commitment}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	notified := make(chan *ws.SignatureResult, 1)
+	if wsClient != nil {
+		if sub, err := wsClient.SignatureSubscribe(signature, opts.Commitment); err == nil {
+			defer sub.Unsubscribe()
+			go func() {
+				if res, err := sub.Recv(subCtx); err == nil {
+					notified <- res
+				}
+			}()
+		}
+	}
+
+	ticker := time.NewTicker(opts.RebroadcastInterval)
+	defer ticker.Stop()
+	for first := true; ; first = false {
+		height, err := rpcClient.GetBlockHeight(ctx, opts.Commitment)
+		if err != nil {
+			return result, fmt.Errorf("获取区块高度失败: %w", err)
+		}
+		if height > lastValidBlockHeight {
+			// 过期前的最后一次广播可能刚好上链，再查一次状态
+			if done, err := pollSignatureStatus(ctx, rpcClient, result); done || err != nil {
+				return result, err
+			}
+			if result.Status != "" {
+				// 已被处理但还没达到目标级别，交易不会因为过期而丢失
+				return WaitForConfirmation(ctx, rpcClient, wsClient, signature, opts.Commitment, 0)
+			}
+			return result, fmt.Errorf("%w: %s 当前高度 %d 超过 %d", ErrBlockhashExpired, signature, height, lastValidBlockHeight)
+		}
+
+		*attempt++
+		_, sendErr := rpcClient.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{
+			SkipPreflight:       !first || opts.SkipPreflight,
+			PreflightCommitment: opts.Commitment,
+			MaxRetries:          new(uint),
+		})
+		sendErr = ClassifyError(sendErr)
+		if opts.OnAttempt != nil {
+			opts.OnAttempt(SendAttempt{
+				Attempt:              *attempt,
+				Round:                round,
+				Signature:            signature,
+				Blockhash:            tx.Message.RecentBlockhash,
+				LastValidBlockHeight: lastValidBlockHeight,
+				BlockHeight:          height,
+				Resigned:             first && round > 0,
+				Err:                  sendErr,
+			})
+		}
+		// 预检失败说明交易本身有问题，重发没有意义；节点落后导致的区块哈希未找到等可重试错误继续广播
+		if first && sendErr != nil && !opts.SkipPreflight &&
+			!IsRetryable(sendErr) && !errors.Is(sendErr, ErrAlreadyProcessed) {
+			return result, fmt.Errorf("发送交易失败: %w", sendErr)
+		}
+
+		if done, err := pollSignatureStatus(ctx, rpcClient, result); done || err != nil {
+			return result, err
+		}
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case res := <-notified:
+			result.Slot = res.Context.Slot
+			result.Status = rpc.ConfirmationStatusType(opts.Commitment)
+			result.Err = res.Value.Err
+			return result, result.err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// resign 清空旧签名，用signers重新签名
+func resign(tx *solana.Transaction, signers []solana.PrivateKey) error {
+	tx.Signatures = nil
+	_, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		for i := range signers {
+			if signers[i].PublicKey().Equals(key) {
+				return &signers[i]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("重新签名交易失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"solana-go/mockrpc"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	testBalance  = 10 * solana.LAMPORTS_PER_SOL
+	testAmount   = solana.LAMPORTS_PER_SOL
+	testValidity = 6 // 区块哈希有效块数，每轮最多广播testValidity+1次
+)
+
+// testNode 模拟节点和一个有余额的付款账户
+type testNode struct {
+	srv   *mockrpc.Server
+	rpc   *rpc.Client
+	payer solana.PrivateKey
+	to    solana.PublicKey
+}
+
+func newTestNode(t *testing.T, opts mockrpc.Options) *testNode {
+	t.Helper()
+	srv := mockrpc.NewServer(opts)
+	t.Cleanup(srv.Close)
+	n := &testNode{
+		srv:   srv,
+		rpc:   rpc.New(srv.URL),
+		payer: solana.NewWallet().PrivateKey,
+		to:    solana.NewWallet().PublicKey(),
+	}
+	srv.SetBalance(n.payer.PublicKey(), testBalance)
+	return n
+}
+
+// transfer 构造并签名一笔转账，返回交易和lastValidBlockHeight
+func (n *testNode) transfer(t *testing.T, amount uint64) (*solana.Transaction, uint64) {
+	t.Helper()
+	blockhash, lastValid, err := GetRecentBlockhash(n.rpc, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, missing, err := NewTxBuilder(n.payer.PublicKey(), blockhash).
+		Add(system.NewTransferInstruction(amount, n.payer.PublicKey(), n.to).Build()).
+		Sign(NewKeySigner(n.payer)).
+		Build()
+	if err != nil || len(missing) > 0 {
+		t.Fatalf("构造交易失败: %v 缺少签名 %v", err, missing)
+	}
+	return tx, lastValid
+}
+
+// sendOptions 每次广播后出一个块，广播次数只取决于区块哈希有效期
+func (n *testNode) sendOptions(attempts *[]SendAttempt) SendOptions {
+	return SendOptions{
+		RebroadcastInterval: time.Millisecond,
+		OnAttempt: func(a SendAttempt) {
+			*attempts = append(*attempts, a)
+			n.srv.Advance(1)
+		},
+	}
+}
+
+func TestSendTransactionBlockhashNotFound(t *testing.T) {
+	n := newTestNode(t, mockrpc.Options{})
+	n.srv.FailNext("sendTransaction", mockrpc.BlockhashNotFound())
+	tx, lastValid := n.transfer(t, testAmount)
+
+	var attempts []SendAttempt
+	result, err := SendTransaction(context.Background(), n.rpc, nil, tx, lastValid, n.sendOptions(&attempts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 节点找不到区块哈希属于可重试错误，第一次失败后继续广播
+	if len(attempts) != 2 || !errors.Is(attempts[0].Err, ErrBlockhashExpired) || attempts[1].Err != nil {
+		t.Fatalf("广播记录: %v", attempts)
+	}
+	if result.Status != rpc.ConfirmationStatusConfirmed || result.Signature != tx.Signatures[0] {
+		t.Fatalf("确认结果: %+v", result)
+	}
+	if got := n.srv.Balance(n.to); got != testAmount {
+		t.Fatalf("收款账户余额 %d，期望 %d", got, testAmount)
+	}
+}
+
+func TestSendTransactionPreflightFailure(t *testing.T) {
+	n := newTestNode(t, mockrpc.Options{})
+	n.srv.FailNext("sendTransaction", mockrpc.SignatureVerificationFailure())
+	tx, lastValid := n.transfer(t, testAmount)
+
+	var attempts []SendAttempt
+	_, err := SendTransaction(context.Background(), n.rpc, nil, tx, lastValid, n.sendOptions(&attempts))
+	// 不可重试的错误在第一次广播后直接返回
+	if !errors.Is(err, ErrSignatureVerification) || len(attempts) != 1 {
+		t.Fatalf("err = %v，广播 %d 次", err, len(attempts))
+	}
+}
+
+func TestSendTransactionRebroadcastUntilExpired(t *testing.T) {
+	n := newTestNode(t, mockrpc.Options{BlockhashValidity: testValidity})
+	n.srv.DropNext(100)
+	tx, lastValid := n.transfer(t, testAmount)
+	start := n.srv.BlockHeight()
+
+	var attempts []SendAttempt
+	_, err := SendTransaction(context.Background(), n.rpc, nil, tx, lastValid, n.sendOptions(&attempts))
+	if !errors.Is(err, ErrBlockhashExpired) {
+		t.Fatalf("err = %v，期望区块哈希过期", err)
+	}
+	// 从签名时的高度一直广播到lastValidBlockHeight，没有Signers时不重新签名
+	if want := int(lastValid - start + 1); len(attempts) != want {
+		t.Fatalf("广播 %d 次，期望 %d 次", len(attempts), want)
+	}
+	for i, a := range attempts {
+		if a.Round != 0 || a.Signature != tx.Signatures[0] || a.BlockHeight > lastValid {
+			t.Fatalf("第%d次广播: %v", i+1, a)
+		}
+	}
+	if last := attempts[len(attempts)-1]; last.BlockHeight != lastValid {
+		t.Fatalf("最后一次广播在高度 %d，期望 %d", last.BlockHeight, lastValid)
+	}
+	if landed, _ := n.srv.Landed(tx.Signatures[0]); landed {
+		t.Fatal("被丢弃的交易不应上链")
+	}
+}
+
+func TestSendTransactionResign(t *testing.T) {
+	const maxResigns = 2
+	tests := []struct {
+		name      string
+		drop      int
+		wantErr   error
+		wantRound int
+	}{
+		// 每轮广播testValidity+1次，丢弃数量超过所有轮次时重新签名用完后过期
+		{name: "重新签名次数用完", drop: 100, wantErr: ErrBlockhashExpired, wantRound: maxResigns},
+		{name: "重新签名后上链", drop: testValidity + 3, wantRound: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestNode(t, mockrpc.Options{BlockhashValidity: testValidity})
+			n.srv.DropNext(tt.drop)
+			tx, lastValid := n.transfer(t, testAmount)
+			first := tx.Signatures[0]
+
+			var attempts []SendAttempt
+			opts := n.sendOptions(&attempts)
+			opts.Signers = []solana.PrivateKey{n.payer}
+			opts.MaxResigns = maxResigns
+			result, err := SendTransaction(context.Background(), n.rpc, nil, tx, lastValid, opts)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("err = %v，期望 %v", err, tt.wantErr)
+			}
+
+			last := attempts[len(attempts)-1]
+			if last.Round != tt.wantRound {
+				t.Fatalf("最后一次广播在第%d轮，期望第%d轮", last.Round, tt.wantRound)
+			}
+			// 初始签名和每次重新签名各取一次区块哈希
+			if got := n.srv.Calls("getLatestBlockhash"); got != tt.wantRound+1 {
+				t.Fatalf("getLatestBlockhash调用 %d 次，期望 %d 次", got, tt.wantRound+1)
+			}
+			signatures := map[solana.Signature]bool{}
+			for _, a := range attempts {
+				if a.Resigned != (a.Round > 0 && !signatures[a.Signature]) {
+					t.Fatalf("Resigned标记错误: %v", a)
+				}
+				signatures[a.Signature] = true
+			}
+			if len(signatures) != tt.wantRound+1 || !signatures[first] {
+				t.Fatalf("签名 %d 个，期望 %d 个", len(signatures), tt.wantRound+1)
+			}
+			if tt.wantErr == nil {
+				if result.Signature == first || result.Signature != tx.Signatures[0] {
+					t.Fatalf("结果签名 %s 应为重新签名后的 %s", result.Signature, tx.Signatures[0])
+				}
+				if landed, txErr := n.srv.Landed(result.Signature); !landed || txErr != nil {
+					t.Fatalf("交易未上链: %v", txErr)
+				}
+			}
+		})
+	}
+}
+
+func TestTransferSOL(t *testing.T) {
+	n := newTestNode(t, mockrpc.Options{AutoAdvance: true})
+	n.srv.FailNext("sendTransaction", mockrpc.BlockhashNotFound())
+	blockhash, lastValid, err := GetRecentBlockhash(n.rpc, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var attempts []SendAttempt
+	var simulated bool
+	signature, err := TransferSOL(n.rpc, nil, n.payer, n.to, testAmount, blockhash, lastValid, SendOptions{
+		RebroadcastInterval: time.Millisecond,
+		OnSimulation:        func(*SimulationReport) { simulated = true },
+		OnAttempt:           func(a SendAttempt) { attempts = append(attempts, a) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !simulated || len(attempts) != 2 || attempts[1].Signature != signature {
+		t.Fatalf("模拟 %v，广播记录: %v", simulated, attempts)
+	}
+	if got := n.srv.Balance(n.to); got != testAmount {
+		t.Fatalf("收款账户余额 %d，期望 %d", got, testAmount)
+	}
+
+	// 余额不足时模拟失败，交易不会发送
+	sends := n.srv.Calls("sendTransaction")
+	blockhash, lastValid, err = GetRecentBlockhash(n.rpc, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = TransferSOL(n.rpc, nil, n.payer, n.to, testBalance, blockhash, lastValid, SendOptions{})
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("err = %v，期望余额不足", err)
+	}
+	if n.srv.Calls("sendTransaction") != sends {
+		t.Fatal("模拟失败后不应发送交易")
+	}
+}
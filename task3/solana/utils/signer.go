@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"solana-go/wallet"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// 多方签名：交易的签名者可以来自不同的地方（内存中的私钥、密钥文件、硬件钱包或远程签名服务），
+// 一方签完自己的部分后把交易序列化为base64交给另一方，对方用同一份消息补上签名；
+// 各方分别签名的副本也可以合并。所有签名齐全且有效后才能广播
+
+// ErrMissingSignatures 交易还缺少签名
+var ErrMissingSignatures = errors.New("交易缺少签名")
+
+// Signer 交易签名者
+type Signer interface {
+	PublicKey() solana.PublicKey
+	// SignMessage 对序列化后的交易消息签名
+	SignMessage(message []byte) (solana.Signature, error)
+}
+
+// keySigner 内存中的私钥
+type keySigner struct {
+	key solana.PrivateKey
+}
+
+// NewKeySigner 用私钥签名
+func NewKeySigner(key solana.PrivateKey) Signer {
+	return keySigner{key: key}
+}
+
+func (s keySigner) PublicKey() solana.PublicKey {
+	return s.key.PublicKey()
+}
+
+func (s keySigner) SignMessage(message []byte) (solana.Signature, error) {
+	return s.key.Sign(message)
+}
+
+// NewFileSigner 读取solana-keygen格式或加密的密钥文件，passphrase只用于加密的文件
+func NewFileSigner(path, passphrase string) (Signer, error) {
+	key, err := wallet.Load(path, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("读取签名者密钥 %s 失败: %w", path, err)
+	}
+	return NewKeySigner(key), nil
+}
+
+// externalSigner 私钥不在本进程的签名者
+type externalSigner struct {
+	pubkey solana.PublicKey
+	sign   func(message []byte) (solana.Signature, error)
+}
+
+// NewExternalSigner 由sign完成签名，例如调用硬件钱包或远程签名服务；签名结果会用pubkey校验
+func NewExternalSigner(pubkey solana.PublicKey, sign func(message []byte) (solana.Signature, error)) Signer {
+	return externalSigner{pubkey: pubkey, sign: sign}
+}
+
+func (s externalSigner) PublicKey() solana.PublicKey {
+	return s.pubkey
+}
+
+func (s externalSigner) SignMessage(message []byte) (solana.Signature, error) {
+	sig, err := s.sign(message)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	if !sig.Verify(s.pubkey, message) {
+		return solana.Signature{}, fmt.Errorf("外部签名者返回的签名与公钥 %s 不匹配", s.pubkey)
+	}
+	return sig, nil
+}
+
+// PartialSign 用signers补上交易中各自的签名，其他签名保持不变。
+// signer不是交易的签名者时报错，防止签错交易
+func PartialSign(tx *solana.Transaction, signers ...Signer) error {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("序列化交易消息失败: %v", err)
+	}
+	required := tx.Message.Signers()
+	if len(tx.Signatures) == 0 {
+		tx.Signatures = make([]solana.Signature, len(required))
+	} else if len(tx.Signatures) != len(required) {
+		return fmt.Errorf("交易有 %d 个签名位，但需要 %d 个签名者", len(tx.Signatures), len(required))
+	}
+	for _, signer := range signers {
+		idx := slices.Index(required, signer.PublicKey())
+		if idx < 0 {
+			return fmt.Errorf("%s 不是这笔交易的签名者", signer.PublicKey())
+		}
+		sig, err := signer.SignMessage(message)
+		if err != nil {
+			return fmt.Errorf("%s 签名失败: %v", signer.PublicKey(), err)
+		}
+		tx.Signatures[idx] = sig
+	}
+	return nil
+}
+
+// SignatureState 一个签名者的签名状态
+type SignatureState struct {
+	Signer solana.PublicKey
+	Signed bool // 已有签名
+	Valid  bool // 签名能通过校验
+}
+
+// SignatureStates 检查交易每个签名者的签名，顺序与消息中的签名者一致
+func SignatureStates(tx *solana.Transaction) ([]SignatureState, error) {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("序列化交易消息失败: %v", err)
+	}
+	required := tx.Message.Signers()
+	if len(tx.Signatures) != 0 && len(tx.Signatures) != len(required) {
+		return nil, fmt.Errorf("交易有 %d 个签名位，但需要 %d 个签名者", len(tx.Signatures), len(required))
+	}
+	states := make([]SignatureState, len(required))
+	for i, key := range required {
+		states[i].Signer = key
+		if i < len(tx.Signatures) && !tx.Signatures[i].IsZero() {
+			states[i].Signed = true
+			states[i].Valid = tx.Signatures[i].Verify(key, message)
+		}
+	}
+	return states, nil
+}
+
+// MissingSigners 还没有签名的签名者；存在无效签名时返回错误，通常是签名后交易又被修改
+func MissingSigners(tx *solana.Transaction) ([]solana.PublicKey, error) {
+	states, err := SignatureStates(tx)
+	if err != nil {
+		return nil, err
+	}
+	var missing []solana.PublicKey
+	for _, s := range states {
+		if !s.Signed {
+			missing = append(missing, s.Signer)
+		} else if !s.Valid {
+			return nil, fmt.Errorf("%w: %s 的签名无效", ErrSignatureVerification, s.Signer)
+		}
+	}
+	return missing, nil
+}
+
+// CheckFullySigned 广播前检查：所有签名齐全且有效
+func CheckFullySigned(tx *solana.Transaction) error {
+	missing, err := MissingSigners(tx)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingSignatures, joinKeys(missing))
+	}
+	return nil
+}
+
+// MergeSignatures 把others中的签名合并到tx。各副本的消息必须完全相同，
+// 只合并能通过校验的签名，tx中已有的有效签名不会被覆盖
+func MergeSignatures(tx *solana.Transaction, others ...*solana.Transaction) error {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("序列化交易消息失败: %v", err)
+	}
+	required := tx.Message.Signers()
+	if len(tx.Signatures) == 0 {
+		tx.Signatures = make([]solana.Signature, len(required))
+	} else if len(tx.Signatures) != len(required) {
+		return fmt.Errorf("交易有 %d 个签名位，但需要 %d 个签名者", len(tx.Signatures), len(required))
+	}
+	for n, other := range others {
+		otherMessage, err := other.Message.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("序列化第%d份交易的消息失败: %v", n+1, err)
+		}
+		if string(otherMessage) != string(message) {
+			return fmt.Errorf("第%d份交易的消息与原交易不同，不能合并签名", n+1)
+		}
+		for i, sig := range other.Signatures {
+			if i >= len(required) || sig.IsZero() {
+				continue
+			}
+			if !sig.Verify(required[i], message) {
+				return fmt.Errorf("%w: 第%d份交易中 %s 的签名无效", ErrSignatureVerification, n+1, required[i])
+			}
+			if tx.Signatures[i].IsZero() || !tx.Signatures[i].Verify(required[i], message) {
+				tx.Signatures[i] = sig
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeTransaction 把交易（可以是部分签名的）序列化为base64，未签名的位置为全零。不修改tx
+func EncodeTransaction(tx *solana.Transaction) (string, error) {
+	if len(tx.Signatures) == 0 {
+		// 序列化格式要求签名数与消息头一致，未签名的交易在副本上补零值
+		unsigned := *tx
+		unsigned.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+		tx = &unsigned
+	}
+	s, err := tx.ToBase64()
+	if err != nil {
+		return "", fmt.Errorf("序列化交易失败: %v", err)
+	}
+	return s, nil
+}
+
+// DecodeTransaction 解析EncodeTransaction的输出，忽略空白和换行
+func DecodeTransaction(s string) (*solana.Transaction, error) {
+	tx, err := solana.TransactionFromBase64(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return nil, fmt.Errorf("解析交易失败: %v", err)
+	}
+	return tx, nil
+}
+
+func joinKeys(keys []solana.PublicKey) string {
+	s := make([]string, len(keys))
+	for i, k := range keys {
+		s[i] = k.String()
+	}
+	return strings.Join(s, ", ")
+}
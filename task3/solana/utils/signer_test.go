@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+// multisigTx 离线构造一笔需要两个签名者的交易：payer付手续费，from转出SOL。
+// 交易只签名、不发送，区块哈希随便取
+type multisigTx struct {
+	payer, from solana.PrivateKey
+	blockhash   solana.Hash
+}
+
+func newMultisigTx() *multisigTx {
+	return &multisigTx{
+		payer:     solana.NewWallet().PrivateKey,
+		from:      solana.NewWallet().PrivateKey,
+		blockhash: solana.HashFromBytes([]byte("signer-test-blockhash-0000000000")),
+	}
+}
+
+func (m *multisigTx) build(t *testing.T, amount uint64, signers ...Signer) *solana.Transaction {
+	t.Helper()
+	tx, _, err := NewTxBuilder(m.payer.PublicKey(), m.blockhash).
+		Add(system.NewTransferInstruction(amount, m.from.PublicKey(), solana.NewWallet().PublicKey()).Build()).
+		Sign(signers...).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// roundTrip 序列化后再解析，模拟把交易交给另一方
+func roundTrip(t *testing.T, tx *solana.Transaction) *solana.Transaction {
+	t.Helper()
+	s, err := EncodeTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeTransaction(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestPartialSignAndMerge(t *testing.T) {
+	m := newMultisigTx()
+	tx := m.build(t, 1000)
+	if missing, err := MissingSigners(tx); err != nil || len(missing) != 2 {
+		t.Fatalf("未签名交易缺少 %v, %v", missing, err)
+	}
+
+	// 两方各自拿到未签名的交易，分别签名
+	payerCopy, fromCopy := roundTrip(t, tx), roundTrip(t, tx)
+	if err := PartialSign(payerCopy, NewKeySigner(m.payer)); err != nil {
+		t.Fatal(err)
+	}
+	if err := PartialSign(fromCopy, NewKeySigner(m.from)); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckFullySigned(payerCopy); !errors.Is(err, ErrMissingSignatures) || !strings.Contains(err.Error(), m.from.PublicKey().String()) {
+		t.Fatalf("只有付款方签名时 err = %v", err)
+	}
+	if err := PartialSign(payerCopy, NewKeySigner(solana.NewWallet().PrivateKey)); err == nil {
+		t.Fatal("不是签名者的私钥也能签名")
+	}
+
+	// 合并两份签名后齐全
+	merged := roundTrip(t, tx)
+	if err := MergeSignatures(merged, roundTrip(t, payerCopy), roundTrip(t, fromCopy)); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckFullySigned(merged); err != nil {
+		t.Fatal(err)
+	}
+	if err := merged.VerifySignatures(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 依次补签得到同样的签名
+	chained := roundTrip(t, payerCopy)
+	if err := PartialSign(chained, NewKeySigner(m.from)); err != nil {
+		t.Fatal(err)
+	}
+	for i := range merged.Signatures {
+		if chained.Signatures[i] != merged.Signatures[i] {
+			t.Fatalf("第%d个签名不同", i)
+		}
+	}
+}
+
+func TestMergeSignaturesRejects(t *testing.T) {
+	m := newMultisigTx()
+	payerSigned := m.build(t, 1000, NewKeySigner(m.payer))
+	want := payerSigned.Signatures[0]
+
+	// 对另一笔交易（金额不同）的签名不能合并
+	other := m.build(t, 2000, NewKeySigner(m.from))
+	if err := MergeSignatures(payerSigned, other); err == nil || !strings.Contains(err.Error(), "消息与原交易不同") {
+		t.Fatalf("合并不同消息的签名 err = %v", err)
+	}
+
+	// 消息相同但签名无效
+	forged := roundTrip(t, payerSigned)
+	forged.Signatures[1] = other.Signatures[1]
+	if err := MergeSignatures(payerSigned, forged); !errors.Is(err, ErrSignatureVerification) {
+		t.Fatalf("合并无效签名 err = %v", err)
+	}
+	if !payerSigned.Signatures[1].IsZero() || payerSigned.Signatures[0] != want {
+		t.Fatalf("合并失败后签名被修改: %v", payerSigned.Signatures)
+	}
+	if _, err := MissingSigners(forged); !errors.Is(err, ErrSignatureVerification) {
+		t.Fatalf("无效签名 err = %v", err)
+	}
+}
+
+func TestEncodeDecodeTransaction(t *testing.T) {
+	m := newMultisigTx()
+	tx := m.build(t, 1000, NewKeySigner(m.from))
+	s, err := EncodeTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 复制粘贴时带上的换行和空格不影响解析
+	wrapped := s[:20] + "\n  " + s[20:] + "\n"
+	decoded, err := DecodeTransaction(wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := tx.Message.MarshalBinary()
+	got, _ := decoded.Message.MarshalBinary()
+	if string(got) != string(want) {
+		t.Fatal("解析后的消息与原交易不同")
+	}
+	states, err := SignatureStates(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 || states[0].Signer != m.payer.PublicKey() || states[0].Signed ||
+		states[1].Signer != m.from.PublicKey() || !states[1].Signed || !states[1].Valid {
+		t.Fatalf("签名状态: %+v", states)
+	}
+	if _, err := DecodeTransaction("not base64"); err == nil {
+		t.Fatal("无效输入应报错")
+	}
+
+	// 没有签名位的交易序列化时补零值，但不修改调用方的交易
+	unsigned, err := solana.NewTransaction([]solana.Instruction{
+		system.NewTransferInstruction(1000, m.payer.PublicKey(), m.from.PublicKey()).Build(),
+	}, m.blockhash, solana.TransactionPayer(m.payer.PublicKey()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded := roundTrip(t, unsigned); len(unsigned.Signatures) != 0 || len(decoded.Signatures) != 1 || !decoded.Signatures[0].IsZero() {
+		t.Fatalf("原交易签名 %v，解析后 %v", unsigned.Signatures, decoded.Signatures)
+	}
+}
+
+func TestExternalSigner(t *testing.T) {
+	m := newMultisigTx()
+	tx := m.build(t, 1000)
+	// 远程签名服务返回了别的私钥的签名
+	wrong := NewExternalSigner(m.from.PublicKey(), func(message []byte) (solana.Signature, error) {
+		return m.payer.Sign(message)
+	})
+	if err := PartialSign(tx, wrong); err == nil || !strings.Contains(err.Error(), "不匹配") {
+		t.Fatalf("err = %v", err)
+	}
+	right := NewExternalSigner(m.from.PublicKey(), func(message []byte) (solana.Signature, error) {
+		return m.from.Sign(message)
+	})
+	if err := PartialSign(tx, right); err != nil {
+		t.Fatal(err)
+	}
+	if missing, err := MissingSigners(tx); err != nil || len(missing) != 1 || missing[0] != m.payer.PublicKey() {
+		t.Fatalf("缺少 %v, %v", missing, err)
+	}
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// 发送前的模拟预检：用simulateTransaction执行已签名的交易，报告程序日志、消耗的计算单元、
+// 可写账户在交易前后的余额以及归类后的错误。SendTransaction默认在第一次广播前模拟，
+// 模拟失败时不发送，避免为注定失败的交易支付手续费
+
+// SimulateOptions 模拟参数
+type SimulateOptions struct {
+	Commitment rpc.CommitmentType // 模拟所基于的承诺级别，默认confirmed
+	// SigVerify 校验签名，与ReplaceRecentBlockhash互斥（节点的限制）
+	SigVerify bool
+	// ReplaceRecentBlockhash 用节点最新的区块哈希替换交易中的，交易未签名或区块哈希可能已过期时使用
+	ReplaceRecentBlockhash bool
+	// Accounts 需要报告余额变化的账户，默认为交易中所有可写账户
+	Accounts []solana.PublicKey
+}
+
+// SimulationReport 模拟结果
+type SimulationReport struct {
+	Slot          uint64        // 模拟所基于的slot
+	Err           interface{}   // 节点返回的交易错误，成功为nil
+	Logs          []string      // 程序日志，交易未能开始执行时为空
+	UnitsConsumed uint64        // 消耗的计算单元
+	Balances      []BalanceDiff // Accounts中各账户模拟前后的余额
+}
+
+// BalanceDiff 账户模拟前后的余额（lamports），账户不存在时为0
+type BalanceDiff struct {
+	Account solana.PublicKey
+	Pre     uint64
+	Post    uint64
+}
+
+// Delta 余额变化量
+func (d BalanceDiff) Delta() int64 {
+	return int64(d.Post) - int64(d.Pre)
+}
+
+// OK 模拟是否执行成功
+func (r *SimulationReport) OK() bool {
+	return r.Err == nil
+}
+
+// Error 模拟失败时返回*SimulationError，可用errors.Is判断ErrInsufficientFunds等；成功返回nil
+func (r *SimulationReport) Error() error {
+	if r.Err == nil {
+		return nil
+	}
+	return NewSimulationError(r.Err, r.Logs, r.UnitsConsumed)
+}
+
+func (r *SimulationReport) String() string {
+	var b strings.Builder
+	status := "成功"
+	if err := r.Error(); err != nil {
+		status = "失败: " + fmt.Sprint(r.Err)
+		if kind := TransactionErrorKind(r.Err, r.Logs); kind != nil {
+			status += fmt.Sprintf("（%v）", kind)
+		}
+	}
+	fmt.Fprintf(&b, "模拟%s，slot %d，消耗计算单元 %d", status, r.Slot, r.UnitsConsumed)
+	for _, d := range r.Balances {
+		fmt.Fprintf(&b, "\n  %s %s -> %s SOL", d.Account, FormatTokenAmount(d.Pre, 9), FormatTokenAmount(d.Post, 9))
+		if delta := d.Delta(); delta < 0 {
+			fmt.Fprintf(&b, " (-%s)", FormatTokenAmount(uint64(-delta), 9))
+		} else if delta > 0 {
+			fmt.Fprintf(&b, " (+%s)", FormatTokenAmount(uint64(delta), 9))
+		}
+	}
+	if len(r.Logs) > 0 {
+		fmt.Fprintf(&b, "\n程序日志:\n  %s", strings.Join(r.Logs, "\n  "))
+	}
+	return b.String()
+}
+
+// Simulate 模拟执行交易。模拟本身失败（如节点不可用、签名校验失败）时返回error；
+// 交易执行失败不算error，见SimulationReport.Err和SimulationReport.Error()
+func Simulate(ctx context.Context, rpcClient *rpc.Client, tx *solana.Transaction, opts SimulateOptions) (*SimulationReport, error) {
+	if opts.SigVerify && opts.ReplaceRecentBlockhash {
+		return nil, fmt.Errorf("SigVerify与ReplaceRecentBlockhash不能同时使用")
+	}
+	if opts.Commitment == "" {
+		opts.Commitment = rpc.CommitmentConfirmed
+	}
+	if opts.Accounts == nil {
+		accounts, err := writableKeys(tx)
+		if err != nil {
+			return nil, err
+		}
+		opts.Accounts = accounts
+	}
+	if len(tx.Signatures) == 0 {
+		// 节点要求签名数量与消息头一致，不校验签名时填零值即可
+		tx = shallowCopy(tx)
+		tx.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+	}
+
+	// 模拟前的余额单独查询，simulateTransaction只返回执行后的账户状态
+	var pre []*rpc.Account
+	if len(opts.Accounts) > 0 {
+		res, err := rpcClient.GetMultipleAccountsWithOpts(ctx, opts.Accounts, &rpc.GetMultipleAccountsOpts{
+			Commitment: opts.Commitment,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("查询账户余额失败: %w", ClassifyError(err))
+		}
+		pre = res.Value
+	}
+
+	simOpts := &rpc.SimulateTransactionOpts{
+		SigVerify:              opts.SigVerify,
+		Commitment:             opts.Commitment,
+		ReplaceRecentBlockhash: opts.ReplaceRecentBlockhash,
+	}
+	if len(opts.Accounts) > 0 {
+		simOpts.Accounts = &rpc.SimulateTransactionAccountsOpts{
+			Encoding:  solana.EncodingBase64,
+			Addresses: opts.Accounts,
+		}
+	}
+	res, err := rpcClient.SimulateTransactionWithOpts(ctx, tx, simOpts)
+	if err != nil {
+		return nil, fmt.Errorf("模拟交易失败: %w", ClassifyError(err))
+	}
+
+	report := &SimulationReport{Slot: res.Context.Slot, Err: res.Value.Err, Logs: res.Value.Logs}
+	if res.Value.UnitsConsumed != nil {
+		report.UnitsConsumed = *res.Value.UnitsConsumed
+	}
+	// 交易未能开始执行时节点不返回账户状态，余额视为不变
+	post := res.Value.Accounts
+	for i, account := range opts.Accounts {
+		diff := BalanceDiff{Account: account, Pre: lamportsAt(pre, i)}
+		diff.Post = diff.Pre
+		if len(post) == len(opts.Accounts) {
+			diff.Post = lamportsAt(post, i)
+		}
+		report.Balances = append(report.Balances, diff)
+	}
+	return report, nil
+}
+
+// writableKeys 消息中的可写账户，包括付款账户
+func writableKeys(tx *solana.Transaction) ([]solana.PublicKey, error) {
+	var keys []solana.PublicKey
+	for i, key := range tx.Message.AccountKeys {
+		writable, err := tx.Message.IsWritable(key)
+		if err != nil {
+			return nil, fmt.Errorf("解析交易账户 #%d 失败: %v", i, err)
+		}
+		if writable {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func shallowCopy(tx *solana.Transaction) *solana.Transaction {
+	c := *tx
+	return &c
+}
+
+func lamportsAt(accounts []*rpc.Account, i int) uint64 {
+	if i >= len(accounts) || accounts[i] == nil {
+		return 0
+	}
+	return accounts[i].Lamports
+}
+
+// simulateBeforeSend SendTransaction的模拟预检：校验签名后模拟，失败时返回*SimulationError。
+// 区块哈希已过期时不拦截，交给发送循环重新签名
+func simulateBeforeSend(ctx context.Context, rpcClient *rpc.Client, tx *solana.Transaction, opts SendOptions) error {
+	report, err := Simulate(ctx, rpcClient, tx, SimulateOptions{Commitment: opts.Commitment, SigVerify: true})
+	if err != nil {
+		return err
+	}
+	if opts.OnSimulation != nil {
+		opts.OnSimulation(report)
+	}
+	simErr := report.Error()
+	if simErr == nil || errors.Is(simErr, ErrBlockhashExpired) || opts.IgnoreSimulationFailure {
+		return nil
+	}
+	return simErr
+}
@@ -0,0 +1,471 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// SPL代币账户管理：关联代币账户(ATA)的推导与幂等创建、mint信息、
+// 持有者的代币账户列表、按精度校验的转账，以及关闭空账户回收租金。
+// 同时支持Token和Token-2022程序，程序ID取自mint账户的owner。
+
+const (
+	// mintLen和tokenAccountLen为Token程序的账户长度，Token-2022的扩展数据跟在后面
+	mintLen         = 82
+	tokenAccountLen = 165
+
+	// closeBatchSize 每笔交易最多关闭的账户数，避免超过交易大小限制
+	closeBatchSize = 20
+)
+
+// MintInfo 代币mint账户信息
+type MintInfo struct {
+	Address         solana.PublicKey
+	ProgramID       solana.PublicKey // Token或Token-2022
+	Decimals        uint8
+	Supply          uint64
+	MintAuthority   *solana.PublicKey
+	FreezeAuthority *solana.PublicKey
+}
+
+// FormatAmount 按mint精度格式化最小单位的数量
+func (m *MintInfo) FormatAmount(amount uint64) string {
+	return FormatTokenAmount(amount, m.Decimals)
+}
+
+// TokenAccount 解析后的代币账户
+type TokenAccount struct {
+	Address   solana.PublicKey
+	ProgramID solana.PublicKey
+	Mint      solana.PublicKey
+	Owner     solana.PublicKey
+	Amount    uint64
+	Decimals  uint8
+	Lamports  uint64 // 账户租金，关闭后返还
+	State     token.AccountState
+	IsNative  bool // 包装SOL账户
+}
+
+// UIAmount 按精度格式化的余额
+func (a *TokenAccount) UIAmount() string {
+	return FormatTokenAmount(a.Amount, a.Decimals)
+}
+
+// FindAssociatedTokenAddress 推导owner在mint下的关联代币账户地址，
+// tokenProgramID为零值时按Token程序推导
+func FindAssociatedTokenAddress(owner, mint, tokenProgramID solana.PublicKey) (solana.PublicKey, error) {
+	if tokenProgramID.IsZero() {
+		tokenProgramID = solana.TokenProgramID
+	}
+	ata, _, err := solana.FindProgramAddress(
+		[][]byte{owner[:], tokenProgramID[:], mint[:]},
+		solana.SPLAssociatedTokenAccountProgramID,
+	)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("推导关联代币账户失败: %v", err)
+	}
+	return ata, nil
+}
+
+// NewCreateIdempotentInstruction 关联代币账户程序的CreateIdempotent指令（数据为[1]），
+// 账户已存在时不会失败，可以放心放在转账之前
+func NewCreateIdempotentInstruction(payer, owner, mint, tokenProgramID solana.PublicKey) (solana.Instruction, error) {
+	ata, err := FindAssociatedTokenAddress(owner, mint, tokenProgramID)
+	if err != nil {
+		return nil, err
+	}
+	if tokenProgramID.IsZero() {
+		tokenProgramID = solana.TokenProgramID
+	}
+	return solana.NewInstruction(
+		solana.SPLAssociatedTokenAccountProgramID,
+		solana.AccountMetaSlice{
+			solana.Meta(payer).WRITE().SIGNER(),
+			solana.Meta(ata).WRITE(),
+			solana.Meta(owner),
+			solana.Meta(mint),
+			solana.Meta(solana.SystemProgramID),
+			solana.Meta(tokenProgramID),
+		},
+		[]byte{1},
+	), nil
+}
+
+// GetMintInfo 读取mint的精度、供应量和权限
+func GetMintInfo(ctx context.Context, rpcClient *rpc.Client, mint solana.PublicKey) (*MintInfo, error) {
+	info, err := rpcClient.GetAccountInfoWithOpts(ctx, mint, &rpc.GetAccountInfoOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取mint账户 %s 失败: %w", mint, err)
+	}
+	return DecodeMint(mint, info.Value)
+}
+
+// DecodeMint 解析mint账户数据，Token-2022的扩展数据忽略
+func DecodeMint(address solana.PublicKey, acc *rpc.Account) (*MintInfo, error) {
+	if !isTokenProgram(acc.Owner) {
+		return nil, fmt.Errorf("%s 不是代币mint账户，owner为 %s", address, acc.Owner)
+	}
+	data := acc.Data.GetBinary()
+	if len(data) < mintLen {
+		return nil, fmt.Errorf("mint账户 %s 数据长度 %d 小于 %d", address, len(data), mintLen)
+	}
+	var mint token.Mint
+	if err := mint.UnmarshalWithDecoder(bin.NewBinDecoder(data[:mintLen])); err != nil {
+		return nil, fmt.Errorf("解析mint账户 %s 失败: %v", address, err)
+	}
+	if !mint.IsInitialized {
+		return nil, fmt.Errorf("mint账户 %s 尚未初始化", address)
+	}
+	return &MintInfo{
+		Address:         address,
+		ProgramID:       acc.Owner,
+		Decimals:        mint.Decimals,
+		Supply:          mint.Supply,
+		MintAuthority:   mint.MintAuthority,
+		FreezeAuthority: mint.FreezeAuthority,
+	}, nil
+}
+
+// GetTokenAccounts 列出owner在Token和Token-2022程序下的全部代币账户，余额按mint精度解析
+func GetTokenAccounts(ctx context.Context, rpcClient *rpc.Client, owner solana.PublicKey) ([]*TokenAccount, error) {
+	var accounts []*TokenAccount
+	for _, programID := range []solana.PublicKey{solana.TokenProgramID, solana.Token2022ProgramID} {
+		res, err := rpcClient.GetTokenAccountsByOwner(ctx, owner,
+			&rpc.GetTokenAccountsConfig{ProgramId: programID.ToPointer()},
+			&rpc.GetTokenAccountsOpts{Encoding: solana.EncodingBase64, Commitment: rpc.CommitmentConfirmed},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("查询 %s 的代币账户失败: %w", owner, err)
+		}
+		for _, item := range res.Value {
+			acc, err := DecodeTokenAccount(item.Pubkey, &item.Account)
+			if err != nil {
+				return nil, err
+			}
+			accounts = append(accounts, acc)
+		}
+	}
+	if err := fillDecimals(ctx, rpcClient, accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// GetTokenBalance 查询owner持有的mint代币余额，关联代币账户不存在时余额为0
+func GetTokenBalance(ctx context.Context, rpcClient *rpc.Client, owner, mint solana.PublicKey) (*TokenAccount, error) {
+	mintInfo, err := GetMintInfo(ctx, rpcClient, mint)
+	if err != nil {
+		return nil, err
+	}
+	ata, err := FindAssociatedTokenAddress(owner, mint, mintInfo.ProgramID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := rpcClient.GetAccountInfoWithOpts(ctx, ata, &rpc.GetAccountInfoOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if errors.Is(err, rpc.ErrNotFound) {
+		return &TokenAccount{Address: ata, ProgramID: mintInfo.ProgramID, Mint: mint, Owner: owner, Decimals: mintInfo.Decimals}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取代币账户 %s 失败: %w", ata, err)
+	}
+	acc, err := DecodeTokenAccount(ata, info.Value)
+	if err != nil {
+		return nil, err
+	}
+	acc.Decimals = mintInfo.Decimals
+	return acc, nil
+}
+
+// EnsureAssociatedTokenAccount 确保owner在mint下的关联代币账户存在，不存在时由payer付费创建并等待确认。
+// 账户已存在时签名为零值
+func EnsureAssociatedTokenAccount(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	payer solana.PrivateKey,
+	owner solana.PublicKey,
+	mint solana.PublicKey,
+	recentBlockhash solana.Hash,
+	lastValidBlockHeight uint64,
+) (solana.PublicKey, solana.Signature, error) {
+	mintInfo, err := GetMintInfo(ctx, rpcClient, mint)
+	if err != nil {
+		return solana.PublicKey{}, solana.Signature{}, err
+	}
+	ata, err := FindAssociatedTokenAddress(owner, mint, mintInfo.ProgramID)
+	if err != nil {
+		return solana.PublicKey{}, solana.Signature{}, err
+	}
+	_, err = rpcClient.GetAccountInfoWithOpts(ctx, ata, &rpc.GetAccountInfoOpts{Commitment: rpc.CommitmentConfirmed})
+	if err == nil {
+		return ata, solana.Signature{}, nil
+	}
+	if !errors.Is(err, rpc.ErrNotFound) {
+		return solana.PublicKey{}, solana.Signature{}, fmt.Errorf("查询关联代币账户 %s 失败: %w", ata, err)
+	}
+	create, err := NewCreateIdempotentInstruction(payer.PublicKey(), owner, mint, mintInfo.ProgramID)
+	if err != nil {
+		return solana.PublicKey{}, solana.Signature{}, err
+	}
+	sig, err := sendInstructions(ctx, rpcClient, wsClient, []solana.Instruction{create}, recentBlockhash, lastValidBlockHeight, payer)
+	if err != nil {
+		return solana.PublicKey{}, sig, err
+	}
+	return ata, sig, nil
+}
+
+// TransferToken 从from的关联代币账户向to转amount个mint代币，amount按mint精度填写（如"1.5"）。
+// 使用TransferChecked让链上再校验一次精度，接收方的关联代币账户不存在时在同一笔交易中创建
+func TransferToken(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	from solana.PrivateKey,
+	to solana.PublicKey,
+	mint solana.PublicKey,
+	amount string,
+	recentBlockhash solana.Hash,
+	lastValidBlockHeight uint64,
+) (solana.Signature, error) {
+	mintInfo, err := GetMintInfo(ctx, rpcClient, mint)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	raw, err := ParseTokenAmount(amount, mintInfo.Decimals)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	if raw == 0 {
+		return solana.Signature{}, fmt.Errorf("转账数量不能为0")
+	}
+
+	owner := from.PublicKey()
+	source, err := GetTokenBalance(ctx, rpcClient, owner, mint)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	if source.Amount < raw {
+		return solana.Signature{}, fmt.Errorf("代币余额不足: 余额 %s，需要 %s", source.UIAmount(), mintInfo.FormatAmount(raw))
+	}
+	destination, err := FindAssociatedTokenAddress(to, mint, mintInfo.ProgramID)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+
+	create, err := NewCreateIdempotentInstruction(owner, to, mint, mintInfo.ProgramID)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	transfer, err := withProgramID(
+		token.NewTransferCheckedInstruction(raw, mintInfo.Decimals, source.Address, mint, destination, owner, nil).Build(),
+		mintInfo.ProgramID,
+	)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	return sendInstructions(ctx, rpcClient, wsClient, []solana.Instruction{create, transfer}, recentBlockhash, lastValidBlockHeight, from)
+}
+
+// CloseEmptyTokenAccounts 关闭owner名下余额为0的代币账户，租金返还给owner。
+// 账户较多时分多笔交易发送，返回各笔交易签名和回收的lamports总数
+func CloseEmptyTokenAccounts(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	owner solana.PrivateKey,
+	recentBlockhash solana.Hash,
+	lastValidBlockHeight uint64,
+) ([]solana.Signature, uint64, error) {
+	accounts, err := GetTokenAccounts(ctx, rpcClient, owner.PublicKey())
+	if err != nil {
+		return nil, 0, err
+	}
+	var (
+		instructions []solana.Instruction
+		reclaimed    uint64
+	)
+	for _, acc := range accounts {
+		// 冻结的账户无法关闭，包装SOL账户关闭时会连同余额一起取回，这里只处理普通空账户
+		if acc.Amount != 0 || acc.IsNative || acc.State != token.Initialized {
+			continue
+		}
+		closeAccount, err := withProgramID(
+			token.NewCloseAccountInstruction(acc.Address, owner.PublicKey(), owner.PublicKey(), nil).Build(),
+			acc.ProgramID,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		instructions = append(instructions, closeAccount)
+		reclaimed += acc.Lamports
+	}
+
+	var sigs []solana.Signature
+	for start := 0; start < len(instructions); start += closeBatchSize {
+		end := min(start+closeBatchSize, len(instructions))
+		sig, err := sendInstructions(ctx, rpcClient, wsClient, instructions[start:end], recentBlockhash, lastValidBlockHeight, owner)
+		if err != nil {
+			return sigs, 0, fmt.Errorf("关闭代币账户失败（已发送 %d 笔）: %w", len(sigs), err)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, reclaimed, nil
+}
+
+// ParseTokenAmount 将"1.5"这样的金额按精度转换为最小单位，小数位超过精度时报错
+func ParseTokenAmount(s string, decimals uint8) (uint64, error) {
+	s = strings.TrimSpace(s)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart+fracPart == "" || strings.Trim(intPart+fracPart, "0123456789") != "" {
+		return 0, fmt.Errorf("无效的金额: %q", s)
+	}
+	if len(fracPart) > int(decimals) {
+		return 0, fmt.Errorf("金额 %s 的小数位超过代币精度 %d", s, decimals)
+	}
+	digits := intPart + fracPart + strings.Repeat("0", int(decimals)-len(fracPart))
+	v, _ := new(big.Int).SetString(digits, 10)
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("金额 %s 超出范围", s)
+	}
+	return v.Uint64(), nil
+}
+
+// FormatTokenAmount 将最小单位的数量按精度格式化，去掉小数末尾的0
+func FormatTokenAmount(amount uint64, decimals uint8) string {
+	s := fmt.Sprintf("%0*d", int(decimals)+1, amount)
+	if decimals == 0 {
+		return s
+	}
+	intPart, fracPart := s[:len(s)-int(decimals)], strings.TrimRight(s[len(s)-int(decimals):], "0")
+	if fracPart == "" {
+		return intPart
+	}
+	return intPart + "." + fracPart
+}
+
+// DecodeTokenAccount 解析代币账户数据，Decimals需要另外从mint读取
+func DecodeTokenAccount(address solana.PublicKey, acc *rpc.Account) (*TokenAccount, error) {
+	if !isTokenProgram(acc.Owner) {
+		return nil, fmt.Errorf("%s 不是代币账户，owner为 %s", address, acc.Owner)
+	}
+	data := acc.Data.GetBinary()
+	if len(data) < tokenAccountLen {
+		return nil, fmt.Errorf("代币账户 %s 数据长度 %d 小于 %d", address, len(data), tokenAccountLen)
+	}
+	var ta token.Account
+	if err := ta.UnmarshalWithDecoder(bin.NewBinDecoder(data[:tokenAccountLen])); err != nil {
+		return nil, fmt.Errorf("解析代币账户 %s 失败: %v", address, err)
+	}
+	return &TokenAccount{
+		Address:   address,
+		ProgramID: acc.Owner,
+		Mint:      ta.Mint,
+		Owner:     ta.Owner,
+		Amount:    ta.Amount,
+		Lamports:  acc.Lamports,
+		State:     ta.State,
+		IsNative:  ta.IsNative != nil,
+	}, nil
+}
+
+// fillDecimals 批量读取代币账户对应mint的精度
+func fillDecimals(ctx context.Context, rpcClient *rpc.Client, accounts []*TokenAccount) error {
+	var mints []solana.PublicKey
+	seen := map[solana.PublicKey]bool{}
+	for _, acc := range accounts {
+		if !seen[acc.Mint] {
+			seen[acc.Mint] = true
+			mints = append(mints, acc.Mint)
+		}
+	}
+	decimals := map[solana.PublicKey]uint8{}
+	// getMultipleAccounts每次最多100个账户
+	for start := 0; start < len(mints); start += 100 {
+		batch := mints[start:min(start+100, len(mints))]
+		res, err := rpcClient.GetMultipleAccountsWithOpts(ctx, batch, &rpc.GetMultipleAccountsOpts{
+			Encoding:   solana.EncodingBase64,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		if err != nil {
+			return fmt.Errorf("读取mint账户失败: %w", err)
+		}
+		for i, acc := range res.Value {
+			if acc == nil {
+				return fmt.Errorf("mint账户 %s 不存在", batch[i])
+			}
+			info, err := DecodeMint(batch[i], acc)
+			if err != nil {
+				return err
+			}
+			decimals[batch[i]] = info.Decimals
+		}
+	}
+	for _, acc := range accounts {
+		acc.Decimals = decimals[acc.Mint]
+	}
+	return nil
+}
+
+func isTokenProgram(programID solana.PublicKey) bool {
+	return programID.Equals(solana.TokenProgramID) || programID.Equals(solana.Token2022ProgramID)
+}
+
+// withProgramID token包构造的指令固定指向Token程序，Token-2022的账户需要换成对应的程序ID
+func withProgramID(inst solana.Instruction, programID solana.PublicKey) (solana.Instruction, error) {
+	data, err := inst.Data()
+	if err != nil {
+		return nil, fmt.Errorf("编码代币指令失败: %w", err)
+	}
+	return solana.NewInstruction(programID, inst.Accounts(), data), nil
+}
+
+// sendInstructions 加上计算预算指令后构造交易、由payer签名，经SendTransaction模拟、发送并等待确认，
+// 区块哈希过期时由payer重新签名
+func sendInstructions(
+	ctx context.Context,
+	rpcClient *rpc.Client,
+	wsClient *ws.Client,
+	instructions []solana.Instruction,
+	recentBlockhash solana.Hash,
+	lastValidBlockHeight uint64,
+	payer solana.PrivateKey,
+) (solana.Signature, error) {
+	instructions, _, err := WithComputeBudget(ctx, rpcClient, instructions, payer.PublicKey(), DefaultFeeOptions())
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	tx, missing, err := NewTxBuilder(payer.PublicKey(), recentBlockhash).
+		Add(instructions...).
+		Sign(NewKeySigner(payer)).
+		Build()
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	if len(missing) > 0 {
+		return solana.Signature{}, fmt.Errorf("%w: %s", ErrMissingSignatures, joinKeys(missing))
+	}
+
+	result, err := SendTransaction(ctx, rpcClient, wsClient, tx, lastValidBlockHeight, SendOptions{
+		Signers:    []solana.PrivateKey{payer},
+		MaxResigns: 2,
+	})
+	if err != nil {
+		return tx.Signatures[0], err
+	}
+	return result.Signature, nil
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"solana-go/mockrpc"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// setMint 在模拟节点上创建decimals为6的mint账户，owner为programID
+func (n *testNode) setMint(t *testing.T, programID solana.PublicKey) solana.PublicKey {
+	t.Helper()
+	mint := solana.NewWallet().PublicKey()
+	authority := n.payer.PublicKey()
+	var buf bytes.Buffer
+	if err := bin.NewBinEncoder(&buf).Encode(token.Mint{MintAuthority: &authority, Decimals: 6, IsInitialized: true}); err != nil {
+		t.Fatal(err)
+	}
+	n.srv.SetAccount(mint, programID, solana.LAMPORTS_PER_SOL/100, buf.Bytes())
+	return mint
+}
+
+func TestEnsureAssociatedTokenAccount(t *testing.T) {
+	tests := []struct {
+		name      string
+		programID solana.PublicKey
+		exists    bool
+	}{
+		{name: "Token创建", programID: solana.TokenProgramID},
+		{name: "Token-2022创建", programID: solana.Token2022ProgramID},
+		{name: "已存在", programID: solana.TokenProgramID, exists: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			n := newTestNode(t, mockrpc.Options{AutoAdvance: true})
+			mint := n.setMint(t, tt.programID)
+			owner := n.to
+			// 关联代币账户地址由mint所属的代币程序决定
+			want, err := FindAssociatedTokenAddress(owner, mint, tt.programID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.exists {
+				n.srv.SetAccount(want, tt.programID, solana.LAMPORTS_PER_SOL/100, make([]byte, tokenAccountLen))
+			}
+			blockhash, lastValid, err := GetRecentBlockhash(n.rpc, rpc.CommitmentConfirmed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ata, sig, err := EnsureAssociatedTokenAccount(ctx, n.rpc, nil, n.payer, owner, mint, blockhash, lastValid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ata != want {
+				t.Fatalf("关联代币账户 %s，期望 %s", ata, want)
+			}
+			if tt.exists {
+				if !sig.IsZero() || n.srv.Calls("sendTransaction") != 0 {
+					t.Fatalf("账户已存在时不应发送交易，签名 %s", sig)
+				}
+				return
+			}
+			// 发送前先模拟，发送后等到确认才返回
+			if n.srv.Calls("simulateTransaction") == 0 || n.srv.Calls("sendTransaction") != 1 {
+				t.Fatalf("模拟 %d 次，发送 %d 次", n.srv.Calls("simulateTransaction"), n.srv.Calls("sendTransaction"))
+			}
+			if landed, txErr := n.srv.Landed(sig); !landed || txErr != nil {
+				t.Fatalf("创建交易未上链: %v", txErr)
+			}
+			if n.srv.Calls("getSignatureStatuses") == 0 {
+				t.Fatal("没有等待确认")
+			}
+		})
+	}
+}
+
+func TestEnsureAssociatedTokenAccountNotMint(t *testing.T) {
+	n := newTestNode(t, mockrpc.Options{AutoAdvance: true})
+	blockhash, lastValid, err := GetRecentBlockhash(n.rpc, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 普通System账户不是mint，不会发送交易
+	_, _, err = EnsureAssociatedTokenAccount(context.Background(), n.rpc, nil, n.payer, n.to, n.payer.PublicKey(), blockhash, lastValid)
+	if err == nil || n.srv.Calls("sendTransaction") != 0 {
+		t.Fatalf("err = %v，发送 %d 次", err, n.srv.Calls("sendTransaction"))
+	}
+}
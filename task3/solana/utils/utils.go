@@ -3,14 +3,12 @@ package utils
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
-	"time"
+
+	"solana-go/wallet"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/gagliardetto/solana-go/rpc"
-	confirm "github.com/gagliardetto/solana-go/rpc/sendAndConfirmTransaction"
 	"github.com/gagliardetto/solana-go/rpc/ws"
 )
 
@@ -19,19 +17,17 @@ type KeyPairJSON struct {
 }
 
 // 获取最近区块哈希和最后有效区块高度[8](@ref)
-func GetRecentBlockhash(rpcClient *rpc.Client) (solana.Hash, uint64, error) {
-	resp, err := rpcClient.GetLatestBlockhash(context.TODO(), rpc.CommitmentConfirmed)
-	if err != nil {
-		return solana.Hash{}, 0, fmt.Errorf("获取区块哈希失败: %v", err)
+// commitment应与发送交易时等待的承诺级别一致，为空时使用confirmed
+func GetRecentBlockhash(rpcClient *rpc.Client, commitment rpc.CommitmentType) (solana.Hash, uint64, error) {
+	if commitment == "" {
+		commitment = rpc.CommitmentConfirmed
 	}
-
-	// 计算最后有效区块高度[8](@ref)
-	lastValidBlockHeight, err := rpcClient.GetBlockHeight(context.TODO(), rpc.CommitmentConfirmed)
+	resp, err := rpcClient.GetLatestBlockhash(context.TODO(), commitment)
 	if err != nil {
-		return solana.Hash{}, 0, fmt.Errorf("获取区块高度失败: %v", err)
+		return solana.Hash{}, 0, fmt.Errorf("获取区块哈希失败: %v", err)
 	}
-
-	return resp.Value.Blockhash, lastValidBlockHeight + 150, nil // 通常有效期为当前高度+150
+	// 超过lastValidBlockHeight后使用该区块哈希的交易不会再被打包
+	return resp.Value.Blockhash, resp.Value.LastValidBlockHeight, nil
 }
 
 // 查询账户余额
@@ -47,25 +43,18 @@ func GetBalance(rpcClient *rpc.Client, account solana.PublicKey) (*rpc.GetBalanc
 	return balance, nil
 }
 
-// 从私钥获取账户
-func GetAccountFromPrivateKey(filePath string) solana.PrivateKey {
-	privateKey, err := solana.PrivateKeyFromSolanaKeygenFile(filePath)
+// 从密钥文件读取账户，支持solana-keygen的JSON和base58文本；加密的密钥文件用wallet.Load并提供口令
+func GetAccountFromPrivateKey(filePath string) (solana.PrivateKey, error) {
+	privateKey, err := wallet.Load(filePath, "")
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("%w: 读取 %s 失败: %v", ErrInvalidKeypair, filePath, err)
 	}
-	//data, err := ioutil.ReadFile("wallet-keypair.json")
-	//if err != nil {
-	//	log.Fatal(err)
-	//}
-	//var keypair []byte
-	//if err := json.Unmarshal(data, &keypair); err != nil {
-	//	log.Fatal(err)
-	//}
-	//privateKeyBase58 := base58.Encode(keypair[:32])
-	return privateKey
+	return privateKey, nil
 }
 
 // 转账SOL[6](@ref)
+// opts的OnSimulation、OnAttempt等回调由调用方设置，用于打印模拟报告和广播进度；
+// Signers为空时区块哈希过期后用from重新签名，MaxResigns为0时最多重新签名2次
 func TransferSOL(
 	rpcClient *rpc.Client,
 	wsClient *ws.Client,
@@ -73,6 +62,8 @@ func TransferSOL(
 	to solana.PublicKey,
 	amount uint64,
 	recentBlockhash solana.Hash,
+	lastValidBlockHeight uint64,
+	opts SendOptions,
 ) (solana.Signature, error) {
 	// 创建转账指令
 	instruction := system.NewTransferInstruction(
@@ -81,108 +72,34 @@ func TransferSOL(
 		to,
 	).Build()
 
-	// 构造交易
-	tx, err := solana.NewTransaction(
-		[]solana.Instruction{instruction},
-		recentBlockhash,
-		solana.TransactionPayer(from.PublicKey()),
-	)
+	// 模拟得到计算单元并按最近优先费设置单价，拥堵时交易不容易被丢弃
+	instructions, _, err := WithComputeBudget(context.TODO(), rpcClient, []solana.Instruction{instruction}, from.PublicKey(), DefaultFeeOptions())
 	if err != nil {
-		return solana.Signature{}, fmt.Errorf("构造交易失败: %v", err)
+		return solana.Signature{}, err
 	}
 
-	// 签名交易
-	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-		if from.PublicKey().Equals(key) {
-			return &from
-		}
-		return nil
-	})
+	// 构造交易并签名
+	tx, missing, err := NewTxBuilder(from.PublicKey(), recentBlockhash).
+		Add(instructions...).
+		Sign(NewKeySigner(from)).
+		Build()
 	if err != nil {
-		return solana.Signature{}, fmt.Errorf("签名交易失败: %v", err)
+		return solana.Signature{}, err
 	}
-
-	// 发送交易并等待确认（带重试机制）
-	maxRetries := 3
-	var sig solana.Signature
-	for i := 0; i < maxRetries; i++ {
-		sig, err = confirm.SendAndConfirmTransaction(
-			context.TODO(),
-			rpcClient,
-			wsClient,
-			tx,
-		)
-		if err != nil {
-			if shouldRetry(err) {
-				log.Printf("Attempt %d/%d failed: %v. Retrying...", i+1, maxRetries, err)
-				time.Sleep(2 * time.Second) // 等待后重试
-				continue
-			}
-			log.Fatalf("Failed to send transaction: %v", err)
-		}
-		break
+	if len(missing) > 0 {
+		return solana.Signature{}, fmt.Errorf("%w: %s", ErrMissingSignatures, joinKeys(missing))
 	}
 
-	return sig, nil
-}
-
-// shouldRetry 判断错误是否应该重试
-func shouldRetry(err error) bool {
-	errMsg := err.Error()
-	retryableErrors := []string{
-		"Blockhash not found",
-		"block height exceeded",
-		"BlockhashNotFound",
-	}
-	for _, retryable := range retryableErrors {
-		if strings.Contains(errMsg, retryable) {
-			return true
-		}
+	// 发送交易并等待确认：先模拟，失败时不发送；有效期内定期重新广播，区块哈希过期后换新的区块哈希重新签名
+	if len(opts.Signers) == 0 {
+		opts.Signers = []solana.PrivateKey{from}
 	}
-	return false
-}
-
-// 监听交易确认[7](@ref)
-func WaitForConfirmation(wsClient *ws.Client, signature solana.Signature, timeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	// 创建 WebSocket 客户端用于监听交易状态
-	wsClient, err := ws.Connect(ctx, rpc.MainNetBeta_WS)
-	if err != nil {
-		return fmt.Errorf("failed to connect to websocket: %v", err)
+	if opts.MaxResigns == 0 {
+		opts.MaxResigns = 2
 	}
-	defer wsClient.Close()
-
-	// 订阅交易签名
-	sub, err := wsClient.SignatureSubscribe(
-		signature,
-		"",
-	)
+	result, err := SendTransaction(context.TODO(), rpcClient, wsClient, tx, lastValidBlockHeight, opts)
 	if err != nil {
-		return fmt.Errorf("failed to subscribe to signature: %v", err)
-	}
-	defer sub.Unsubscribe()
-
-	// 等待交易确认
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("context cancelled while waiting for confirmation")
-
-		case resp, ok := <-sub.Response():
-			if !ok {
-				return fmt.Errorf("subscription closed")
-			}
-
-			if resp.Value.Err != nil {
-				return fmt.Errorf("transaction failed: %v", resp.Value.Err)
-			}
-
-			//// 检查交易状态
-			//if resp.Value.Context.Slot== rpc.ConfirmationStatusFinalized {
-			//	return nil
-			//}
-		}
+		return tx.Signatures[0], err
 	}
+	return result.Signature, nil
 }
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testKeygenFile 测试助记词派生的第0个Solana账户，格式与solana-keygen和task1的hdkeys -keygen-dir一致
+const (
+	testKeygenFile = "testdata/solana-keygen.json"
+	testAddress    = "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk"
+)
+
+func TestGetAccountFromPrivateKey(t *testing.T) {
+	key, err := GetAccountFromPrivateKey(testKeygenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := key.PublicKey().String(); got != testAddress {
+		t.Fatalf("读出的地址 = %s，期望 %s", got, testAddress)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(bad, []byte("[1,2,3]"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetAccountFromPrivateKey(bad); !errors.Is(err, ErrInvalidKeypair) {
+		t.Fatalf("无效密钥文件 err = %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gagliardetto/solana-go"
+	"golang.org/x/crypto/scrypt"
+)
+
+// 加密保存：口令经scrypt派生32字节密钥，用AES-256-GCM加密64字节私钥，公钥作为附加数据一起认证。
+// 文件为JSON，公钥明文保存，不解密也能知道是哪个账户
+
+// ErrWrongPassphrase 口令错误或文件被篡改
+var ErrWrongPassphrase = errors.New("口令错误或密钥文件已损坏")
+
+const (
+	keystoreVersion = 1
+	// scrypt参数与以太坊keystore的标准强度一致，单次解密约需要1秒和256MB内存
+	scryptN      = 1 << 18
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+
+	// 解密时接受的scrypt参数上限。参数来自文件本身，不设上限时篡改过的文件可以让解密
+	// 占用任意多的内存和时间；上限下最多约1GB内存
+	maxScryptN   = 1 << 20
+	maxScryptRP  = 16
+	maxScryptMem = 1 << 30 // scrypt需要约128*N*R字节内存
+)
+
+// EncryptedKey 加密后的密钥文件内容
+type EncryptedKey struct {
+	Version    int       `json:"version"`
+	PublicKey  string    `json:"public_key"`
+	KDF        string    `json:"kdf"`
+	KDFParams  KDFParams `json:"kdf_params"`
+	Cipher     string    `json:"cipher"`
+	Nonce      string    `json:"nonce"`      // hex
+	Ciphertext string    `json:"ciphertext"` // hex，包含GCM认证标签
+}
+
+// KDFParams scrypt参数
+type KDFParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"` // hex
+}
+
+// Encrypt 用口令加密密钥
+func Encrypt(key solana.PrivateKey, passphrase string) (*EncryptedKey, error) {
+	return encrypt(key, passphrase, scryptN, scryptR, scryptP)
+}
+
+// encrypt 用指定的scrypt参数加密，测试用较小的参数
+func encrypt(key solana.PrivateKey, passphrase string, n, r, p int) (*EncryptedKey, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("口令不能为空")
+	}
+	if _, err := checkKey(key); err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("生成盐失败: %v", err)
+	}
+	ek := &EncryptedKey{
+		Version:   keystoreVersion,
+		PublicKey: key.PublicKey().String(),
+		KDF:       "scrypt",
+		KDFParams: KDFParams{N: n, R: r, P: p, Salt: hex.EncodeToString(salt)},
+		Cipher:    "aes-256-gcm",
+	}
+	aead, err := ek.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成nonce失败: %v", err)
+	}
+	ek.Nonce = hex.EncodeToString(nonce)
+	ek.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, key, []byte(ek.PublicKey)))
+	return ek, nil
+}
+
+// Decrypt 用口令解密，口令错误时返回ErrWrongPassphrase
+func (ek *EncryptedKey) Decrypt(passphrase string) (solana.PrivateKey, error) {
+	if ek.Version != keystoreVersion || ek.KDF != "scrypt" || ek.Cipher != "aes-256-gcm" {
+		return nil, fmt.Errorf("%w: 不支持的密钥文件格式: 版本 %d, %s, %s", ErrInvalidKey, ek.Version, ek.KDF, ek.Cipher)
+	}
+	nonce, err := hex.DecodeString(ek.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: nonce格式错误", ErrInvalidKey)
+	}
+	ciphertext, err := hex.DecodeString(ek.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 密文格式错误", ErrInvalidKey)
+	}
+	aead, err := ek.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: nonce长度错误", ErrInvalidKey)
+	}
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(ek.PublicKey))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	key, err := checkKey(plain)
+	if err != nil {
+		return nil, err
+	}
+	if key.PublicKey().String() != ek.PublicKey {
+		return nil, ErrWrongPassphrase
+	}
+	return key, nil
+}
+
+// aead 由口令和文件中的scrypt参数构造AES-256-GCM
+func (ek *EncryptedKey) aead(passphrase string) (cipher.AEAD, error) {
+	if err := ek.KDFParams.check(); err != nil {
+		return nil, err
+	}
+	salt, err := hex.DecodeString(ek.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 盐格式错误", ErrInvalidKey)
+	}
+	derived, err := scrypt.Key([]byte(passphrase), salt, ek.KDFParams.N, ek.KDFParams.R, ek.KDFParams.P, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("派生加密密钥失败: %v", err)
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, fmt.Errorf("初始化AES失败: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("初始化GCM失败: %v", err)
+	}
+	return aead, nil
+}
+
+// check 校验scrypt参数在允许范围内
+func (p KDFParams) check() error {
+	if p.N < 2 || p.N&(p.N-1) != 0 || p.N > maxScryptN {
+		return fmt.Errorf("%w: scrypt参数N=%d 应为2到%d之间的2的幂", ErrInvalidKey, p.N, maxScryptN)
+	}
+	if p.R < 1 || p.P < 1 || p.R > maxScryptRP || p.P > maxScryptRP || p.R*p.P > maxScryptRP {
+		return fmt.Errorf("%w: scrypt参数r=%d p=%d 超出范围，r*p不能超过%d", ErrInvalidKey, p.R, p.P, maxScryptRP)
+	}
+	if 128*p.N*p.R > maxScryptMem {
+		return fmt.Errorf("%w: scrypt参数N=%d r=%d 需要的内存超过%dMB", ErrInvalidKey, p.N, p.R, maxScryptMem>>20)
+	}
+	return nil
+}
+
+// SaveEncrypted 用口令加密后保存，文件权限0600
+func SaveEncrypted(path string, key solana.PrivateKey, passphrase string) error {
+	ek, err := Encrypt(key, passphrase)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ek, "", "  ")
+	if err != nil {
+		return fmt.Errorf("编码密钥文件失败: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("保存密钥文件失败: %v", err)
+	}
+	return nil
+}
+
+// LoadEncrypted 读取并解密SaveEncrypted保存的文件
+func LoadEncrypted(path, passphrase string) (solana.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+	ek, err := parseEncryptedKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return ek.Decrypt(passphrase)
+}
+
+// IsEncryptedFile 文件是否为加密格式，用于决定是否需要提示输入口令
+func IsEncryptedFile(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+	return isEncrypted(data), nil
+}
+
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+func parseEncryptedKey(data []byte) (*EncryptedKey, error) {
+	var ek EncryptedKey
+	if err := json.Unmarshal(data, &ek); err != nil {
+		return nil, fmt.Errorf("%w: 解析加密密钥文件失败: %v", ErrInvalidKey, err)
+	}
+	return &ek, nil
+}
@@ -0,0 +1,121 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// 测试用较小的scrypt参数，避免每次派生都要1秒和256MB内存
+const (
+	testScryptN = 1 << 10
+	testScryptR = 8
+	testScryptP = 1
+)
+
+// saveTestKey 用测试参数加密后写入文件
+func saveTestKey(t *testing.T, path, passphrase string) *EncryptedKey {
+	t.Helper()
+	key, err := FromMnemonic(testMnemonic, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ek, err := encrypt(key, passphrase, testScryptN, testScryptR, testScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(ek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return ek
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	ek := saveTestKey(t, path, "correct horse")
+	if ek.PublicKey != testAddress {
+		t.Fatalf("文件中的公钥 = %s，期望 %s", ek.PublicKey, testAddress)
+	}
+
+	key, err := ek.Decrypt("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.PublicKey().String() != testAddress {
+		t.Fatalf("解密得到 %s，期望 %s", key.PublicKey(), testAddress)
+	}
+	// 两个入口读取同一个文件得到同一个密钥
+	for name, load := range map[string]func(string, string) (solana.PrivateKey, error){
+		"LoadEncrypted": LoadEncrypted,
+		"Load":          Load,
+	} {
+		loaded, err := load(path, "correct horse")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if loaded.String() != key.String() {
+			t.Fatalf("%s 得到 %s，期望 %s", name, loaded.PublicKey(), key.PublicKey())
+		}
+	}
+	if encrypted, err := IsEncryptedFile(path); err != nil || !encrypted {
+		t.Fatalf("IsEncryptedFile = %v, %v", encrypted, err)
+	}
+}
+
+func TestDecryptWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	ek := saveTestKey(t, path, "correct horse")
+
+	if _, err := ek.Decrypt("wrong horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("口令错误 err = %v", err)
+	}
+	if _, err := LoadEncrypted(path, "wrong horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("LoadEncrypted口令错误 err = %v", err)
+	}
+	// 加密文件不提供口令时提示需要口令
+	if _, err := Load(path, ""); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("未提供口令 err = %v", err)
+	}
+	// 篡改明文公钥后认证失败
+	tampered := *ek
+	tampered.PublicKey = "11111111111111111111111111111111"
+	if _, err := tampered.Decrypt("correct horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("篡改公钥后 err = %v", err)
+	}
+}
+
+func TestDecryptKDFParamsBounds(t *testing.T) {
+	ek := saveTestKey(t, filepath.Join(t.TempDir(), "key.json"), "correct horse")
+	tests := []struct {
+		name    string
+		n, r, p int
+	}{
+		{"N超过上限", maxScryptN << 1, 8, 1},
+		{"N不是2的幂", 1000, 8, 1},
+		{"N为0", 0, 8, 1},
+		{"r*p超过上限", 1 << 10, 8, 4},
+		{"p极大", 1 << 10, 1, 1 << 30},
+		{"r为0", 1 << 10, 0, 1},
+		{"内存超过上限", maxScryptN, 16, 1},
+	}
+	for _, tt := range tests {
+		bad := *ek
+		bad.KDFParams.N, bad.KDFParams.R, bad.KDFParams.P = tt.n, tt.r, tt.p
+		// 在派生密钥之前拒绝，不会真的分配内存
+		if _, err := bad.Decrypt("correct horse"); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("%s: err = %v，期望参数无效", tt.name, err)
+		}
+	}
+	// 标准强度的参数在范围内
+	if err := (KDFParams{N: scryptN, R: scryptR, P: scryptP}).check(); err != nil {
+		t.Fatal(err)
+	}
+}
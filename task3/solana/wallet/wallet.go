@@ -0,0 +1,216 @@
+package wallet
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Solana密钥管理：生成、导入（base58私钥、solana-keygen字节数组JSON、BIP39助记词）、导出，
+// 以及用口令加密保存（见keystore.go）。助记词按SLIP-0010派生ed25519密钥，
+// 路径与Phantom、solana-keygen一致：m/44'/501'/n'/0'
+
+// ErrInvalidKey 私钥、助记词或派生路径格式错误
+var ErrInvalidKey = errors.New("无效的密钥")
+
+const (
+	// MnemonicEntropy12 12个单词的助记词熵长度（位）
+	MnemonicEntropy12 = 128
+	// MnemonicEntropy24 24个单词的助记词熵长度（位）
+	MnemonicEntropy24 = 256
+
+	hardenedOffset = 0x80000000
+	ed25519Curve   = "ed25519 seed" // SLIP-0010 ed25519主密钥的HMAC key
+)
+
+// Generate 生成随机密钥
+func Generate() (solana.PrivateKey, error) {
+	key, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("生成密钥失败: %v", err)
+	}
+	return key, nil
+}
+
+// NewMnemonic 生成助记词，bits为MnemonicEntropy12或MnemonicEntropy24
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", fmt.Errorf("生成助记词熵失败: %v", err)
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", fmt.Errorf("生成助记词失败: %v", err)
+	}
+	return mnemonic, nil
+}
+
+// DerivationPath 第account个账户的派生路径
+func DerivationPath(account uint32) string {
+	return fmt.Sprintf("m/44'/501'/%d'/0'", account)
+}
+
+// FromMnemonic 由助记词派生第account个账户的密钥，passphrase为BIP39的可选口令（"第25个词"），没有时传空字符串
+func FromMnemonic(mnemonic, passphrase string, account uint32) (solana.PrivateKey, error) {
+	return FromMnemonicPath(mnemonic, passphrase, DerivationPath(account))
+}
+
+// FromMnemonicPath 由助记词按指定路径派生密钥，ed25519只支持硬化路径
+func FromMnemonicPath(mnemonic, passphrase, path string) (solana.PrivateKey, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 助记词校验失败: %v", ErrInvalidKey, err)
+	}
+	return DeriveFromSeed(seed, path)
+}
+
+// DeriveFromSeed 按SLIP-0010从BIP39种子派生ed25519密钥
+func DeriveFromSeed(seed []byte, path string) (solana.PrivateKey, error) {
+	indexes, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha512.New, []byte(ed25519Curve))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+	for _, index := range indexes {
+		data := make([]byte, 0, 37)
+		data = append(data, 0)
+		data = append(data, key...)
+		data = binary.BigEndian.AppendUint32(data, index)
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+		key, chainCode = sum[:32], sum[32:]
+	}
+	return solana.PrivateKey(ed25519.NewKeyFromSeed(key)), nil
+}
+
+// parsePath 解析m/44'/501'/0'/0'形式的路径，每一级都必须是硬化的
+func parsePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, fmt.Errorf("%w: 派生路径必须以m开头: %q", ErrInvalidKey, path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		trimmed := strings.TrimRight(part, "'h")
+		if trimmed == part {
+			return nil, fmt.Errorf("%w: ed25519只支持硬化派生，%q 缺少'", ErrInvalidKey, part)
+		}
+		n, err := strconv.ParseUint(trimmed, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 派生路径 %q 中的 %q 无效", ErrInvalidKey, path, part)
+		}
+		indexes = append(indexes, uint32(n)+hardenedOffset)
+	}
+	return indexes, nil
+}
+
+// FromBase58 导入base58编码的64字节私钥（Phantom等钱包导出的格式）
+func FromBase58(secret string) (solana.PrivateKey, error) {
+	key, err := solana.PrivateKeyFromBase58(strings.TrimSpace(secret))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 解析base58私钥失败: %v", ErrInvalidKey, err)
+	}
+	return checkKey(key)
+}
+
+// FromJSON 导入solana-keygen格式的字节数组JSON，如[12,34,...]
+func FromJSON(data []byte) (solana.PrivateKey, error) {
+	// []byte默认按base64解析，字节数组需要先解到[]int
+	var ints []int
+	if err := json.Unmarshal(data, &ints); err != nil {
+		return nil, fmt.Errorf("%w: 解析密钥JSON失败: %v", ErrInvalidKey, err)
+	}
+	raw := make([]byte, 0, len(ints))
+	for _, v := range ints {
+		if v < 0 || v > 255 {
+			return nil, fmt.Errorf("%w: 密钥JSON中的 %d 超出字节范围", ErrInvalidKey, v)
+		}
+		raw = append(raw, byte(v))
+	}
+	return checkKey(raw)
+}
+
+// Import 自动识别格式导入密钥：[开头按字节数组JSON，多个单词按助记词（第0个账户），否则按base58私钥
+func Import(s string) (solana.PrivateKey, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "["):
+		return FromJSON([]byte(s))
+	case len(strings.Fields(s)) > 1:
+		return FromMnemonic(s, "", 0)
+	default:
+		return FromBase58(s)
+	}
+}
+
+// ToBase58 导出为base58私钥
+func ToBase58(key solana.PrivateKey) string {
+	return key.String()
+}
+
+// ToJSON 导出为solana-keygen格式的字节数组JSON
+func ToJSON(key solana.PrivateKey) []byte {
+	ints := make([]int, len(key))
+	for i, b := range key {
+		ints[i] = int(b)
+	}
+	data, _ := json.Marshal(ints)
+	return data
+}
+
+// Save 以solana-keygen格式明文保存，文件权限0600。长期保存的密钥应使用SaveEncrypted
+func Save(path string, key solana.PrivateKey) error {
+	if err := os.WriteFile(path, ToJSON(key), 0o600); err != nil {
+		return fmt.Errorf("保存密钥文件失败: %v", err)
+	}
+	return nil
+}
+
+// Load 读取密钥文件：加密格式用passphrase解密，否则按solana-keygen JSON或base58文本读取
+func Load(path, passphrase string) (solana.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+	if isEncrypted(data) {
+		if passphrase == "" {
+			return nil, fmt.Errorf("%w: 密钥文件 %s 已加密，需要提供口令", ErrWrongPassphrase, path)
+		}
+		ek, err := parseEncryptedKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return ek.Decrypt(passphrase)
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil, fmt.Errorf("%w: 密钥文件 %s 为空", ErrInvalidKey, path)
+	}
+	return Import(string(data))
+}
+
+// checkKey 校验64字节私钥的后32字节确实是前32字节对应的公钥
+func checkKey(raw []byte) (solana.PrivateKey, error) {
+	if len(raw) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("%w: 私钥长度应为%d字节，实际%d字节", ErrInvalidKey, ed25519.PrivateKeySize, len(raw))
+	}
+	expected := ed25519.NewKeyFromSeed(raw[:ed25519.SeedSize])
+	if !hmac.Equal(expected[ed25519.SeedSize:], raw[ed25519.SeedSize:]) {
+		return nil, fmt.Errorf("%w: 私钥与公钥不匹配", ErrInvalidKey)
+	}
+	return solana.PrivateKey(raw), nil
+}
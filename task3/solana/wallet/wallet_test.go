@@ -0,0 +1,62 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// task1/pkg/hdwallet的Solana账户也由DeriveFromSeed派生，这里的向量同时覆盖两边
+const (
+	testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	// testAddress Phantom在 m/44'/501'/0'/0' 的地址
+	testAddress = "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk"
+)
+
+func TestSLIP10Vectors(t *testing.T) {
+	// SLIP-0010 Test vector 1 for ed25519，DeriveFromSeed不返回chain code，只比较私钥
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	vectors := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+		{"m/0'/1'", "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2"},
+		{"m/0'/1'/2'", "92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9"},
+		{"m/0'/1'/2'/2'", "30d1dc7e5fc04c31219ab25a27ae00b50f6fd66622f6e9c913253d6511d1e662"},
+		{"m/0'/1'/2'/2'/1000000000'", "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793"},
+	}
+	for _, v := range vectors {
+		key, err := DeriveFromSeed(seed, v.path)
+		if err != nil {
+			t.Fatalf("%s: %v", v.path, err)
+		}
+		if got := hex.EncodeToString(key[:32]); got != v.key {
+			t.Fatalf("%s 私钥 = %s，期望 %s", v.path, got, v.key)
+		}
+	}
+
+	if _, err := DeriveFromSeed(seed, "m/44'/501'/0"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("非硬化路径 err = %v", err)
+	}
+}
+
+func TestFromMnemonic(t *testing.T) {
+	key, err := FromMnemonic(testMnemonic, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := key.PublicKey().String(); got != testAddress {
+		t.Fatalf("第0个账户 = %s，期望 %s", got, testAddress)
+	}
+	// 导出再导入得到同一个密钥
+	for _, s := range []string{string(ToJSON(key)), ToBase58(key), testMnemonic} {
+		imported, err := Import(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !imported.PublicKey().Equals(key.PublicKey()) {
+			t.Fatalf("导入 %.20s... 得到 %s", s, imported.PublicKey())
+		}
+	}
+}
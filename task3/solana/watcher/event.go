@@ -0,0 +1,187 @@
+package watcher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Kind 订阅类型
+type Kind string
+
+const (
+	KindAccount Kind = "account" // accountSubscribe：单个账户的余额和数据变化
+	KindProgram Kind = "program" // programSubscribe：程序拥有的任意账户的变化
+	KindLogs    Kind = "logs"    // logsSubscribe：提到该地址的交易日志
+)
+
+// Target 一个订阅目标
+type Target struct {
+	Kind    Kind             `json:"kind"`
+	Address solana.PublicKey `json:"address"`
+}
+
+func (t Target) String() string {
+	return fmt.Sprintf("%s:%s", t.Kind, t.Address)
+}
+
+// ParseTargets 把配置中的地址列表解析为订阅目标
+func ParseTargets(kind Kind, addresses []string) ([]Target, error) {
+	switch kind {
+	case KindAccount, KindProgram, KindLogs:
+	default:
+		return nil, fmt.Errorf("未知的订阅类型: %s（可选 account/program/logs）", kind)
+	}
+	targets := make([]Target, 0, len(addresses))
+	for _, s := range addresses {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		addr, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			return nil, fmt.Errorf("无效的%s地址 %s: %v", kind, s, err)
+		}
+		targets = append(targets, Target{Kind: kind, Address: addr})
+	}
+	return targets, nil
+}
+
+// EventType 事件类型
+type EventType string
+
+const (
+	EventAccount   EventType = "account"   // 账户变化，来自account或program订阅
+	EventLogs      EventType = "logs"      // 交易日志
+	EventReconnect EventType = "reconnect" // 断线重连完成，Gap为可能漏掉通知的slot区间
+)
+
+// Event 推送给channel和Sink的事件
+type Event struct {
+	Type    EventType      `json:"type"`
+	Target  *Target        `json:"target,omitempty"` // 产生事件的订阅，重连事件为nil
+	Slot    uint64         `json:"slot"`
+	Time    time.Time      `json:"time"`
+	Resync  bool           `json:"resync,omitempty"` // 重连后补查得到的事件，而不是实时通知
+	Account *AccountUpdate `json:"account,omitempty"`
+	Logs    *LogsUpdate    `json:"logs,omitempty"`
+	Gap     *Gap           `json:"gap,omitempty"`
+}
+
+func (e Event) String() string {
+	prefix := fmt.Sprintf("%s slot %d", e.Time.Local().Format(time.TimeOnly), e.Slot)
+	if e.Resync {
+		prefix += " [补查]"
+	}
+	switch {
+	case e.Account != nil:
+		return fmt.Sprintf("%s %s %s", prefix, e.Target, e.Account)
+	case e.Logs != nil:
+		return fmt.Sprintf("%s %s %s", prefix, e.Target, e.Logs)
+	case e.Gap != nil:
+		return fmt.Sprintf("%s 重连完成，%s", prefix, e.Gap)
+	}
+	return fmt.Sprintf("%s %s", prefix, e.Type)
+}
+
+// AccountUpdate 解码后的账户状态
+type AccountUpdate struct {
+	Address       solana.PublicKey `json:"address"`
+	Owner         solana.PublicKey `json:"owner"`
+	Lamports      uint64           `json:"lamports"`
+	LamportsDelta int64            `json:"lamports_delta"` // 与上一次已知状态相比，首次出现的账户为0
+	DataLen       int              `json:"data_len"`
+	Closed        bool             `json:"closed,omitempty"` // 账户已关闭（余额为0或不存在）
+	Token         *TokenUpdate     `json:"token,omitempty"`  // Token/Token-2022程序的代币账户
+}
+
+func (a *AccountUpdate) String() string {
+	if a.Closed {
+		return fmt.Sprintf("%s 已关闭", a.Address)
+	}
+	s := fmt.Sprintf("%s 余额 %s SOL", a.Address, utils.FormatTokenAmount(a.Lamports, 9))
+	if a.LamportsDelta != 0 {
+		s += fmt.Sprintf(" (%s)", signed(a.LamportsDelta, 9))
+	}
+	if a.Token != nil {
+		s += " " + a.Token.String()
+	}
+	return s
+}
+
+// TokenUpdate 代币账户的余额，精度未知时UIAmount为空
+type TokenUpdate struct {
+	Mint        solana.PublicKey `json:"mint"`
+	Owner       solana.PublicKey `json:"owner"`
+	Amount      uint64           `json:"amount"`
+	AmountDelta int64            `json:"amount_delta"`
+	Decimals    uint8            `json:"decimals"`
+	UIAmount    string           `json:"ui_amount,omitempty"`
+}
+
+func (t *TokenUpdate) String() string {
+	amount, delta := t.UIAmount, ""
+	if amount == "" {
+		amount = fmt.Sprintf("%d (最小单位)", t.Amount)
+	}
+	if t.AmountDelta != 0 {
+		if t.UIAmount != "" {
+			delta = fmt.Sprintf(" (%s)", signed(t.AmountDelta, t.Decimals))
+		} else {
+			delta = fmt.Sprintf(" (%+d)", t.AmountDelta)
+		}
+	}
+	return fmt.Sprintf("代币 %s%s，mint %s，所有者 %s", amount, delta, t.Mint, t.Owner)
+}
+
+// LogsUpdate 一笔交易的日志，补查的交易只有签名和错误
+type LogsUpdate struct {
+	Signature solana.Signature `json:"signature"`
+	Err       string           `json:"error,omitempty"`
+	Logs      []string         `json:"logs,omitempty"`
+}
+
+func (l *LogsUpdate) String() string {
+	status := "成功"
+	if l.Err != "" {
+		status = "失败: " + l.Err
+	}
+	return fmt.Sprintf("交易 %s %s，%d 行日志", l.Signature, status, len(l.Logs))
+}
+
+// Gap 断线期间可能漏掉通知的slot区间：account订阅已用当前状态补齐，logs订阅已按签名补查，
+// program订阅无法补查，需要的话由使用方自行处理
+type Gap struct {
+	FromSlot uint64 `json:"from_slot"` // 断线前最后观察到的slot
+	ToSlot   uint64 `json:"to_slot"`   // 重新订阅后的slot
+	Reason   string `json:"reason"`
+	// Truncated 漏掉的交易超过Options.BackfillLimit的logs订阅，只补推了最新的部分，
+	// 更早的交易需要使用方按签名自行补查
+	Truncated []Target `json:"truncated,omitempty"`
+}
+
+// Slots 区间内的slot数
+func (g *Gap) Slots() uint64 {
+	if g.ToSlot <= g.FromSlot {
+		return 0
+	}
+	return g.ToSlot - g.FromSlot
+}
+
+func (g *Gap) String() string {
+	s := fmt.Sprintf("断线 slot %d-%d（%d 个slot）: %s", g.FromSlot, g.ToSlot, g.Slots(), g.Reason)
+	for _, target := range g.Truncated {
+		s += fmt.Sprintf("；%s 补查不完整", target)
+	}
+	return s
+}
+
+func signed(delta int64, decimals uint8) string {
+	if delta < 0 {
+		return "-" + utils.FormatTokenAmount(uint64(-delta), decimals)
+	}
+	return "+" + utils.FormatTokenAmount(uint64(delta), decimals)
+}
@@ -0,0 +1,143 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Sink 事件输出，Watcher串行调用Send，实现不需要考虑并发
+type Sink interface {
+	Send(ctx context.Context, ev Event) error
+	Close() error
+}
+
+// ParseSink 按配置创建Sink：stdout、file:<路径>（JSON Lines追加写入）或 http(s)://webhook地址
+func ParseSink(spec string) (Sink, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "stdout":
+		return NewWriterSink(os.Stdout), nil
+	case strings.HasPrefix(spec, "file:"):
+		return NewFileSink(strings.TrimPrefix(spec, "file:"))
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return NewWebhookSink(spec), nil
+	}
+	return nil, fmt.Errorf("未知的输出: %q（可选 stdout、file:<路径>、http(s)://地址）", spec)
+}
+
+// WriterSink 每个事件写一行可读文本
+type WriterSink struct {
+	w io.Writer
+}
+
+// NewWriterSink 写到w，例如os.Stdout
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Send(_ context.Context, ev Event) error {
+	if _, err := fmt.Fprintln(s.w, ev.String()); err != nil {
+		return fmt.Errorf("写入事件失败: %w", err)
+	}
+	return nil
+}
+
+func (s *WriterSink) Close() error {
+	return nil
+}
+
+// FileSink 以JSON Lines格式追加写入文件，每个事件一行
+type FileSink struct {
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewFileSink 打开（不存在时创建）path用于追加写入
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file输出需要指定路径，如 file:events.jsonl")
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("打开事件文件失败: %w", err)
+	}
+	return &FileSink{file: file, enc: json.NewEncoder(file)}, nil
+}
+
+func (s *FileSink) Send(_ context.Context, ev Event) error {
+	if err := s.enc.Encode(ev); err != nil {
+		return fmt.Errorf("写入事件文件失败: %w", err)
+	}
+	return nil
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+const (
+	webhookTimeout = 10 * time.Second
+	webhookRetries = 3
+)
+
+// WebhookSink 把事件以JSON POST到URL，非2xx响应或网络错误时按1s、2s...重试
+type WebhookSink struct {
+	URL     string
+	Client  *http.Client
+	Retries int
+}
+
+// NewWebhookSink 创建webhook输出，默认超时10秒、最多重试3次
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{URL: url, Client: &http.Client{Timeout: webhookTimeout}, Retries: webhookRetries}
+}
+
+func (s *WebhookSink) Send(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("编码事件失败: %v", err)
+	}
+	var lastErr error
+	for attempt := 0; attempt <= s.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+		if lastErr = s.post(ctx, body); lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("推送webhook失败（已重试 %d 次）: %w", s.Retries, lastErr)
+}
+
+func (s *WebhookSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("响应状态 %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *WebhookSink) Close() error {
+	return nil
+}
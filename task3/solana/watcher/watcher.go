@@ -0,0 +1,542 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// 订阅监听服务：对配置的地址建立accountSubscribe/programSubscribe/logsSubscribe订阅，
+// 解码通知后推送到channel和各个Sink。websocket断开后自动重连并重新订阅，
+// 用断线前后的slot标出可能漏掉通知的区间，account订阅重新读取当前状态补齐，
+// logs订阅按getSignaturesForAddress补查断线期间的交易
+
+const (
+	defaultReconnectDelay    = time.Second
+	defaultMaxReconnectDelay = 30 * time.Second
+	// recentSignatures 记住最近推送过的交易签名数，避免补查和实时通知重复推送
+	recentSignatures     = 1024
+	defaultBackfillLimit = 10_000
+)
+
+// backfillPageSize 补查时getSignaturesForAddress每页的签名数，节点允许的最大值为1000
+var backfillPageSize = 1000
+
+// Options 监听参数
+type Options struct {
+	Commitment        rpc.CommitmentType // 默认confirmed
+	Sinks             []Sink
+	ReconnectDelay    time.Duration // 首次重连等待，之后每次翻倍，默认1秒
+	MaxReconnectDelay time.Duration // 默认30秒
+	// BackfillLimit 重连后每个logs订阅最多补查的交易数，默认10000。
+	// 超过时只推送最新的BackfillLimit笔，并在重连事件的Gap.Truncated中标出
+	BackfillLimit int
+	// OnError 连接断开、Sink推送失败等不中断监听的错误，默认用log.Printf打印
+	OnError func(error)
+}
+
+// Watcher 订阅监听服务
+type Watcher struct {
+	rpcClient *rpc.Client
+	wsURL     string
+	targets   []Target
+	opts      Options
+
+	mu       sync.Mutex
+	lastSlot uint64
+	accounts map[solana.PublicKey]accountState
+	mints    map[solana.PublicKey]uint8
+	lastSigs map[solana.PublicKey]solana.Signature // logs订阅最近一笔交易，作为补查的终点
+	seen     map[solana.Signature]bool
+	seenList []solana.Signature
+
+	emitMu sync.Mutex
+}
+
+// accountState 账户上一次已知的状态，用于计算变化量和判断补查时是否有变化
+type accountState struct {
+	lamports    uint64
+	tokenAmount uint64
+	exists      bool
+}
+
+// New 创建监听服务，rpcClient用于补查和读取mint精度，wsURL为websocket节点地址
+func New(rpcClient *rpc.Client, wsURL string, targets []Target, opts Options) (*Watcher, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("没有订阅目标")
+	}
+	if opts.Commitment == "" {
+		opts.Commitment = rpc.CommitmentConfirmed
+	}
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = defaultReconnectDelay
+	}
+	if opts.MaxReconnectDelay < opts.ReconnectDelay {
+		opts.MaxReconnectDelay = max(defaultMaxReconnectDelay, opts.ReconnectDelay)
+	}
+	if opts.BackfillLimit <= 0 {
+		opts.BackfillLimit = defaultBackfillLimit
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(err error) { log.Printf("监听: %v", err) }
+	}
+	return &Watcher{
+		rpcClient: rpcClient,
+		wsURL:     wsURL,
+		targets:   targets,
+		opts:      opts,
+		accounts:  make(map[solana.PublicKey]accountState),
+		mints:     make(map[solana.PublicKey]uint8),
+		lastSigs:  make(map[solana.PublicKey]solana.Signature),
+		seen:      make(map[solana.Signature]bool),
+	}, nil
+}
+
+// Run 开始监听，直到ctx取消（返回ctx.Err()）。events不为nil时每个事件也会发送到events，
+// 调用方需要持续读取，否则监听会阻塞。返回前关闭所有Sink，但不关闭events
+func (w *Watcher) Run(ctx context.Context, events chan<- Event) error {
+	defer func() {
+		for _, sink := range w.opts.Sinks {
+			if err := sink.Close(); err != nil {
+				w.opts.OnError(err)
+			}
+		}
+	}()
+
+	delay := w.opts.ReconnectDelay
+	// synced 至少完成过一次订阅和状态读取，之后的断线才需要补齐
+	var (
+		synced bool
+		gap    *Gap
+	)
+	for {
+		client, err := ws.Connect(ctx, w.wsURL)
+		if err == nil {
+			var ok bool
+			ok, err = w.session(ctx, client, events, gap)
+			client.Close()
+			if ok {
+				synced, gap, delay = true, nil, w.opts.ReconnectDelay
+			}
+		} else {
+			err = fmt.Errorf("连接 %s 失败: %v", w.wsURL, err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		w.opts.OnError(fmt.Errorf("%v，%s 后重连", err, delay))
+		// 从断线前最后观察到的slot开始补齐：连接可能在最后一次通知之后早已失效，
+		// 此时再向节点查询当前slot会跳过中间的变化。重连失败时保留第一次断线的slot，补齐整个区间
+		if synced && gap == nil {
+			gap = &Gap{FromSlot: w.observedSlot(), Reason: err.Error()}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, w.opts.MaxReconnectDelay)
+	}
+}
+
+// session 在一个连接上订阅全部目标并接收通知，直到连接断开或ctx取消。
+// gap不为nil表示这是重连，订阅成功后先补齐断线期间的变化；synced表示订阅和补齐都已完成
+func (w *Watcher) session(ctx context.Context, client *ws.Client, events chan<- Event, gap *Gap) (synced bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	receivers := make([]func(context.Context) error, 0, len(w.targets))
+	for _, target := range w.targets {
+		recv, unsubscribe, err := w.subscribe(client, target, events)
+		if err != nil {
+			return false, fmt.Errorf("订阅 %s 失败: %v", target, err)
+		}
+		defer unsubscribe()
+		receivers = append(receivers, recv)
+	}
+
+	// 先订阅再读取状态，两者之间发生的变化会通过通知收到，不会漏掉
+	if gap == nil {
+		if err := w.snapshot(ctx); err != nil {
+			return false, err
+		}
+	} else {
+		gap.ToSlot = w.currentSlot(ctx)
+		if err := w.resync(ctx, events, gap); err != nil {
+			return false, err
+		}
+	}
+
+	errCh := make(chan error, len(receivers))
+	var wg sync.WaitGroup
+	for _, recv := range receivers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if err := recv(ctx); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case err = <-errCh:
+		err = fmt.Errorf("连接断开: %v", err)
+	}
+	cancel()
+	wg.Wait()
+	return true, err
+}
+
+// subscribe 建立一个订阅，返回接收并推送一条通知的函数
+func (w *Watcher) subscribe(client *ws.Client, target Target, events chan<- Event) (func(context.Context) error, func(), error) {
+	switch target.Kind {
+	case KindAccount:
+		sub, err := client.AccountSubscribeWithOpts(target.Address, w.opts.Commitment, solana.EncodingBase64)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(ctx context.Context) error {
+			res, err := sub.Recv(ctx)
+			if err != nil {
+				return err
+			}
+			ev := w.accountEvent(ctx, target, res.Context.Slot, target.Address, res.Value, false)
+			return w.emit(ctx, events, ev)
+		}, sub.Unsubscribe, nil
+	case KindProgram:
+		sub, err := client.ProgramSubscribeWithOpts(target.Address, w.opts.Commitment, solana.EncodingBase64, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(ctx context.Context) error {
+			res, err := sub.Recv(ctx)
+			if err != nil {
+				return err
+			}
+			ev := w.accountEvent(ctx, target, res.Context.Slot, res.Value.Pubkey, res.Value.Account, false)
+			return w.emit(ctx, events, ev)
+		}, sub.Unsubscribe, nil
+	case KindLogs:
+		sub, err := client.LogsSubscribeMentions(target.Address, w.opts.Commitment)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(ctx context.Context) error {
+			res, err := sub.Recv(ctx)
+			if err != nil {
+				return err
+			}
+			w.observeSlot(res.Context.Slot)
+			if !w.markSeen(target.Address, res.Value.Signature) {
+				return nil
+			}
+			return w.emit(ctx, events, Event{
+				Type:   EventLogs,
+				Target: &target,
+				Slot:   res.Context.Slot,
+				Time:   time.Now(),
+				Logs:   &LogsUpdate{Signature: res.Value.Signature, Err: errString(res.Value.Err), Logs: res.Value.Logs},
+			})
+		}, sub.Unsubscribe, nil
+	}
+	return nil, nil, fmt.Errorf("未知的订阅类型: %s", target.Kind)
+}
+
+// snapshot 首次连接时记录account订阅的当前状态和logs订阅的最新交易，不推送事件
+func (w *Watcher) snapshot(ctx context.Context) error {
+	for _, target := range w.targets {
+		switch target.Kind {
+		case KindAccount:
+			info, err := w.getAccount(ctx, target.Address)
+			if err != nil {
+				return err
+			}
+			w.accountUpdate(ctx, target.Address, info)
+		case KindLogs:
+			limit := 1
+			sigs, err := w.rpcClient.GetSignaturesForAddressWithOpts(ctx, target.Address, &rpc.GetSignaturesForAddressOpts{
+				Limit:      &limit,
+				Commitment: rpc.CommitmentConfirmed,
+			})
+			if err != nil {
+				return fmt.Errorf("查询 %s 的交易签名失败: %w", target.Address, utils.ClassifyError(err))
+			}
+			if len(sigs) > 0 {
+				w.markSeen(target.Address, sigs[0].Signature)
+			}
+		}
+	}
+	return nil
+}
+
+// resync 重连后先补查logs订阅漏掉的交易，推送重连事件，再推送account订阅的状态变化和补查到的交易
+func (w *Watcher) resync(ctx context.Context, events chan<- Event, gap *Gap) error {
+	// 补查不完整时要在重连事件中标出，所以先查签名再推送。上次重连失败时可能已经标过，重新统计
+	gap.Truncated = nil
+	missed := make(map[int][]*rpc.TransactionSignature)
+	for i, target := range w.targets {
+		if target.Kind != KindLogs {
+			continue
+		}
+		sigs, complete, err := w.missedSignatures(ctx, target, gap)
+		if err != nil {
+			return err
+		}
+		if !complete {
+			gap.Truncated = append(gap.Truncated, target)
+		}
+		missed[i] = sigs
+	}
+	if err := w.emit(ctx, events, Event{Type: EventReconnect, Slot: gap.ToSlot, Time: time.Now(), Gap: gap}); err != nil {
+		return err
+	}
+	for i, target := range w.targets {
+		switch target.Kind {
+		case KindAccount:
+			info, err := w.getAccount(ctx, target.Address)
+			if err != nil {
+				return err
+			}
+			w.mu.Lock()
+			prev := w.accounts[target.Address]
+			w.mu.Unlock()
+			ev := w.accountEvent(ctx, target, gap.ToSlot, target.Address, info, true)
+			if ev.Account.Lamports == prev.lamports && tokenAmount(ev.Account) == prev.tokenAmount && !ev.Account.Closed == prev.exists {
+				continue
+			}
+			if err := w.emit(ctx, events, ev); err != nil {
+				return err
+			}
+		case KindLogs:
+			if err := w.backfillLogs(ctx, events, target, missed[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// missedSignatures 分页查询断线前最后一笔交易之后的交易，按从新到旧返回，最多BackfillLimit笔。
+// 还有更早的交易没有查到时complete为false
+func (w *Watcher) missedSignatures(ctx context.Context, target Target, gap *Gap) (sigs []*rpc.TransactionSignature, complete bool, err error) {
+	w.mu.Lock()
+	until := w.lastSigs[target.Address]
+	w.mu.Unlock()
+	var before solana.Signature
+	// 多查一笔，用来判断是否超过上限
+	for len(sigs) <= w.opts.BackfillLimit {
+		limit := min(w.opts.BackfillLimit+1-len(sigs), backfillPageSize)
+		page, err := w.rpcClient.GetSignaturesForAddressWithOpts(ctx, target.Address, &rpc.GetSignaturesForAddressOpts{
+			Limit:      &limit,
+			Before:     before,
+			Until:      until,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("补查 %s 的交易失败: %w", target.Address, utils.ClassifyError(err))
+		}
+		for _, sig := range page {
+			// 断线前没有见过任何交易时只补查断线区间内的
+			if until.IsZero() && sig.Slot < gap.FromSlot {
+				return sigs, true, nil
+			}
+			sigs = append(sigs, sig)
+		}
+		if len(page) < limit {
+			return sigs, true, nil
+		}
+		before = page[len(page)-1].Signature
+	}
+	return sigs[:w.opts.BackfillLimit], false, nil
+}
+
+// backfillLogs 按从旧到新推送补查到的交易，sigs按从新到旧排列
+func (w *Watcher) backfillLogs(ctx context.Context, events chan<- Event, target Target, sigs []*rpc.TransactionSignature) error {
+	for i := len(sigs) - 1; i >= 0; i-- {
+		sig := sigs[i]
+		if !w.markSeen(target.Address, sig.Signature) {
+			continue
+		}
+		if err := w.emit(ctx, events, Event{
+			Type:   EventLogs,
+			Target: &target,
+			Slot:   sig.Slot,
+			Time:   time.Now(),
+			Resync: true,
+			Logs:   &LogsUpdate{Signature: sig.Signature, Err: errString(sig.Err)},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accountEvent 解码账户通知并更新已知状态，info为nil表示账户不存在
+func (w *Watcher) accountEvent(ctx context.Context, target Target, slot uint64, address solana.PublicKey, info *rpc.Account, resync bool) Event {
+	w.observeSlot(slot)
+	return Event{
+		Type:    EventAccount,
+		Target:  &target,
+		Slot:    slot,
+		Time:    time.Now(),
+		Resync:  resync,
+		Account: w.accountUpdate(ctx, address, info),
+	}
+}
+
+func (w *Watcher) accountUpdate(ctx context.Context, address solana.PublicKey, info *rpc.Account) *AccountUpdate {
+	update := &AccountUpdate{Address: address, Closed: info == nil || info.Lamports == 0}
+	if info != nil {
+		update.Owner = info.Owner
+		update.Lamports = info.Lamports
+		if info.Data != nil {
+			update.DataLen = len(info.Data.GetBinary())
+		}
+		if ta, err := utils.DecodeTokenAccount(address, info); err == nil {
+			update.Token = &TokenUpdate{Mint: ta.Mint, Owner: ta.Owner, Amount: ta.Amount}
+			if decimals, ok := w.mintDecimals(ctx, ta.Mint); ok {
+				update.Token.Decimals = decimals
+				update.Token.UIAmount = utils.FormatTokenAmount(ta.Amount, decimals)
+			}
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	prev, known := w.accounts[address]
+	if known {
+		update.LamportsDelta = int64(update.Lamports) - int64(prev.lamports)
+		if update.Token != nil {
+			update.Token.AmountDelta = int64(update.Token.Amount) - int64(prev.tokenAmount)
+		}
+	}
+	w.accounts[address] = accountState{lamports: update.Lamports, tokenAmount: tokenAmount(update), exists: !update.Closed}
+	return update
+}
+
+// mintDecimals 读取并缓存mint精度，读取失败时下次再试
+func (w *Watcher) mintDecimals(ctx context.Context, mint solana.PublicKey) (uint8, bool) {
+	w.mu.Lock()
+	decimals, ok := w.mints[mint]
+	w.mu.Unlock()
+	if ok {
+		return decimals, true
+	}
+	info, err := utils.GetMintInfo(ctx, w.rpcClient, mint)
+	if err != nil {
+		w.opts.OnError(err)
+		return 0, false
+	}
+	w.mu.Lock()
+	w.mints[mint] = info.Decimals
+	w.mu.Unlock()
+	return info.Decimals, true
+}
+
+func (w *Watcher) getAccount(ctx context.Context, address solana.PublicKey) (*rpc.Account, error) {
+	res, err := w.rpcClient.GetAccountInfoWithOpts(ctx, address, &rpc.GetAccountInfoOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: w.opts.Commitment,
+	})
+	if errors.Is(err, rpc.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取账户 %s 失败: %w", address, utils.ClassifyError(err))
+	}
+	w.observeSlot(res.Context.Slot)
+	return res.Value, nil
+}
+
+// currentSlot 节点当前的slot，读取失败时用最后一次通知的slot
+func (w *Watcher) currentSlot(ctx context.Context) uint64 {
+	if slot, err := w.rpcClient.GetSlot(ctx, w.opts.Commitment); err == nil {
+		w.observeSlot(slot)
+		return slot
+	}
+	return w.observedSlot()
+}
+
+// observedSlot 通知和状态读取中观察到的最大slot
+func (w *Watcher) observedSlot() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastSlot
+}
+
+func (w *Watcher) observeSlot(slot uint64) {
+	w.mu.Lock()
+	w.lastSlot = max(w.lastSlot, slot)
+	w.mu.Unlock()
+}
+
+// markSeen 记录logs订阅的交易，已经推送过时返回false
+func (w *Watcher) markSeen(address solana.PublicKey, sig solana.Signature) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastSigs[address] = sig
+	if w.seen[sig] {
+		return false
+	}
+	w.seen[sig] = true
+	w.seenList = append(w.seenList, sig)
+	if len(w.seenList) > recentSignatures {
+		delete(w.seen, w.seenList[0])
+		w.seenList = w.seenList[1:]
+	}
+	return true
+}
+
+// emit 串行推送到各个Sink和events，Sink失败只报告错误
+func (w *Watcher) emit(ctx context.Context, events chan<- Event, ev Event) error {
+	w.emitMu.Lock()
+	defer w.emitMu.Unlock()
+	for _, sink := range w.opts.Sinks {
+		if err := sink.Send(ctx, ev); err != nil {
+			w.opts.OnError(err)
+		}
+	}
+	if events == nil {
+		return nil
+	}
+	select {
+	case events <- ev:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func tokenAmount(update *AccountUpdate) uint64 {
+	if update.Token == nil {
+		return 0
+	}
+	return update.Token.Amount
+}
+
+// errString 交易错误的JSON表示，成功时为空
+func errString(txErr interface{}) string {
+	if txErr == nil {
+		return ""
+	}
+	data, err := json.Marshal(txErr)
+	if err != nil {
+		return fmt.Sprint(txErr)
+	}
+	return string(data)
+}
@@ -0,0 +1,196 @@
+package watcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"solana-go/mockrpc"
+	"solana-go/utils"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// testNode 模拟节点和一个logs订阅的地址，send向该地址转账产生交易
+type testNode struct {
+	srv    *mockrpc.Server
+	rpc    *rpc.Client
+	payer  solana.PrivateKey
+	target Target
+	sent   uint64
+}
+
+func newTestNode(t *testing.T) *testNode {
+	t.Helper()
+	srv := mockrpc.NewServer(mockrpc.Options{})
+	t.Cleanup(srv.Close)
+	n := &testNode{
+		srv:    srv,
+		rpc:    rpc.New(srv.URL),
+		payer:  solana.NewWallet().PrivateKey,
+		target: Target{Kind: KindLogs, Address: solana.NewWallet().PublicKey()},
+	}
+	srv.SetBalance(n.payer.PublicKey(), solana.LAMPORTS_PER_SOL)
+	return n
+}
+
+// send 发送一笔提到订阅地址的交易并出一个块，每笔金额不同，签名不会重复
+func (n *testNode) send(t *testing.T) solana.Signature {
+	t.Helper()
+	n.sent++
+	blockhash, _, err := utils.GetRecentBlockhash(n.rpc, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, missing, err := utils.NewTxBuilder(n.payer.PublicKey(), blockhash).
+		Add(system.NewTransferInstruction(1000+n.sent, n.payer.PublicKey(), n.target.Address).Build()).
+		Sign(utils.NewKeySigner(n.payer)).
+		Build()
+	if err != nil || len(missing) > 0 {
+		t.Fatalf("构造交易失败: %v 缺少签名 %v", err, missing)
+	}
+	sig, err := n.rpc.SendTransaction(context.Background(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.srv.Advance(1)
+	return sig
+}
+
+// run 启动监听，等首次订阅和快照完成后返回事件channel，测试结束时停止
+func (n *testNode) run(t *testing.T, opts Options) <-chan Event {
+	t.Helper()
+	opts.ReconnectDelay, opts.MaxReconnectDelay = 5*time.Millisecond, 20*time.Millisecond
+	opts.OnError = func(err error) { t.Logf("监听: %v", err) }
+	w, err := New(n.rpc, n.srv.WSURL, []Target{n.target}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan Event, 100)
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx, events) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	// 先订阅再读取快照，快照请求到达节点时订阅已经建立
+	deadline := time.Now().Add(5 * time.Second)
+	for n.srv.Calls("getSignaturesForAddress") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("等待订阅超时")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return events
+}
+
+func next(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待事件超时")
+		return Event{}
+	}
+}
+
+// expectLogs 依次收到sigs的logs事件，返回最后一个事件
+func expectLogs(t *testing.T, events <-chan Event, resync bool, sigs ...solana.Signature) Event {
+	t.Helper()
+	var ev Event
+	for _, sig := range sigs {
+		ev = next(t, events)
+		if ev.Type != EventLogs || ev.Logs.Signature != sig || ev.Resync != resync {
+			t.Fatalf("收到 %s，期望交易 %s（补查 %v）", ev, sig, resync)
+		}
+	}
+	return ev
+}
+
+// expectReconnect 收到重连事件
+func expectReconnect(t *testing.T, events <-chan Event) *Gap {
+	t.Helper()
+	ev := next(t, events)
+	if ev.Type != EventReconnect || ev.Gap == nil {
+		t.Fatalf("收到 %s，期望重连事件", ev)
+	}
+	return ev.Gap
+}
+
+func expectNoEvent(t *testing.T, events <-chan Event) {
+	t.Helper()
+	select {
+	case ev := <-events:
+		t.Fatalf("多余的事件: %s", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// disconnect 断开WebSocket，期间发送n笔交易再恢复，返回这些交易的签名
+func (n *testNode) disconnect(t *testing.T, count int) []solana.Signature {
+	t.Helper()
+	n.srv.SetWebSocketDown(true)
+	sigs := make([]solana.Signature, count)
+	for i := range sigs {
+		sigs[i] = n.send(t)
+	}
+	n.srv.SetWebSocketDown(false)
+	return sigs
+}
+
+func TestReconnectBackfill(t *testing.T) {
+	n := newTestNode(t)
+	// 监听之前的交易由快照记住，不推送
+	n.send(t)
+	events := n.run(t, Options{})
+
+	live := expectLogs(t, events, false, n.send(t))
+	// 断线时节点已经前进，缺口要从最后一次通知的slot开始，而不是断线时节点的slot
+	n.srv.Advance(5)
+	missed := n.disconnect(t, 3)
+
+	gap := expectReconnect(t, events)
+	if gap.FromSlot != live.Slot || gap.ToSlot != n.srv.BlockHeight() || len(gap.Truncated) != 0 {
+		t.Fatalf("缺口 %+v，期望从slot %d 到 %d", gap, live.Slot, n.srv.BlockHeight())
+	}
+	// 漏掉的交易按从旧到新补推，断线前推送过的不重复
+	expectLogs(t, events, true, missed...)
+	// 重新订阅后实时通知恢复
+	expectLogs(t, events, false, n.send(t))
+	expectNoEvent(t, events)
+}
+
+func TestReconnectBackfillLimit(t *testing.T) {
+	pageSize := backfillPageSize
+	backfillPageSize = 2
+	defer func() { backfillPageSize = pageSize }()
+
+	tests := []struct {
+		name      string
+		missed    int
+		truncated bool
+	}{
+		{name: "恰好达到上限", missed: 3},
+		{name: "超过上限", missed: 5, truncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestNode(t)
+			events := n.run(t, Options{BackfillLimit: 3})
+			expectLogs(t, events, false, n.send(t))
+			missed := n.disconnect(t, tt.missed)
+
+			gap := expectReconnect(t, events)
+			if truncated := len(gap.Truncated) == 1 && gap.Truncated[0] == n.target; truncated != tt.truncated || len(gap.Truncated) > 1 {
+				t.Fatalf("Truncated = %v，期望标出 %v", gap.Truncated, tt.truncated)
+			}
+			// 超过上限时只补推最新的3笔
+			expectLogs(t, events, true, missed[len(missed)-3:]...)
+			expectNoEvent(t, events)
+		})
+	}
+}